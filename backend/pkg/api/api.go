@@ -48,7 +48,7 @@ func New(cfg *Config) *API {
 		logger.Fatal("failed to create kafka service", zap.Error(err))
 	}
 
-	consoleSvc, err := console.NewService(cfg.Console, logger, kafkaSvc)
+	consoleSvc, err := console.NewService(cfg.Console, logger, kafkaSvc, cfg.MetricsNamespace)
 	if err != nil {
 		logger.Fatal("failed to create owl service", zap.Error(err))
 	}
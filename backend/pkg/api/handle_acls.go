@@ -10,11 +10,15 @@
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/gorilla/schema"
 	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
 
 	"github.com/cloudhut/common/rest"
 	"github.com/cloudhut/kowl/backend/pkg/console"
@@ -145,3 +149,530 @@ func (api *API) handleGetACLsOverview() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
 }
+
+type createACLsRequest struct {
+	ACLs   []console.AclBinding `json:"acls"`
+	DryRun bool                 `json:"dryRun"`
+}
+
+func (c *createACLsRequest) OK() error {
+	if len(c.ACLs) == 0 {
+		return fmt.Errorf("at least one ACL must be specified")
+	}
+	for i, acl := range c.ACLs {
+		_, err := acl.ToCreation()
+		if err != nil {
+			return fmt.Errorf("acl at index '%d' is invalid: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleCreateACLs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req createACLsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to create ACLs
+		isAllowed, restErr := api.Hooks.Console.CanCreateACL(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to create ACLs"),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to create ACLs",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Create ACLs
+		creations := make([]kmsg.CreateACLsRequestCreation, len(req.ACLs))
+		for i, acl := range req.ACLs {
+			creations[i], _ = acl.ToCreation() // Error has been checked in validation function
+		}
+		res, restErr := api.ConsoleSvc.CreateACLs(r.Context(), creations, req.DryRun)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// aclDeleteFilter describes which ACL bindings shall be deleted. Empty properties match any value.
+type aclDeleteFilter struct {
+	ResourceType        string  `json:"resourceType"`
+	ResourceName        *string `json:"resourceName"`
+	ResourcePatternType string  `json:"resourcePatternType"` // ANY, MATCH, LITERAL or PREFIXED
+	Principal           *string `json:"principal"`
+	Host                *string `json:"host"`
+	Operation           string  `json:"operation"`
+	PermissionType      string  `json:"permissionType"`
+}
+
+// ToKmsg parses the filter into a kmsg delete filter and validates it.
+func (a *aclDeleteFilter) ToKmsg() (kmsg.DeleteACLsRequestFilter, error) {
+	filter := kmsg.NewDeleteACLsRequestFilter()
+	filter.ResourceName = a.ResourceName
+	filter.Principal = a.Principal
+	filter.Host = a.Host
+
+	filter.ResourceType = kmsg.ACLResourceTypeAny
+	if a.ResourceType != "" {
+		resourceType, err := kmsg.ParseACLResourceType(a.ResourceType)
+		if err != nil {
+			return filter, fmt.Errorf("resource type '%v' is invalid", a.ResourceType)
+		}
+		filter.ResourceType = resourceType
+	}
+
+	filter.ResourcePatternType = kmsg.ACLResourcePatternTypeAny
+	if a.ResourcePatternType != "" {
+		patternType, err := kmsg.ParseACLResourcePatternType(a.ResourcePatternType)
+		if err != nil {
+			return filter, fmt.Errorf("resource pattern type '%v' is invalid", a.ResourcePatternType)
+		}
+		filter.ResourcePatternType = patternType
+	}
+
+	filter.Operation = kmsg.ACLOperationAny
+	if a.Operation != "" {
+		operation, err := kmsg.ParseACLOperation(a.Operation)
+		if err != nil {
+			return filter, fmt.Errorf("operation '%v' is invalid", a.Operation)
+		}
+		filter.Operation = operation
+	}
+
+	filter.PermissionType = kmsg.ACLPermissionTypeAny
+	if a.PermissionType != "" {
+		permissionType, err := kmsg.ParseACLPermissionType(a.PermissionType)
+		if err != nil {
+			return filter, fmt.Errorf("permission type '%v' is invalid", a.PermissionType)
+		}
+		filter.PermissionType = permissionType
+	}
+
+	return filter, nil
+}
+
+// isNarrowed returns true if the filter is restricted to a principal, resource name or host. Filters without
+// any of these match the ACL bindings of all principals and resources.
+func (a *aclDeleteFilter) isNarrowed() bool {
+	return a.Principal != nil || a.ResourceName != nil || a.Host != nil
+}
+
+type deleteACLsRequest struct {
+	Filters []aclDeleteFilter `json:"filters"`
+	DryRun  bool              `json:"dryRun"`
+
+	// ConfirmDeleteAll must be set to allow filters that are not restricted to a principal, resource name or
+	// host, as these may delete all ACL bindings of the cluster.
+	ConfirmDeleteAll bool `json:"confirmDeleteAll"`
+}
+
+func (d *deleteACLsRequest) OK() error {
+	if len(d.Filters) == 0 {
+		return fmt.Errorf("at least one filter must be specified")
+	}
+	for i, filter := range d.Filters {
+		_, err := filter.ToKmsg()
+		if err != nil {
+			return fmt.Errorf("filter at index '%d' is invalid: %w", i, err)
+		}
+		if !filter.isNarrowed() && !d.ConfirmDeleteAll {
+			return fmt.Errorf("filter at index '%d' must specify a principal, resource name or host, "+
+				"set confirmDeleteAll to delete ACLs without these restrictions", i)
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleDeleteACLs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteACLsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to delete ACLs
+		isAllowed, restErr := api.Hooks.Console.CanDeleteACL(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to delete ACLs"),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to delete ACLs",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Delete ACLs
+		filters := make([]kmsg.DeleteACLsRequestFilter, len(req.Filters))
+		for i, filter := range req.Filters {
+			filters[i], _ = filter.ToKmsg() // Error has been checked in validation function
+		}
+		res, restErr := api.ConsoleSvc.DeleteACLs(r.Context(), filters, req.DryRun)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+type checkACLPermissionRequest struct {
+	Principal    string `schema:"principal"`
+	Host         string `schema:"host"`
+	Operation    string `schema:"operation"`
+	ResourceType string `schema:"resourceType"`
+	ResourceName string `schema:"resourceName"`
+}
+
+// ToPermissionCheck parses and validates the request.
+func (c *checkACLPermissionRequest) ToPermissionCheck() (console.ACLPermissionCheck, error) {
+	check := console.ACLPermissionCheck{
+		Principal:    c.Principal,
+		Host:         c.Host,
+		ResourceName: c.ResourceName,
+	}
+	if check.Principal == "" {
+		return check, fmt.Errorf("principal must be set")
+	}
+	if check.Host == "" {
+		check.Host = "*"
+	}
+	if check.ResourceName == "" {
+		return check, fmt.Errorf("resource name must be set")
+	}
+
+	operation, err := kmsg.ParseACLOperation(c.Operation)
+	if err != nil || operation == kmsg.ACLOperationAny || operation == kmsg.ACLOperationAll {
+		return check, fmt.Errorf("operation '%v' is invalid", c.Operation)
+	}
+	check.Operation = operation
+
+	resourceType, err := kmsg.ParseACLResourceType(c.ResourceType)
+	if err != nil || resourceType == kmsg.ACLResourceTypeAny {
+		return check, fmt.Errorf("resource type '%v' is invalid", c.ResourceType)
+	}
+	check.ResourceType = resourceType
+
+	return check, nil
+}
+
+// handleCheckACLPermission evaluates all ACL bindings to decide whether a principal is allowed to perform
+// an operation on a resource.
+func (api *API) handleCheckACLPermission() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		decoder := schema.NewDecoder()
+		req := &checkACLPermissionRequest{}
+		err := decoder.Decode(req, r.URL.Query())
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  "Failed to parse request parameters",
+				IsSilent: false,
+			})
+			return
+		}
+		check, err := req.ToPermissionCheck()
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to validate request parameters: %v", err.Error()),
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to list ACLs
+		restErr := api.checkCanListACLs(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Evaluate ACLs
+		res, restErr := api.ConsoleSvc.CheckACLPermission(r.Context(), check)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// checkCanListACLs returns a REST error if the requester is not allowed to list ACLs.
+func (api *API) checkCanListACLs(r *http.Request) *rest.Error {
+	isAllowed, restErr := api.Hooks.Console.CanListACLs(r.Context())
+	if restErr != nil {
+		return restErr
+	}
+	if !isAllowed {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to list ACLs"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to list ACLs",
+			IsSilent: true,
+		}
+	}
+
+	return nil
+}
+
+// handleGetACLsByPrincipal returns all ACL bindings grouped by principal.
+func (api *API) handleGetACLsByPrincipal() http.HandlerFunc {
+	type response struct {
+		Principals []*console.AclPrincipal `json:"principals"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		restErr := api.checkCanListACLs(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		var principal *string
+		if p := r.URL.Query().Get("principal"); p != "" {
+			principal = &p
+		}
+		principals, restErr := api.ConsoleSvc.ListACLsByPrincipal(r.Context(), principal)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Principals: principals})
+	}
+}
+
+// handleExportACLs returns all ACL bindings as declarative YAML (default) or JSON document.
+func (api *API) handleExportACLs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "yaml"
+		}
+		if format != "yaml" && format != "json" {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("invalid export format '%v'", format),
+				Status:   http.StatusBadRequest,
+				Message:  "Export format must be either yaml or json",
+				IsSilent: true,
+			})
+			return
+		}
+
+		restErr := api.checkCanListACLs(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		doc, restErr := api.ConsoleSvc.ExportACLs(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		if format == "json" {
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, doc)
+			return
+		}
+
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to marshal ACLs as YAML: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+	}
+}
+
+// maxACLDocumentSize is the maximum size of an ACL document that can be imported.
+const maxACLDocumentSize = 10 * 1024 * 1024
+
+// decodeACLDocument parses the request body as YAML or JSON ACL document. Unknown properties are rejected. A
+// document without any ACLs would delete all ACL bindings, therefore it is only accepted if the query parameter
+// allowDeleteAll is set to true.
+func decodeACLDocument(r *http.Request) (console.AclDocument, *rest.Error) {
+	var doc console.AclDocument
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxACLDocumentSize))
+	if err != nil {
+		return doc, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  "Failed to read request body",
+			IsSilent: true,
+		}
+	}
+
+	// JSON is a subset of YAML, hence we can parse both formats with the YAML decoder
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&doc)
+	if err != nil && err != io.EOF {
+		return doc, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to parse ACL document: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	if len(doc.ACLs) == 0 && r.URL.Query().Get("allowDeleteAll") != "true" {
+		return doc, &rest.Error{
+			Err:      fmt.Errorf("ACL document does not contain any ACLs"),
+			Status:   http.StatusBadRequest,
+			Message:  "The ACL document does not contain any ACLs. Set allowDeleteAll=true to delete all ACLs of the cluster",
+			IsSilent: true,
+		}
+	}
+
+	return doc, nil
+}
+
+// handlePlanACLImport returns the ACL bindings that would be created and deleted by importing the given document.
+func (api *API) handlePlanACLImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, restErr := decodeACLDocument(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		restErr = api.checkCanListACLs(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		plan, restErr := api.ConsoleSvc.PlanACLImport(r.Context(), doc)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
+// handleApplyACLImport creates and deletes all ACL bindings so that the cluster's ACLs match the given document.
+// The planHash of the reviewed plan must be passed, the document is not applied if the planned changes differ.
+func (api *API) handleApplyACLImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse request and calculate plan
+		planHash := r.URL.Query().Get("planHash")
+		if planHash == "" {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("plan hash is not set"),
+				Status:   http.StatusBadRequest,
+				Message:  "The planHash of the reviewed plan must be set",
+				IsSilent: true,
+			})
+			return
+		}
+		doc, restErr := decodeACLDocument(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		restErr = api.checkCanListACLs(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		plan, restErr := api.ConsoleSvc.PlanACLImport(r.Context(), doc)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if plan.PlanHash != planHash {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("plan hash '%v' does not match the current plan's hash '%v'", planHash, plan.PlanHash),
+				Status:   http.StatusConflict,
+				Message:  "The ACLs to create or delete have changed since the plan has been reviewed, please plan again",
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to create and delete the planned ACLs
+		if len(plan.ToCreate) > 0 {
+			isAllowed, restErr := api.Hooks.Console.CanCreateACL(r.Context())
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !isAllowed {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("requester is not allowed to create ACLs"),
+					Status:   http.StatusForbidden,
+					Message:  "You are not allowed to create ACLs",
+					IsSilent: false,
+				})
+				return
+			}
+		}
+		if len(plan.ToDelete) > 0 {
+			isAllowed, restErr := api.Hooks.Console.CanDeleteACL(r.Context())
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !isAllowed {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("requester is not allowed to delete ACLs"),
+					Status:   http.StatusForbidden,
+					Message:  "You are not allowed to delete ACLs",
+					IsSilent: false,
+				})
+				return
+			}
+		}
+
+		// 3. Apply plan
+		res, restErr := api.ConsoleSvc.ApplyACLImportPlan(r.Context(), plan)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
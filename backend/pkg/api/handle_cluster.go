@@ -40,3 +40,46 @@ func (api *API) handleDescribeCluster() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, response)
 	}
 }
+
+// handleGetClusterHealth returns all under replicated, under min ISR, offline and leaderless partitions along with
+// a per broker summary.
+func (api *API) handleGetClusterHealth() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		health, restErr := api.ConsoleSvc.GetClusterHealth(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Only include partitions of topics the user is allowed to see
+		canSeeByTopic := make(map[string]bool)
+		filterPartitions := func(partitions []console.ClusterHealthPartition) ([]console.ClusterHealthPartition, *rest.Error) {
+			visiblePartitions := make([]console.ClusterHealthPartition, 0, len(partitions))
+			for _, partition := range partitions {
+				canSee, exists := canSeeByTopic[partition.TopicName]
+				if !exists {
+					var restErr *rest.Error
+					canSee, restErr = api.Hooks.Console.CanSeeTopic(r.Context(), partition.TopicName)
+					if restErr != nil {
+						return nil, restErr
+					}
+					canSeeByTopic[partition.TopicName] = canSee
+				}
+				if canSee {
+					visiblePartitions = append(visiblePartitions, partition)
+				}
+			}
+			return visiblePartitions, nil
+		}
+		for _, partitions := range []*[]console.ClusterHealthPartition{&health.UnderReplicatedPartitions,
+			&health.UnderMinISRPartitions, &health.OfflinePartitions, &health.LeaderlessPartitions} {
+			*partitions, restErr = filterPartitions(*partitions)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, health)
+	}
+}
@@ -12,6 +12,8 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -190,6 +192,74 @@ func (api *API) handlePatchConsumerGroup() http.HandlerFunc {
 	}
 }
 
+type resetConsumerGroupOffsetsRequest struct {
+	console.ResetConsumerGroupOffsetsRequest
+}
+
+func (r *resetConsumerGroupOffsetsRequest) OK() error {
+	return r.Validate()
+}
+
+// handleResetConsumerGroupOffsets resets the offsets of a consumer group using one of the server-side reset
+// strategies (e.g. to a timestamp or to another group's offsets). Dry runs return the computed offsets and
+// resulting lags without committing them.
+func (api *API) handleResetConsumerGroupOffsets() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		groupID := chi.URLParam(r, "groupId")
+		var req resetConsumerGroupOffsetsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to edit Consumer Group and, when copying offsets from another
+		// group, whether the user is allowed to see the source group.
+		canEdit, restErr := api.Hooks.Console.CanEditConsumerGroup(r.Context(), groupID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canEdit {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to edit consumer group"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to edit this consumer group",
+				InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+				IsSilent:     false,
+			})
+			return
+		}
+		if req.Strategy == console.ResetOffsetsStrategyToGroup {
+			canSee, restErr := api.Hooks.Console.CanSeeConsumerGroup(r.Context(), req.SourceGroupID)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canSee {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:          fmt.Errorf("requester has no permissions to view source consumer group"),
+					Status:       http.StatusForbidden,
+					Message:      "You don't have permissions to view the source consumer group",
+					InternalLogs: []zapcore.Field{zap.String("source_group_id", req.SourceGroupID)},
+					IsSilent:     false,
+				})
+				return
+			}
+		}
+
+		// 3. Compute and (unless it's a dry run) commit the new offsets
+		res, restErr := api.ConsoleSvc.ResetConsumerGroupOffsets(r.Context(), groupID, req.ResetConsumerGroupOffsetsRequest)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
 type deleteConsumerGroupRequest struct {
 	GroupID string `json:"groupId"`
 	Topics  []struct {
@@ -278,3 +348,222 @@ func (api *API) handleDeleteConsumerGroupOffsets() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
 }
+
+type deleteConsumerGroupsRequest struct {
+	// GroupIDs is a list of consumer groups that shall be deleted.
+	GroupIDs []string `json:"groupIds"`
+
+	// GroupIDPattern is a regex. All consumer groups whose ID match this regex will be deleted.
+	GroupIDPattern string `json:"groupIdPattern"`
+
+	// DryRun reports which groups would be deleted without deleting them.
+	DryRun bool `json:"dryRun"`
+}
+
+func (d *deleteConsumerGroupsRequest) OK() error {
+	if len(d.GroupIDs) == 0 && d.GroupIDPattern == "" {
+		return fmt.Errorf("either a list of group ids or a group id pattern must be set")
+	}
+	if len(d.GroupIDs) > 0 && d.GroupIDPattern != "" {
+		return fmt.Errorf("either a list of group ids or a group id pattern must be set, but not both")
+	}
+	seen := make(map[string]struct{}, len(d.GroupIDs))
+	for _, groupID := range d.GroupIDs {
+		if groupID == "" {
+			return fmt.Errorf("group ids must not be empty")
+		}
+		if _, exists := seen[groupID]; exists {
+			return fmt.Errorf("group id '%v' is specified more than once", groupID)
+		}
+		seen[groupID] = struct{}{}
+	}
+
+	return nil
+}
+
+// handleDeleteConsumerGroups deletes one or more entire consumer groups, selected by a list of group ids or by a
+// regex. Groups with active members or groups the requester is not allowed to delete are reported with a
+// per-group error. A dry run reports the matched groups without deleting them.
+func (api *API) handleDeleteConsumerGroups() http.HandlerFunc {
+	type response struct {
+		Groups []console.DeleteConsumerGroupResult `json:"groups"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteConsumerGroupsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Resolve group ids. Groups which the requester is not allowed to see are not matched by the pattern.
+		groupIDs := req.GroupIDs
+		if req.GroupIDPattern != "" {
+			matchedGroupIDs, restErr := api.ConsoleSvc.ListConsumerGroupIDsByPattern(r.Context(), req.GroupIDPattern)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			groupIDs = make([]string, 0, len(matchedGroupIDs))
+			for _, groupID := range matchedGroupIDs {
+				canSee, restErr := api.Hooks.Console.CanSeeConsumerGroup(r.Context(), groupID)
+				if restErr != nil {
+					rest.SendRESTError(w, r, api.Logger, restErr)
+					return
+				}
+				if canSee {
+					groupIDs = append(groupIDs, groupID)
+				}
+			}
+		}
+
+		// 3. Check if logged in user is allowed to delete each Consumer Group
+		results := make([]console.DeleteConsumerGroupResult, 0, len(groupIDs))
+		deletableGroupIDs := make([]string, 0, len(groupIDs))
+		for _, groupID := range groupIDs {
+			canDelete, restErr := api.Hooks.Console.CanDeleteConsumerGroup(r.Context(), groupID)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canDelete {
+				results = append(results, console.DeleteConsumerGroupResult{
+					GroupID: groupID,
+					Error:   "You don't have permissions to delete this consumer group",
+				})
+				continue
+			}
+			deletableGroupIDs = append(deletableGroupIDs, groupID)
+		}
+
+		// 4. Delete groups
+		deleted, restErr := api.ConsoleSvc.DeleteConsumerGroups(r.Context(), deletableGroupIDs, req.DryRun)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		results = append(results, deleted...)
+		sort.Slice(results, func(i, j int) bool { return results[i].GroupID < results[j].GroupID })
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Groups: results})
+	}
+}
+
+// handleGetConsumerGroupLagHistory returns the recorded lag of a consumer group over time, along with the
+// estimated time until the group has caught up.
+func (api *API) handleGetConsumerGroupLagHistory() http.HandlerFunc {
+	type response struct {
+		IsEnabled  bool                             `json:"isEnabled"`
+		LagHistory *console.ConsumerGroupLagHistory `json:"lagHistory,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		groupID := chi.URLParam(r, "groupId")
+		topicName := r.URL.Query().Get("topicName")
+		var window time.Duration
+		if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+			var err error
+			window, err = time.ParseDuration(windowStr)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("failed to parse window '%v': %w", windowStr, err),
+					Status:   http.StatusBadRequest,
+					Message:  "Window must be a positive duration such as '15m'",
+					IsSilent: true,
+				})
+				return
+			}
+			if window < 0 {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("window '%v' is negative", windowStr),
+					Status:   http.StatusBadRequest,
+					Message:  "Window must be a positive duration such as '15m'",
+					IsSilent: true,
+				})
+				return
+			}
+		}
+
+		// 2. Check if logged in user is allowed to see the Consumer Group
+		canSee, restErr := api.Hooks.Console.CanSeeConsumerGroup(r.Context(), groupID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canSee {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to view consumer group"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to view this consumer group",
+				InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+				IsSilent:     false,
+			})
+			return
+		}
+
+		// 3. Get lag history
+		history, err := api.ConsoleSvc.GetConsumerGroupLagHistory(groupID, topicName, window)
+		if err != nil {
+			if err == console.ErrLagHistoryNotEnabled {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsEnabled: false})
+				return
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to get consumer group lag history: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsEnabled: true, LagHistory: history})
+	}
+}
+
+// handleGetConsumerGroupEvents returns the recorded membership and state change events of a consumer group.
+func (api *API) handleGetConsumerGroupEvents() http.HandlerFunc {
+	type response struct {
+		IsEnabled bool                           `json:"isEnabled"`
+		EventLog  *console.ConsumerGroupEventLog `json:"eventLog,omitempty"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupID := chi.URLParam(r, "groupId")
+
+		// 1. Check if logged in user is allowed to see the Consumer Group
+		canSee, restErr := api.Hooks.Console.CanSeeConsumerGroup(r.Context(), groupID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canSee {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to view consumer group"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to view this consumer group",
+				InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+				IsSilent:     false,
+			})
+			return
+		}
+
+		// 2. Get event log
+		eventLog, err := api.ConsoleSvc.GetConsumerGroupEvents(groupID)
+		if err != nil {
+			if err == console.ErrGroupEventsNotEnabled {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsEnabled: false})
+				return
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to get consumer group events: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsEnabled: true, EventLog: eventLog})
+	}
+}
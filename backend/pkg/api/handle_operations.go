@@ -12,6 +12,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cloudhut/common/rest"
@@ -116,6 +117,10 @@ type patchPartitionsRequest struct {
 			Replicas []int32 `json:"replicas"`
 		} `json:"partitions"`
 	} `json:"topics"`
+
+	// ThrottleRate is the replication throttle in bytes per second that shall be applied to the moved replicas.
+	// No throttle is set if it is 0. The throttle is removed automatically once the reassignment is completed.
+	ThrottleRate int64 `json:"throttleRate"`
 }
 
 func (p *patchPartitionsRequest) OK() error {
@@ -127,6 +132,9 @@ func (p *patchPartitionsRequest) OK() error {
 			return fmt.Errorf("topic '%v' has no partitions set whose assignments shall be altered", topic.TopicName)
 		}
 	}
+	if p.ThrottleRate < 0 {
+		return fmt.Errorf("throttle rate must not be negative")
+	}
 
 	return nil
 }
@@ -134,6 +142,7 @@ func (p *patchPartitionsRequest) OK() error {
 func (api *API) handlePatchPartitionAssignments() http.HandlerFunc {
 	type response struct {
 		ReassignPartitionsResponse []console.AlterPartitionReassignmentsResponse `json:"reassignPartitionsResponses"`
+		Throttle                   *console.ReassignmentThrottle                 `json:"throttle,omitempty"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -161,7 +170,7 @@ func (api *API) handlePatchPartitionAssignments() http.HandlerFunc {
 			return
 		}
 
-		// 3. Submit reassign partitions request
+		// 3. Build reassign partitions request
 		kmsgReq := make([]kmsg.AlterPartitionAssignmentsRequestTopic, len(req.Topics))
 		for i, topic := range req.Topics {
 			partitions := make([]kmsg.AlterPartitionAssignmentsRequestTopicPartition, len(topic.Partitions))
@@ -178,8 +187,20 @@ func (api *API) handlePatchPartitionAssignments() http.HandlerFunc {
 			kmsgReq[i] = topicReq
 		}
 
-		// 4. Check response and pass it to the frontend
+		// 4. Set replication throttle for all replicas that will be moved
+		var throttle *console.ReassignmentThrottle
+		if req.ThrottleRate > 0 {
+			throttle, restErr = api.ConsoleSvc.ThrottlePartitionReassignments(r.Context(), kmsgReq, req.ThrottleRate)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+		}
+
+		// 5. Check response and pass it to the frontend. The throttle is kept until the reassignments have been
+		// submitted, afterwards it's removed as soon as they are completed.
 		owlRes, err := api.ConsoleSvc.AlterPartitionAssignments(r.Context(), kmsgReq)
+		api.ConsoleSvc.ReassignmentsSubmitted(throttle)
 		if err != nil {
 			restErr := &rest.Error{
 				Err:      err,
@@ -191,11 +212,56 @@ func (api *API) handlePatchPartitionAssignments() http.HandlerFunc {
 			return
 		}
 
-		res := response{ReassignPartitionsResponse: owlRes}
+		res := response{ReassignPartitionsResponse: owlRes, Throttle: throttle}
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
 }
 
+type planPartitionReassignmentsRequest struct {
+	console.PlanPartitionReassignmentsRequest
+}
+
+func (p *planPartitionReassignmentsRequest) OK() error {
+	return p.Validate()
+}
+
+func (api *API) handlePlanPartitionReassignments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req planPartitionReassignmentsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to reassign partitions
+		isAllowed, restErr := api.Hooks.Console.CanPatchPartitionReassignments(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to patch partition assignments"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to reassign partitions",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Compute the plan, it will not be applied
+		plan, restErr := api.ConsoleSvc.PlanPartitionReassignments(r.Context(), req.PlanPartitionReassignmentsRequest)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
 type patchConfigsRequest struct {
 	// Resources contains all resources that shall be altered
 	Resources []patchConfigsRequestResource `json:"resources"`
@@ -338,3 +404,252 @@ func (api *API) handlePatchConfigs() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
 }
+
+type electLeadersRequest struct {
+	// ElectionType is either "preferred" or "unclean".
+	ElectionType string `json:"electionType"`
+
+	// Topics whose partition leaders shall be elected.
+	Topics []console.ElectLeadersTopic `json:"topics"`
+
+	// AllTopics must be set to elect the preferred leaders of all partitions in the cluster. It can not be
+	// combined with topics or with unclean elections.
+	AllTopics bool `json:"allTopics"`
+}
+
+func (e *electLeadersRequest) OK() error {
+	if e.ElectionType != console.ElectionTypePreferred && e.ElectionType != console.ElectionTypeUnclean {
+		return fmt.Errorf("election type must be either '%v' or '%v'", console.ElectionTypePreferred, console.ElectionTypeUnclean)
+	}
+	if e.AllTopics && len(e.Topics) > 0 {
+		return fmt.Errorf("either topics or allTopics must be set, but not both")
+	}
+	if !e.AllTopics && len(e.Topics) == 0 {
+		return fmt.Errorf("at least one topic must be set, set allTopics to elect leaders for all partitions in the cluster")
+	}
+	if e.AllTopics && e.ElectionType == console.ElectionTypeUnclean {
+		return fmt.Errorf("unclean leader elections can not be triggered for all topics, because they may cause data loss")
+	}
+	for _, topic := range e.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleElectLeaders() http.HandlerFunc {
+	type response struct {
+		Partitions []console.ElectLeadersPartitionResult `json:"partitions"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req electLeadersRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to elect leaders
+		isAllowed, restErr := api.Hooks.Console.CanElectLeaders(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to elect leaders"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to elect leaders",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Check if logged in user is allowed to elect leaders for each affected topic
+		topicNames := make([]string, len(req.Topics))
+		for i, topic := range req.Topics {
+			topicNames[i] = topic.TopicName
+		}
+		if req.AllTopics {
+			var err error
+			topicNames, err = api.ConsoleSvc.GetAllTopicNames(r.Context(), nil)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      err,
+					Status:   http.StatusServiceUnavailable,
+					Message:  fmt.Sprintf("Failed to list topics: %v", err.Error()),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+		for _, topicName := range topicNames {
+			canElect, restErr := api.Hooks.Console.CanElectTopicLeaders(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canElect {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("requester has no permissions to elect leaders of topic '%v'", topicName),
+					Status:   http.StatusForbidden,
+					Message:  fmt.Sprintf("You don't have permissions to elect leaders of topic '%v'", topicName),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+
+		// 4. Trigger leader election
+		partitions, restErr := api.ConsoleSvc.ElectLeaders(r.Context(), req.ElectionType, req.Topics)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Partitions: partitions})
+	}
+}
+
+func (api *API) handleGetNonPreferredLeaders() http.HandlerFunc {
+	type response struct {
+		Partitions []console.NonPreferredLeaderPartition `json:"partitions"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		partitions, restErr := api.ConsoleSvc.GetNonPreferredLeaders(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Only include partitions of topics the user is allowed to see
+		canSeeByTopic := make(map[string]bool)
+		visiblePartitions := make([]console.NonPreferredLeaderPartition, 0, len(partitions))
+		for _, partition := range partitions {
+			canSee, exists := canSeeByTopic[partition.TopicName]
+			if !exists {
+				canSee, restErr = api.Hooks.Console.CanSeeTopic(r.Context(), partition.TopicName)
+				if restErr != nil {
+					rest.SendRESTError(w, r, api.Logger, restErr)
+					return
+				}
+				canSeeByTopic[partition.TopicName] = canSee
+			}
+			if canSee {
+				visiblePartitions = append(visiblePartitions, partition)
+			}
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Partitions: visiblePartitions})
+	}
+}
+
+// defaultStaleTopicInactiveDays is the number of days without new messages after which a topic is reported as
+// stale, unless the inactiveDays query parameter is set.
+const defaultStaleTopicInactiveDays = 7
+
+// handleGetStaleTopics returns all topics that are empty, have never been consumed, had no writes within the
+// last inactiveDays or have consumer groups without active members.
+func (api *API) handleGetStaleTopics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inactiveDays := defaultStaleTopicInactiveDays
+		if inactiveDaysStr := r.URL.Query().Get("inactiveDays"); inactiveDaysStr != "" {
+			parsed, err := strconv.Atoi(inactiveDaysStr)
+			if err != nil || parsed < 1 {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("failed to parse inactive days: '%v'", inactiveDaysStr),
+					Status:   http.StatusBadRequest,
+					Message:  "Inactive days must be a positive number",
+					IsSilent: true,
+				})
+				return
+			}
+			inactiveDays = parsed
+		}
+
+		report, restErr := api.ConsoleSvc.GetStaleTopics(r.Context(), inactiveDays)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Only include topics the user is allowed to see
+		visibleTopics := make([]console.StaleTopic, 0, len(report.Topics))
+		for _, topic := range report.Topics {
+			canSee, restErr := api.Hooks.Console.CanSeeTopic(r.Context(), topic.TopicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if canSee {
+				visibleTopics = append(visibleTopics, topic)
+			}
+		}
+		report.Topics = visibleTopics
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, report)
+	}
+}
+
+// handleGetTopicConfigViolations checks the configs of all topics against the configured topic config policy and
+// returns the violations along with suggested patches.
+func (api *API) handleGetTopicConfigViolations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, restErr := api.ConsoleSvc.GetTopicConfigPolicyReport(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Only include topics whose configs the user is allowed to view. Patches are only suggested for topics
+		// with violations.
+		topicNames := make([]string, 0, len(report.Violations)+len(report.Errors))
+		for _, violation := range report.Violations {
+			topicNames = append(topicNames, violation.TopicName)
+		}
+		for _, topicErr := range report.Errors {
+			topicNames = append(topicNames, topicErr.TopicName)
+		}
+		canViewByTopic := make(map[string]bool)
+		for _, topicName := range topicNames {
+			if _, exists := canViewByTopic[topicName]; exists {
+				continue
+			}
+			canView, restErr := api.Hooks.Console.CanViewTopicConfig(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			canViewByTopic[topicName] = canView
+		}
+
+		visibleViolations := make([]console.TopicConfigViolation, 0, len(report.Violations))
+		for _, violation := range report.Violations {
+			if canViewByTopic[violation.TopicName] {
+				visibleViolations = append(visibleViolations, violation)
+			}
+		}
+		visiblePatches := make([]console.TopicConfigPatch, 0, len(report.Patches))
+		for _, patch := range report.Patches {
+			if canViewByTopic[patch.ResourceName] {
+				visiblePatches = append(visiblePatches, patch)
+			}
+		}
+		visibleErrors := make([]console.TopicConfigPolicyError, 0, len(report.Errors))
+		for _, topicErr := range report.Errors {
+			if canViewByTopic[topicErr.TopicName] {
+				visibleErrors = append(visibleErrors, topicErr)
+			}
+		}
+		report.Violations = visibleViolations
+		report.Patches = visiblePatches
+		report.Errors = visibleErrors
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, report)
+	}
+}
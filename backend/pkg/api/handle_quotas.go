@@ -14,6 +14,8 @@ import (
 	"net/http"
 
 	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/console"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func (api *API) handleGetQuotas() http.HandlerFunc {
@@ -21,21 +23,243 @@ func (api *API) handleGetQuotas() http.HandlerFunc {
 		quotas := api.ConsoleSvc.DescribeQuotas(r.Context())
 
 		// Check if logged in user is allowed to list Quotas
-		isAllowed, restErr := api.Hooks.Console.CanListQuotas(r.Context())
+		restErr := api.checkCanListQuotas(r)
 		if restErr != nil {
 			rest.SendRESTError(w, r, api.Logger, restErr)
 			return
 		}
-		if !isAllowed {
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, quotas)
+	}
+}
+
+type quotaSetting struct {
+	Key   string  `json:"key"`
+	Value float64 `json:"value"`
+}
+
+type putQuotasRequest struct {
+	Entries []struct {
+		Entity   []console.QuotaEntityComponent `json:"entity"`
+		Settings []quotaSetting                 `json:"settings"`
+	} `json:"entries"`
+	ValidateOnly bool `json:"validateOnly"`
+}
+
+func (p *putQuotasRequest) OK() error {
+	if len(p.Entries) == 0 {
+		return fmt.Errorf("at least one entry must be specified")
+	}
+	for i, entry := range p.Entries {
+		err := console.ValidateQuotaEntity(entry.Entity)
+		if err != nil {
+			return fmt.Errorf("entry at index '%d' is invalid: %w", i, err)
+		}
+		if len(entry.Settings) == 0 {
+			return fmt.Errorf("entry at index '%d' must set at least one quota", i)
+		}
+		for _, setting := range entry.Settings {
+			err := console.ValidateQuotaKey(entry.Entity, setting.Key)
+			if err != nil {
+				return fmt.Errorf("entry at index '%d' is invalid: %w", i, err)
+			}
+			if setting.Value <= 0 {
+				return fmt.Errorf("entry at index '%d' is invalid: value for quota '%v' must be positive", i, setting.Key)
+			}
+		}
+	}
+
+	return nil
+}
+
+type deleteQuotasRequest struct {
+	Entries []struct {
+		Entity []console.QuotaEntityComponent `json:"entity"`
+		Keys   []string                       `json:"keys"`
+	} `json:"entries"`
+	ValidateOnly bool `json:"validateOnly"`
+}
+
+func (d *deleteQuotasRequest) OK() error {
+	if len(d.Entries) == 0 {
+		return fmt.Errorf("at least one entry must be specified")
+	}
+	for i, entry := range d.Entries {
+		err := console.ValidateQuotaEntity(entry.Entity)
+		if err != nil {
+			return fmt.Errorf("entry at index '%d' is invalid: %w", i, err)
+		}
+		if len(entry.Keys) == 0 {
+			return fmt.Errorf("entry at index '%d' must specify at least one quota key", i)
+		}
+		for _, key := range entry.Keys {
+			err := console.ValidateQuotaKey(entry.Entity, key)
+			if err != nil {
+				return fmt.Errorf("entry at index '%d' is invalid: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func newAlterClientQuotasEntity(entity []console.QuotaEntityComponent) []kmsg.AlterClientQuotasRequestEntryEntity {
+	components := make([]kmsg.AlterClientQuotasRequestEntryEntity, len(entity))
+	for i, component := range entity {
+		c := kmsg.NewAlterClientQuotasRequestEntryEntity()
+		c.Type = component.Type
+		c.Name = component.Name
+		components[i] = c
+	}
+	return components
+}
+
+// checkCanListQuotas returns a REST error if the requester is not allowed to list quotas.
+func (api *API) checkCanListQuotas(r *http.Request) *rest.Error {
+	isAllowed, restErr := api.Hooks.Console.CanListQuotas(r.Context())
+	if restErr != nil {
+		return restErr
+	}
+	if !isAllowed {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to list quotas"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to list quotas",
+			IsSilent: true,
+		}
+	}
+
+	return nil
+}
+
+// checkCanAlterQuotas returns a REST error if the requester is not allowed to alter quotas.
+func (api *API) checkCanAlterQuotas(r *http.Request) *rest.Error {
+	isAllowed, restErr := api.Hooks.Console.CanAlterQuotas(r.Context())
+	if restErr != nil {
+		return restErr
+	}
+	if !isAllowed {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to alter quotas"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to alter quotas",
+			IsSilent: false,
+		}
+	}
+
+	return nil
+}
+
+// handlePutQuotas sets the given client quotas.
+func (api *API) handlePutQuotas() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req putQuotasRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to alter quotas
+		restErr = api.checkCanAlterQuotas(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Set quotas
+		entries := make([]kmsg.AlterClientQuotasRequestEntry, len(req.Entries))
+		for i, entry := range req.Entries {
+			e := kmsg.NewAlterClientQuotasRequestEntry()
+			e.Entity = newAlterClientQuotasEntity(entry.Entity)
+			for _, setting := range entry.Settings {
+				op := kmsg.NewAlterClientQuotasRequestEntryOp()
+				op.Key = setting.Key
+				op.Value = setting.Value
+				e.Ops = append(e.Ops, op)
+			}
+			entries[i] = e
+		}
+		res, restErr := api.ConsoleSvc.AlterQuotas(r.Context(), entries, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// handleDeleteQuotas removes the given client quotas.
+func (api *API) handleDeleteQuotas() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteQuotasRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to alter quotas
+		restErr = api.checkCanAlterQuotas(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Remove quotas
+		entries := make([]kmsg.AlterClientQuotasRequestEntry, len(req.Entries))
+		for i, entry := range req.Entries {
+			e := kmsg.NewAlterClientQuotasRequestEntry()
+			e.Entity = newAlterClientQuotasEntity(entry.Entity)
+			for _, key := range entry.Keys {
+				op := kmsg.NewAlterClientQuotasRequestEntryOp()
+				op.Key = key
+				op.Remove = true
+				e.Ops = append(e.Ops, op)
+			}
+			entries[i] = e
+		}
+		res, restErr := api.ConsoleSvc.AlterQuotas(r.Context(), entries, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// handleGetEffectiveQuota resolves the quotas that apply to the given user and client id.
+func (api *API) handleGetEffectiveQuota() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.URL.Query().Get("user")
+		clientID := r.URL.Query().Get("clientId")
+		if user == "" && clientID == "" {
 			rest.SendRESTError(w, r, api.Logger, &rest.Error{
-				Err:      fmt.Errorf("requester is not allowed to list Quotas"),
-				Status:   http.StatusForbidden,
-				Message:  "You are not allowed to list ACLs",
+				Err:      fmt.Errorf("neither user nor client id has been specified"),
+				Status:   http.StatusBadRequest,
+				Message:  "At least one of user or clientId must be specified",
 				IsSilent: true,
 			})
 			return
 		}
 
-		rest.SendResponse(w, r, api.Logger, http.StatusOK, quotas)
+		// Check if logged in user is allowed to list Quotas
+		restErr := api.checkCanListQuotas(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		res, restErr := api.ConsoleSvc.ResolveEffectiveQuota(r.Context(), user, clientID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
 }
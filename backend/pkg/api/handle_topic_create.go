@@ -14,6 +14,8 @@ import (
 	"net/http"
 
 	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/console"
+	"github.com/go-chi/chi"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -23,6 +25,10 @@ type createTopicRequest struct {
 	PartitionCount    int32                      `json:"partitionCount"`
 	ReplicationFactor int16                      `json:"replicationFactor"`
 	Configs           []createTopicRequestConfig `json:"configs"`
+
+	// Template is the name of a configured topic template. Partition count, replication factor and configs that
+	// are not set in the request are taken from the template.
+	Template string `json:"template"`
 }
 
 // OK validates the individual fields.
@@ -35,13 +41,16 @@ func (c *createTopicRequest) OK() error {
 	}
 
 	// Value -1 means that the partition count shall be inherited from the defaults (supported in req v4+).
-	isValidPartitionCount := c.PartitionCount == -1 || c.PartitionCount >= 1
+	// Value 0 means that the partition count shall be inherited from the template.
+	isInheritedFromTemplate := c.Template != "" && c.PartitionCount == 0
+	isValidPartitionCount := c.PartitionCount == -1 || c.PartitionCount >= 1 || isInheritedFromTemplate
 	if !isValidPartitionCount {
 		return fmt.Errorf("you must create a topic with at least one partition")
 	}
 
 	// Value -1 means that the replication factor shall be inherited from the defaults (supported in req v4+).
-	isValidReplicationFactor := c.ReplicationFactor == -1 || c.ReplicationFactor >= 1
+	isInheritedFromTemplate = c.Template != "" && c.ReplicationFactor == 0
+	isValidReplicationFactor := c.ReplicationFactor == -1 || c.ReplicationFactor >= 1 || isInheritedFromTemplate
 	if !isValidReplicationFactor {
 		return fmt.Errorf("replication factor must be 1 or more")
 	}
@@ -116,8 +125,111 @@ func (api *API) handleCreateTopic() http.HandlerFunc {
 			return
 		}
 
-		// 3. Try to create topic
-		createTopicResponse, restErr := api.ConsoleSvc.CreateTopic(r.Context(), req.ToKmsg())
+		// 3. Apply topic template if requested
+		createTopicReq := req.ToKmsg()
+		if req.Template != "" {
+			createTopicReq, restErr = api.ConsoleSvc.ApplyTopicTemplate(req.Template, createTopicReq)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+		}
+
+		// 4. Try to create topic
+		createTopicResponse, restErr := api.ConsoleSvc.CreateTopic(r.Context(), createTopicReq)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, createTopicResponse)
+	}
+}
+
+func (api *API) handleGetTopicTemplates() http.HandlerFunc {
+	type response struct {
+		Templates []console.ConfigTopicTemplate `json:"templates"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Templates: api.ConsoleSvc.GetTopicTemplates()})
+	}
+}
+
+// cloneTopicRequest defines the expected JSON body to clone a topic.
+type cloneTopicRequest struct {
+	console.CloneTopicRequest
+}
+
+// OK validates the individual fields.
+func (c *cloneTopicRequest) OK() error {
+	if c.TopicName == "" {
+		return fmt.Errorf("topic name must be set")
+	}
+	if !isValidKafkaTopicName(c.TopicName) {
+		return fmt.Errorf("valid characters for Kafka topics are the ASCII alphanumeric characters and '.', '_', '-'")
+	}
+	if c.PartitionCount != nil && *c.PartitionCount < 1 {
+		return fmt.Errorf("you must create a topic with at least one partition")
+	}
+	if c.ReplicationFactor != nil && *c.ReplicationFactor < 1 {
+		return fmt.Errorf("replication factor must be 1 or more")
+	}
+	for _, cfg := range c.Configs {
+		if cfg.Name == "" {
+			return fmt.Errorf("a config name must be set")
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleCloneTopic() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sourceTopicName := chi.URLParam(r, "topicName")
+
+		// 1. Parse and validate request
+		var req cloneTopicRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to view the source topic's config and to create the new topic
+		canViewConfig, restErr := api.Hooks.Console.CanViewTopicConfig(r.Context(), sourceTopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canViewConfig {
+			restErr := &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view the config of the source topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view the config of the topic that shall be cloned.",
+				IsSilent: false,
+			}
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		canCreate, restErr := api.Hooks.Console.CanCreateTopic(r.Context(), req.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canCreate {
+			restErr := &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to create this topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to create this topic.",
+				IsSilent: false,
+			}
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Try to clone topic
+		createTopicResponse, restErr := api.ConsoleSvc.CloneTopic(r.Context(), sourceTopicName, req.CloneTopicRequest)
 		if restErr != nil {
 			rest.SendRESTError(w, r, api.Logger, restErr)
 			return
@@ -0,0 +1,266 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/cloudhut/kowl/backend/pkg/console"
+)
+
+// maxTopicDocumentSize is the maximum size of a topic document that can be imported.
+const maxTopicDocumentSize = 10 * 1024 * 1024
+
+// decodeTopicDocument parses the request body as YAML or JSON topic document. If the query parameter source is
+// set to "git" the document is read from the configured git repository instead.
+func (api *API) decodeTopicDocument(r *http.Request) (console.TopicDocument, *rest.Error) {
+	if r.URL.Query().Get("source") == "git" {
+		doc, restErr := api.ConsoleSvc.GetTopicDocumentFromGit()
+		if restErr != nil {
+			return console.TopicDocument{}, restErr
+		}
+		return *doc, nil
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxTopicDocumentSize))
+	if err != nil {
+		return console.TopicDocument{}, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  "Failed to read request body",
+			IsSilent: true,
+		}
+	}
+
+	doc, err := console.ParseTopicDocument(body)
+	if err != nil {
+		return doc, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to parse topic document: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	return doc, nil
+}
+
+// planTopicImport decodes the topic document and calculates the plan to import it.
+func (api *API) planTopicImport(r *http.Request) (*console.TopicImportPlan, *rest.Error) {
+	doc, restErr := api.decodeTopicDocument(r)
+	if restErr != nil {
+		return nil, restErr
+	}
+	deleteUnmanaged := r.URL.Query().Get("deleteUnmanaged") == "true"
+
+	return api.ConsoleSvc.PlanTopicImport(r.Context(), doc, deleteUnmanaged)
+}
+
+// handlePlanTopicImport returns the topics that would be created, altered and deleted by importing the given
+// document.
+func (api *API) handlePlanTopicImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plan, restErr := api.planTopicImport(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Only include topics the user is allowed to see and configs the user is allowed to view
+		restErr = api.filterTopicImportPlan(r.Context(), plan)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// The token is issued for the filtered plan, hence a plan that would delete hidden topics can't be applied
+		restErr = api.ConsoleSvc.AddTopicImportConfirmation(plan)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
+// handleApplyTopicImport creates, alters and deletes topics so that the cluster's topics match the given document.
+func (api *API) handleApplyTopicImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse request and calculate plan
+		plan, restErr := api.planTopicImport(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if len(plan.Errors) > 0 {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("topic import plan contains %d errors", len(plan.Errors)),
+				Status:   http.StatusUnprocessableEntity,
+				Message:  fmt.Sprintf("The topic document can not be applied: %v", plan.Errors[0].Error),
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to run all planned actions
+		restErr = api.checkTopicImportPermissions(r.Context(), plan)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Topics are only deleted if the plan that has been shown to the user deleted exactly the same topics
+		restErr = api.ConsoleSvc.ConfirmTopicImportDeletions(plan, r.URL.Query().Get("confirmationToken"))
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 4. Apply plan
+		res := api.ConsoleSvc.ApplyTopicImportPlan(r.Context(), plan)
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// filterTopicImportPlan removes all planned actions of topics which the user is not allowed to see. Config changes
+// are only included if the user is allowed to view the topic's configs.
+func (api *API) filterTopicImportPlan(ctx context.Context, plan *console.TopicImportPlan) *rest.Error {
+	visible := make(map[string]bool)
+	canSee := func(topicName string) (bool, *rest.Error) {
+		if isVisible, exists := visible[topicName]; exists {
+			return isVisible, nil
+		}
+		isVisible, restErr := api.Hooks.Console.CanSeeTopic(ctx, topicName)
+		if restErr != nil {
+			return false, restErr
+		}
+		visible[topicName] = isVisible
+		return isVisible, nil
+	}
+
+	toCreate := make([]console.DesiredTopic, 0, len(plan.ToCreate))
+	for _, topic := range plan.ToCreate {
+		isVisible, restErr := canSee(topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if isVisible {
+			toCreate = append(toCreate, topic)
+		}
+	}
+	plan.ToCreate = toCreate
+
+	toAddPartitions := make([]console.TopicImportPartitions, 0, len(plan.ToAddPartitions))
+	for _, topic := range plan.ToAddPartitions {
+		isVisible, restErr := canSee(topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if isVisible {
+			toAddPartitions = append(toAddPartitions, topic)
+		}
+	}
+	plan.ToAddPartitions = toAddPartitions
+
+	toAlterConfigs := make([]console.TopicImportConfigs, 0, len(plan.ToAlterConfigs))
+	for _, topic := range plan.ToAlterConfigs {
+		canView, restErr := api.Hooks.Console.CanViewTopicConfig(ctx, topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if canView {
+			toAlterConfigs = append(toAlterConfigs, topic)
+		}
+	}
+	plan.ToAlterConfigs = toAlterConfigs
+
+	toDelete := make([]string, 0, len(plan.ToDelete))
+	for _, topicName := range plan.ToDelete {
+		isVisible, restErr := canSee(topicName)
+		if restErr != nil {
+			return restErr
+		}
+		if isVisible {
+			toDelete = append(toDelete, topicName)
+		}
+	}
+	plan.ToDelete = toDelete
+
+	errors := make([]console.TopicImportActionResult, 0, len(plan.Errors))
+	for _, topicErr := range plan.Errors {
+		isVisible, restErr := canSee(topicErr.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if isVisible {
+			errors = append(errors, topicErr)
+		}
+	}
+	plan.Errors = errors
+
+	return nil
+}
+
+func (api *API) checkTopicImportPermissions(ctx context.Context, plan *console.TopicImportPlan) *rest.Error {
+	forbidden := func(action string, topicName string) *rest.Error {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester has no permissions to %v topic '%v'", action, topicName),
+			Status:   http.StatusForbidden,
+			Message:  fmt.Sprintf("You don't have permissions to %v topic '%v'", action, topicName),
+			IsSilent: false,
+		}
+	}
+
+	for _, topic := range plan.ToCreate {
+		isAllowed, restErr := api.Hooks.Console.CanCreateTopic(ctx, topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if !isAllowed {
+			return forbidden("create", topic.TopicName)
+		}
+	}
+	for _, topic := range plan.ToAddPartitions {
+		isAllowed, restErr := api.Hooks.Console.CanCreateTopicPartitions(ctx, topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if !isAllowed {
+			return forbidden("add partitions to", topic.TopicName)
+		}
+	}
+	if len(plan.ToAlterConfigs) > 0 {
+		isAllowed, restErr := api.Hooks.Console.CanPatchConfigs(ctx)
+		if restErr != nil {
+			return restErr
+		}
+		if !isAllowed {
+			return forbidden("alter configs of", plan.ToAlterConfigs[0].TopicName)
+		}
+	}
+	for _, topicName := range plan.ToDelete {
+		isAllowed, restErr := api.Hooks.Console.CanDeleteTopic(ctx, topicName)
+		if restErr != nil {
+			return restErr
+		}
+		if !isAllowed {
+			return forbidden("delete", topicName)
+		}
+	}
+
+	return nil
+}
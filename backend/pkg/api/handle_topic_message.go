@@ -0,0 +1,238 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/console"
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+)
+
+// messageReference identifies a single record via its topic, partition and offset.
+type messageReference struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+	Offset      int64  `json:"offset"`
+}
+
+func (m *messageReference) OK() error {
+	if m.TopicName == "" {
+		return fmt.Errorf("topic name is required")
+	}
+	if m.PartitionID < 0 {
+		return fmt.Errorf("partitionID must not be negative")
+	}
+	if m.Offset < 0 {
+		return fmt.Errorf("offset must not be negative")
+	}
+
+	return nil
+}
+
+// parseMessageReference parses the topic name, partition id and offset from the URL parameters.
+func parseMessageReference(r *http.Request) (messageReference, *rest.Error) {
+	ref := messageReference{TopicName: chi.URLParam(r, "topicName")}
+
+	partitionID, err := strconv.ParseInt(chi.URLParam(r, "partitionID"), 10, 32)
+	if err != nil {
+		return ref, &rest.Error{
+			Err:      fmt.Errorf("failed to parse partition id: %w", err),
+			Status:   http.StatusBadRequest,
+			Message:  "Partition ID must be a valid int32",
+			IsSilent: true,
+		}
+	}
+	ref.PartitionID = int32(partitionID)
+
+	offset, err := strconv.ParseInt(chi.URLParam(r, "offset"), 10, 64)
+	if err != nil {
+		return ref, &rest.Error{
+			Err:      fmt.Errorf("failed to parse offset: %w", err),
+			Status:   http.StatusBadRequest,
+			Message:  "Offset must be a valid int64",
+			IsSilent: true,
+		}
+	}
+	ref.Offset = offset
+
+	err = ref.OK()
+	if err != nil {
+		return ref, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to validate request parameters: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	return ref, nil
+}
+
+// checkCanViewTopicMessages returns a REST error if the requester is not allowed to view messages in the given topic.
+func (api *API) checkCanViewTopicMessages(r *http.Request, topicName string) *rest.Error {
+	canView, restErr := api.Hooks.Console.CanViewTopicMessages(r.Context(), topicName)
+	if restErr != nil {
+		return restErr
+	}
+	if !canView {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester has no permissions to view messages in the requested topic"),
+			Status:   http.StatusForbidden,
+			Message:  fmt.Sprintf("You don't have permissions to view messages in topic '%v'", topicName),
+			IsSilent: false,
+		}
+	}
+
+	return nil
+}
+
+// handleInspectMessage returns a byte-level breakdown of a single record, including a hex dump, the detected
+// framing and the results of all decoders.
+func (api *API) handleInspectMessage() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		ref, restErr := parseMessageReference(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		logger := api.Logger.With(zap.String("topic_name", ref.TopicName))
+
+		// 2. Check if logged in user is allowed to view messages in this topic
+		restErr = api.checkCanViewTopicMessages(r, ref.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		canBypassRedaction, restErr := api.Hooks.Console.CanBypassMessageRedaction(r.Context(), ref.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		// 3. Fetch and inspect record
+		inspection, restErr := api.ConsoleSvc.InspectMessage(r.Context(), ref.TopicName, ref.PartitionID, ref.Offset, canBypassRedaction)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, logger, http.StatusOK, inspection)
+	}
+}
+
+// handleGetMessage returns a single deserialized record that is identified by its topic, partition and offset.
+func (api *API) handleGetMessage() http.HandlerFunc {
+	type response struct {
+		TopicName string              `json:"topicName"`
+		Message   *kafka.TopicMessage `json:"message"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		ref, restErr := parseMessageReference(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		logger := api.Logger.With(zap.String("topic_name", ref.TopicName))
+
+		// 2. Check if logged in user is allowed to view messages in this topic
+		restErr = api.checkCanViewTopicMessages(r, ref.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		canBypassRedaction, restErr := api.Hooks.Console.CanBypassMessageRedaction(r.Context(), ref.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		// 3. Fetch record
+		message, restErr := api.ConsoleSvc.GetMessage(r.Context(), ref.TopicName, ref.PartitionID, ref.Offset, canBypassRedaction)
+		if restErr != nil {
+			rest.SendRESTError(w, r, logger, restErr)
+			return
+		}
+
+		res := response{
+			TopicName: ref.TopicName,
+			Message:   message,
+		}
+		rest.SendResponse(w, r, logger, http.StatusOK, res)
+	}
+}
+
+// handleDiffMessages returns a structured diff of the key, value and headers of two deserialized records.
+func (api *API) handleDiffMessages() http.HandlerFunc {
+	type request struct {
+		Left  messageReference `json:"left"`
+		Right messageReference `json:"right"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req request
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		for _, ref := range []messageReference{req.Left, req.Right} {
+			err := ref.OK()
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      err,
+					Status:   http.StatusBadRequest,
+					Message:  fmt.Sprintf("Failed to validate message reference: %v", err.Error()),
+					IsSilent: true,
+				})
+				return
+			}
+		}
+
+		// 2. Check if logged in user is allowed to view messages in both topics. Redaction is only bypassed if
+		// the user is allowed to do so in both topics.
+		canBypassRedaction := true
+		for _, topicName := range []string{req.Left.TopicName, req.Right.TopicName} {
+			restErr = api.checkCanViewTopicMessages(r, topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			canBypass, restErr := api.Hooks.Console.CanBypassMessageRedaction(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			canBypassRedaction = canBypassRedaction && canBypass
+		}
+
+		// 3. Fetch both records and compute diff
+		left := console.MessageReference{TopicName: req.Left.TopicName, PartitionID: req.Left.PartitionID, Offset: req.Left.Offset}
+		right := console.MessageReference{TopicName: req.Right.TopicName, PartitionID: req.Right.PartitionID, Offset: req.Right.Offset}
+		diff, restErr := api.ConsoleSvc.DiffMessages(r.Context(), left, right, canBypassRedaction)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, diff)
+	}
+}
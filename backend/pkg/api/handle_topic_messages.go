@@ -139,6 +139,12 @@ func (api *API) handleGetMessages() http.HandlerFunc {
 			}
 		}
 
+		canBypassRedaction, restErr := api.Hooks.Console.CanBypassMessageRedaction(r.Context(), req.TopicName)
+		if restErr != nil {
+			sendError(restErr.Message)
+			return
+		}
+
 		interpreterCode, _ := req.DecodeInterpreterCode() // Error has been checked in validation function
 
 		// Request messages from kafka and return them once we got all the messages or the context is done
@@ -149,6 +155,7 @@ func (api *API) handleGetMessages() http.HandlerFunc {
 			StartTimestamp:        req.StartTimestamp,
 			MessageCount:          req.MaxResults,
 			FilterInterpreterCode: interpreterCode,
+			BypassRedaction:       canBypassRedaction,
 		}
 		api.Hooks.Console.PrintListMessagesAuditLog(r, &listReq)
 
@@ -13,6 +13,7 @@ import (
 	_ "context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	_ "time"
@@ -201,6 +202,233 @@ func (api *API) handleDeleteTopic() http.HandlerFunc {
 	}
 }
 
+type deleteTopicsPreflightRequest struct {
+	// TopicNames is a list of topics that shall be deleted.
+	TopicNames []string `json:"topicNames"`
+
+	// TopicNamePattern is a regex. All topics whose name match this regex will be deleted.
+	TopicNamePattern string `json:"topicNamePattern"`
+}
+
+func (d *deleteTopicsPreflightRequest) OK() error {
+	if len(d.TopicNames) == 0 && d.TopicNamePattern == "" {
+		return fmt.Errorf("either a list of topic names or a topic name pattern must be set")
+	}
+	if len(d.TopicNames) > 0 && d.TopicNamePattern != "" {
+		return fmt.Errorf("either a list of topic names or a topic name pattern must be set, but not both")
+	}
+	seen := make(map[string]struct{}, len(d.TopicNames))
+	for _, topicName := range d.TopicNames {
+		if topicName == "" {
+			return fmt.Errorf("topic names must not be empty")
+		}
+		if _, exists := seen[topicName]; exists {
+			return fmt.Errorf("topic name '%v' is specified more than once", topicName)
+		}
+		seen[topicName] = struct{}{}
+	}
+
+	return nil
+}
+
+// handleDeleteTopicsPreflight reports the consumer groups, the latest message timestamp and the size of all topics
+// that are selected by a list of topic names or by a regex. The returned confirmation token is required to delete
+// the topics that are reported as deletable.
+func (api *API) handleDeleteTopicsPreflight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteTopicsPreflightRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Resolve topic names. Topics which the requester is not allowed to see are not matched by the pattern.
+		topicNames := req.TopicNames
+		if req.TopicNamePattern != "" {
+			matchedTopicNames, restErr := api.ConsoleSvc.ListTopicNamesByPattern(r.Context(), req.TopicNamePattern)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			topicNames = make([]string, 0, len(matchedTopicNames))
+			for _, topicName := range matchedTopicNames {
+				canSee, restErr := api.Hooks.Console.CanSeeTopic(r.Context(), topicName)
+				if restErr != nil {
+					rest.SendRESTError(w, r, api.Logger, restErr)
+					return
+				}
+				if canSee {
+					topicNames = append(topicNames, topicName)
+				}
+			}
+		}
+
+		// 3. Check if logged in user is allowed to delete each topic
+		forbidden := make([]console.TopicDeletionReport, 0)
+		deletableTopicNames := make([]string, 0, len(topicNames))
+		for _, topicName := range topicNames {
+			canDelete, restErr := api.Hooks.Console.CanDeleteTopic(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canDelete {
+				forbidden = append(forbidden, console.TopicDeletionReport{
+					TopicName:                  topicName,
+					LastMessageTimestamp:       -1,
+					LastMessageTimestampStatus: console.LatestTimestampStatusFailed,
+					ConsumerGroups:             []console.CommittedConsumerGroup{},
+					Error:                      "You don't have permissions to delete this topic",
+				})
+				continue
+			}
+			deletableTopicNames = append(deletableTopicNames, topicName)
+		}
+
+		// 4. Run pre-flight
+		preflight, restErr := api.ConsoleSvc.PreflightTopicDeletion(r.Context(), deletableTopicNames)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		preflight.Topics = append(preflight.Topics, forbidden...)
+		sort.Slice(preflight.Topics, func(i, j int) bool { return preflight.Topics[i].TopicName < preflight.Topics[j].TopicName })
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, preflight)
+	}
+}
+
+type deleteTopicsRequest struct {
+	// ConfirmationToken is the token that has been returned by the topic deletion pre-flight.
+	ConfirmationToken string `json:"confirmationToken"`
+}
+
+func (d *deleteTopicsRequest) OK() error {
+	if d.ConfirmationToken == "" {
+		return fmt.Errorf("confirmation token must be set")
+	}
+	return nil
+}
+
+// handleDeleteTopics deletes all topics that have been reported as deletable by the pre-flight which issued the
+// given confirmation token.
+func (api *API) handleDeleteTopics() http.HandlerFunc {
+	type response struct {
+		Topics []console.DeleteTopicResult `json:"topics"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteTopicsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		topicNames, restErr := api.ConsoleSvc.ConfirmTopicDeletion(req.ConfirmationToken)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to delete each topic
+		results := make([]console.DeleteTopicResult, 0, len(topicNames))
+		deletableTopicNames := make([]string, 0, len(topicNames))
+		for _, topicName := range topicNames {
+			canDelete, restErr := api.Hooks.Console.CanDeleteTopic(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canDelete {
+				results = append(results, console.DeleteTopicResult{
+					TopicName: topicName,
+					Error:     "You don't have permissions to delete this topic",
+				})
+				continue
+			}
+			deletableTopicNames = append(deletableTopicNames, topicName)
+		}
+
+		// 3. Delete topics
+		deleted, restErr := api.ConsoleSvc.DeleteTopics(r.Context(), deletableTopicNames)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		results = append(results, deleted...)
+		sort.Slice(results, func(i, j int) bool { return results[i].TopicName < results[j].TopicName })
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Topics: results})
+	}
+}
+
+type createPartitionsRequest struct {
+	// PartitionCount is the new total number of partitions, it must be larger than the current partition count.
+	PartitionCount int32 `json:"partitionCount"`
+
+	// Assignments optionally contains the replica assignment (broker ids) for each new partition.
+	Assignments [][]int32 `json:"assignments"`
+
+	// ValidateOnly only validates the request without creating the partitions.
+	ValidateOnly bool `json:"validateOnly"`
+}
+
+func (c *createPartitionsRequest) OK() error {
+	if c.PartitionCount < 1 {
+		return fmt.Errorf("partition count must be at least 1")
+	}
+	for i, replicas := range c.Assignments {
+		if len(replicas) == 0 {
+			return fmt.Errorf("replica assignment at index %d must contain at least one broker id", i)
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleCreatePartitions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		topicName := chi.URLParam(r, "topicName")
+
+		// 1. Parse and validate request
+		var req createPartitionsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to add partitions to the given topic
+		canCreate, restErr := api.Hooks.Console.CanCreateTopicPartitions(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canCreate {
+			restErr := &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to create partitions for this topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to create partitions for this topic",
+				IsSilent: false,
+			}
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Create partitions
+		res, restErr := api.ConsoleSvc.CreatePartitions(r.Context(), topicName, req.PartitionCount, req.Assignments, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
 type deleteTopicRecordsRequest struct {
 	// Partitions contains partitions to delete records from.
 	Partitions []struct {
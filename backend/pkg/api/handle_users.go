@@ -0,0 +1,230 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/console"
+	"github.com/go-chi/chi"
+)
+
+type scramUserCredentialRequest struct {
+	Password   string `json:"password"`
+	Mechanism  string `json:"mechanism"`
+	Iterations int32  `json:"iterations"` // Defaults to 4096
+}
+
+func (s *scramUserCredentialRequest) OK() error {
+	if s.Password == "" {
+		return fmt.Errorf("password must be set")
+	}
+	_, err := console.ScramMechanismFromString(s.Mechanism)
+	if err != nil {
+		return err
+	}
+	if s.Iterations == 0 {
+		s.Iterations = console.ScramDefaultIterations
+	}
+	if s.Iterations < console.ScramMinIterations || s.Iterations > console.ScramMaxIterations {
+		return fmt.Errorf("iterations must be between %d and %d", console.ScramMinIterations, console.ScramMaxIterations)
+	}
+
+	return nil
+}
+
+type createScramUserRequest struct {
+	Username string `json:"username"`
+	scramUserCredentialRequest
+}
+
+func (c *createScramUserRequest) OK() error {
+	if c.Username == "" {
+		return fmt.Errorf("username must be set")
+	}
+	return c.scramUserCredentialRequest.OK()
+}
+
+// checkScramUserHook returns a REST error if the given hook does not allow the requested action.
+func (api *API) checkScramUserHook(isAllowed bool, restErr *rest.Error, action string) *rest.Error {
+	if restErr != nil {
+		return restErr
+	}
+	if !isAllowed {
+		return &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to %v SCRAM users", action),
+			Status:   http.StatusForbidden,
+			Message:  fmt.Sprintf("You are not allowed to %v SCRAM users", action),
+			IsSilent: false,
+		}
+	}
+	return nil
+}
+
+func (api *API) handleGetScramUsers() http.HandlerFunc {
+	type response struct {
+		Users []console.ScramUser `json:"users"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		isAllowed, restErr := api.Hooks.Console.CanListScramUsers(r.Context())
+		restErr = api.checkScramUserHook(isAllowed, restErr, "list")
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		users, restErr := api.ConsoleSvc.ListScramUsers(r.Context(), nil)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Users: users})
+	}
+}
+
+func (api *API) handleCreateScramUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req createScramUserRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to create SCRAM users
+		isAllowed, restErr := api.Hooks.Console.CanCreateScramUser(r.Context(), req.Username)
+		restErr = api.checkScramUserHook(isAllowed, restErr, "create")
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Make sure we don't overwrite an existing credential
+		users, restErr := api.ConsoleSvc.ListScramUsers(r.Context(), &req.Username)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		for _, user := range users {
+			for _, credential := range user.Credentials {
+				if credential.Mechanism == req.Mechanism {
+					rest.SendRESTError(w, r, api.Logger, &rest.Error{
+						Err:      fmt.Errorf("user already has a credential for the requested mechanism"),
+						Status:   http.StatusConflict,
+						Message:  fmt.Sprintf("User '%v' already has a %v credential", req.Username, req.Mechanism),
+						IsSilent: true,
+					})
+					return
+				}
+			}
+		}
+
+		// 4. Create user
+		restErr = api.ConsoleSvc.UpsertScramUser(r.Context(), req.Username, req.Mechanism, req.Password, req.Iterations)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusCreated, console.ScramUser{
+			Name:        req.Username,
+			Credentials: []console.ScramCredential{{Mechanism: req.Mechanism, Iterations: req.Iterations}},
+		})
+	}
+}
+
+func (api *API) handleUpdateScramUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := chi.URLParam(r, "username")
+
+		// 1. Parse and validate request
+		var req scramUserCredentialRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to edit SCRAM users
+		isAllowed, restErr := api.Hooks.Console.CanEditScramUser(r.Context(), username)
+		restErr = api.checkScramUserHook(isAllowed, restErr, "edit")
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Make sure we don't create a new user, as this requires the create permission
+		users, restErr := api.ConsoleSvc.ListScramUsers(r.Context(), &username)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if len(users) == 0 {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("SCRAM user does not exist"),
+				Status:   http.StatusNotFound,
+				Message:  fmt.Sprintf("User '%v' does not exist", username),
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 4. Update user
+		restErr = api.ConsoleSvc.UpsertScramUser(r.Context(), username, req.Mechanism, req.Password, req.Iterations)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, console.ScramUser{
+			Name:        username,
+			Credentials: []console.ScramCredential{{Mechanism: req.Mechanism, Iterations: req.Iterations}},
+		})
+	}
+}
+
+func (api *API) handleDeleteScramUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := chi.URLParam(r, "username")
+		mechanism := r.URL.Query().Get("mechanism")
+		if mechanism != "" {
+			_, err := console.ScramMechanismFromString(mechanism)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      err,
+					Status:   http.StatusBadRequest,
+					Message:  err.Error(),
+					IsSilent: true,
+				})
+				return
+			}
+		}
+
+		isAllowed, restErr := api.Hooks.Console.CanDeleteScramUser(r.Context(), username)
+		restErr = api.checkScramUserHook(isAllowed, restErr, "delete")
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		restErr = api.ConsoleSvc.DeleteScramUser(r.Context(), username, mechanism)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
+	}
+}
@@ -48,21 +48,33 @@ type ConsoleHooks interface {
 	CanSeeTopic(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanCreateTopic(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanDeleteTopic(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanCreateTopicPartitions(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanPublishTopicRecords(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanDeleteTopicRecords(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanViewTopicPartitions(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanViewTopicConfig(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanViewTopicMessages(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanUseMessageSearchFilters(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanBypassMessageRedaction(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanViewTopicConsumers(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanElectTopicLeaders(ctx context.Context, topicName string) (bool, *rest.Error)
 	AllowedTopicActions(ctx context.Context, topicName string) ([]string, *rest.Error)
 	PrintListMessagesAuditLog(r *http.Request, req *console.ListMessageRequest)
 
 	// ACL Hooks
 	CanListACLs(ctx context.Context) (bool, *rest.Error)
+	CanCreateACL(ctx context.Context) (bool, *rest.Error)
+	CanDeleteACL(ctx context.Context) (bool, *rest.Error)
+
+	// SCRAM User Hooks
+	CanListScramUsers(ctx context.Context) (bool, *rest.Error)
+	CanCreateScramUser(ctx context.Context, username string) (bool, *rest.Error)
+	CanEditScramUser(ctx context.Context, username string) (bool, *rest.Error)
+	CanDeleteScramUser(ctx context.Context, username string) (bool, *rest.Error)
 
 	// Quotas Hookas
 	CanListQuotas(ctx context.Context) (bool, *rest.Error)
+	CanAlterQuotas(ctx context.Context) (bool, *rest.Error)
 
 	// ConsumerGroup Hooks
 	CanSeeConsumerGroup(ctx context.Context, groupName string) (bool, *rest.Error)
@@ -73,6 +85,7 @@ type ConsoleHooks interface {
 	// Operations Hooks
 	CanPatchPartitionReassignments(ctx context.Context) (bool, *rest.Error)
 	CanPatchConfigs(ctx context.Context) (bool, *rest.Error)
+	CanElectLeaders(ctx context.Context) (bool, *rest.Error)
 
 	// Kafka Connect Hooks
 	CanViewConnectCluster(ctx context.Context, clusterName string) (bool, *rest.Error)
@@ -107,6 +120,9 @@ func (*defaultHooks) CanCreateTopic(_ context.Context, _ string) (bool, *rest.Er
 func (*defaultHooks) CanDeleteTopic(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanCreateTopicPartitions(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
 func (*defaultHooks) CanPublishTopicRecords(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
@@ -125,9 +141,16 @@ func (*defaultHooks) CanViewTopicMessages(_ context.Context, _ string) (bool, *r
 func (*defaultHooks) CanUseMessageSearchFilters(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanBypassMessageRedaction(_ context.Context, _ string) (bool, *rest.Error) {
+	// Redaction rules shall apply to everyone unless explicitly allowed otherwise
+	return false, nil
+}
 func (*defaultHooks) CanViewTopicConsumers(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanElectTopicLeaders(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
 func (*defaultHooks) AllowedTopicActions(_ context.Context, _ string) ([]string, *rest.Error) {
 	// "all" will be considered as wild card - all actions are allowed
 	return []string{"all"}, nil
@@ -136,9 +159,30 @@ func (*defaultHooks) PrintListMessagesAuditLog(_ *http.Request, _ *console.ListM
 func (*defaultHooks) CanListACLs(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanCreateACL(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+func (*defaultHooks) CanDeleteACL(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+func (*defaultHooks) CanListScramUsers(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+func (*defaultHooks) CanCreateScramUser(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+func (*defaultHooks) CanEditScramUser(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+func (*defaultHooks) CanDeleteScramUser(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
 func (*defaultHooks) CanListQuotas(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanAlterQuotas(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
 func (*defaultHooks) CanSeeConsumerGroup(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
@@ -158,6 +202,9 @@ func (*defaultHooks) CanPatchPartitionReassignments(_ context.Context) (bool, *r
 func (*defaultHooks) CanPatchConfigs(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
+func (*defaultHooks) CanElectLeaders(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
 func (*defaultHooks) CanViewConnectCluster(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
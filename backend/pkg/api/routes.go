@@ -74,35 +74,71 @@ func (api *API) routes() *chi.Mux {
 				r.Get("/api-versions", api.handleGetAPIVersions())
 				r.Get("/brokers/{brokerID}/config", api.handleBrokerConfig())
 				r.Get("/cluster", api.handleDescribeCluster())
+				r.Get("/cluster/health", api.handleGetClusterHealth())
 				r.Get("/acls", api.handleGetACLsOverview())
+				r.Post("/acls", api.handleCreateACLs())
+				r.Delete("/acls", api.handleDeleteACLs())
+				r.Get("/acls/check", api.handleCheckACLPermission())
+				r.Get("/acls/principals", api.handleGetACLsByPrincipal())
+				r.Get("/acls/export", api.handleExportACLs())
+				r.Post("/acls/import/plan", api.handlePlanACLImport())
+				r.Post("/acls/import/apply", api.handleApplyACLImport())
 
 				// Topics
 				r.Get("/topics-configs", api.handleGetTopicsConfigs())
 				r.Get("/topics-offsets", api.handleGetTopicsOffsets())
 				r.Post("/topics-records", api.handlePublishTopicsRecords())
+				r.Post("/topics-messages-diff", api.handleDiffMessages())
 				r.Get("/topics", api.handleGetTopics())
 				r.Post("/topics", api.handleCreateTopic())
+				r.Delete("/topics", api.handleDeleteTopics())
+				r.Post("/topics/delete-preflight", api.handleDeleteTopicsPreflight())
+				r.Post("/topics/import/plan", api.handlePlanTopicImport())
+				r.Post("/topics/import/apply", api.handleApplyTopicImport())
+				r.Post("/topics/{topicName}/clone", api.handleCloneTopic())
+				r.Get("/topic-templates", api.handleGetTopicTemplates())
 				r.Delete("/topics/{topicName}", api.handleDeleteTopic())
 				r.Delete("/topics/{topicName}/records", api.handleDeleteTopicRecords())
 				r.Get("/topics/{topicName}/partitions", api.handleGetPartitions())
+				r.Patch("/topics/{topicName}/partitions", api.handleCreatePartitions())
 				r.Get("/topics/{topicName}/configuration", api.handleGetTopicConfig())
 				r.Get("/topics/{topicName}/consumers", api.handleGetTopicConsumers())
 				r.Get("/topics/{topicName}/documentation", api.handleGetTopicDocumentation())
+				r.Get("/topics/{topicName}/partitions/{partitionID}/offsets/{offset}", api.handleGetMessage())
+				r.Get("/topics/{topicName}/partitions/{partitionID}/offsets/{offset}/inspect", api.handleInspectMessage())
+
+				// SCRAM Users
+				r.Get("/users", api.handleGetScramUsers())
+				r.Post("/users", api.handleCreateScramUser())
+				r.Put("/users/{username}", api.handleUpdateScramUser())
+				r.Delete("/users/{username}", api.handleDeleteScramUser())
 
 				// Quotas
 				r.Get("/quotas", api.handleGetQuotas())
+				r.Put("/quotas", api.handlePutQuotas())
+				r.Delete("/quotas", api.handleDeleteQuotas())
+				r.Get("/quotas/effective", api.handleGetEffectiveQuota())
 
 				// Consumer Groups
 				r.Get("/consumer-groups", api.handleGetConsumerGroups())
+				r.Delete("/consumer-groups", api.handleDeleteConsumerGroups())
 				r.Get("/consumer-groups/{groupId}", api.handleGetConsumerGroup())
+				r.Get("/consumer-groups/{groupId}/lag-history", api.handleGetConsumerGroupLagHistory())
+				r.Get("/consumer-groups/{groupId}/events", api.handleGetConsumerGroupEvents())
 				r.Patch("/consumer-groups/{groupId}", api.handlePatchConsumerGroup())
+				r.Post("/consumer-groups/{groupId}/reset-offsets", api.handleResetConsumerGroupOffsets())
 				r.Delete("/consumer-groups/{groupId}", api.handleDeleteConsumerGroupOffsets())
 
 				// Bulk Operations
 				r.Get("/operations/topic-details", api.handleGetAllTopicDetails())
 				r.Get("/operations/reassign-partitions", api.handleGetPartitionReassignments())
 				r.Patch("/operations/reassign-partitions", api.handlePatchPartitionAssignments())
+				r.Post("/operations/reassign-partitions/plan", api.handlePlanPartitionReassignments())
 				r.Patch("/operations/configs", api.handlePatchConfigs())
+				r.Post("/operations/elect-leaders", api.handleElectLeaders())
+				r.Get("/operations/non-preferred-leaders", api.handleGetNonPreferredLeaders())
+				r.Get("/operations/stale-topics", api.handleGetStaleTopics())
+				r.Get("/operations/topic-config-violations", api.handleGetTopicConfigViolations())
 
 				// Schema Registry
 				r.Get("/schemas", api.handleGetSchemaOverview())
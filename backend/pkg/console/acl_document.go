@@ -0,0 +1,161 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// AclDocument is a declarative representation of all ACL bindings in a cluster. It can be exported as YAML or
+// JSON, stored in git and imported into (another) cluster.
+type AclDocument struct {
+	ACLs []AclBinding `json:"acls" yaml:"acls"`
+}
+
+// AclImportPlan contains the changes that are required to bring the cluster's ACLs in line with an AclDocument.
+type AclImportPlan struct {
+	ToCreate       []AclBinding `json:"toCreate"`
+	ToDelete       []AclBinding `json:"toDelete"`
+	UnchangedCount int          `json:"unchangedCount"`
+
+	// PlanHash identifies the planned changes. It must be passed when applying the document so that
+	// only the reviewed changes are applied.
+	PlanHash string `json:"planHash"`
+}
+
+// AclImportResult is the result of applying an AclImportPlan.
+type AclImportResult struct {
+	Plan    *AclImportPlan      `json:"plan"`
+	Created *CreateACLsResponse `json:"created"`
+	Deleted *DeleteACLsResponse `json:"deleted"`
+}
+
+// ExportACLs returns all ACL bindings of the cluster as declarative document.
+func (s *Service) ExportACLs(ctx context.Context) (*AclDocument, *rest.Error) {
+	bindings, err := s.ListACLBindings(ctx, newDescribeAllACLsRequest())
+	if err != nil {
+		return nil, newACLRequestError("Failed to list ACLs", err)
+	}
+	sortACLBindings(bindings)
+
+	return &AclDocument{ACLs: bindings}, nil
+}
+
+// PlanACLImport compares the desired ACLs of the given document with the cluster's current ACLs. All bindings
+// that are missing will be created, all bindings that are not part of the document will be deleted.
+func (s *Service) PlanACLImport(ctx context.Context, doc AclDocument) (*AclImportPlan, *rest.Error) {
+	desired := make(map[AclBinding]struct{}, len(doc.ACLs))
+	for i, binding := range doc.ACLs {
+		// Normalize the binding (e.g. 'topic' => 'TOPIC') so that it's comparable with the existing bindings
+		creation, err := binding.ToCreation()
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("ACL at index '%d' is invalid: %v", i, err.Error()),
+				IsSilent: true,
+			}
+		}
+		desired[aclBindingFromCreation(creation)] = struct{}{}
+	}
+
+	existing, err := s.ListACLBindings(ctx, newDescribeAllACLsRequest())
+	if err != nil {
+		return nil, newACLRequestError("Failed to list existing ACLs", err)
+	}
+
+	plan := &AclImportPlan{
+		ToCreate: make([]AclBinding, 0),
+		ToDelete: make([]AclBinding, 0),
+	}
+	existingSet := make(map[AclBinding]struct{}, len(existing))
+	for _, binding := range existing {
+		existingSet[binding] = struct{}{}
+		if _, isDesired := desired[binding]; isDesired {
+			plan.UnchangedCount++
+			continue
+		}
+		plan.ToDelete = append(plan.ToDelete, binding)
+	}
+	for binding := range desired {
+		if _, exists := existingSet[binding]; !exists {
+			plan.ToCreate = append(plan.ToCreate, binding)
+		}
+	}
+	sortACLBindings(plan.ToCreate)
+	sortACLBindings(plan.ToDelete)
+	plan.PlanHash = hashACLImportPlan(plan)
+
+	return plan, nil
+}
+
+// hashACLImportPlan returns the hex encoded SHA-256 hash of the plan's sorted bindings to create and delete.
+func hashACLImportPlan(plan *AclImportPlan) string {
+	// Marshalling slices of flat structs can not fail
+	changes, _ := json.Marshal([][]AclBinding{plan.ToCreate, plan.ToDelete})
+	hash := sha256.Sum256(changes)
+	return hex.EncodeToString(hash[:])
+}
+
+// ApplyACLImportPlan creates and deletes the ACL bindings of the given plan.
+func (s *Service) ApplyACLImportPlan(ctx context.Context, plan *AclImportPlan) (*AclImportResult, *rest.Error) {
+	result := &AclImportResult{Plan: plan}
+
+	if len(plan.ToCreate) > 0 {
+		creations := make([]kmsg.CreateACLsRequestCreation, len(plan.ToCreate))
+		for i, binding := range plan.ToCreate {
+			creation, err := binding.ToCreation()
+			if err != nil {
+				return nil, newACLRequestError("Failed to create ACLs", err)
+			}
+			creations[i] = creation
+		}
+		created, restErr := s.CreateACLs(ctx, creations, false)
+		if restErr != nil {
+			return nil, restErr
+		}
+		result.Created = created
+	}
+
+	if len(plan.ToDelete) > 0 {
+		filters := make([]kmsg.DeleteACLsRequestFilter, len(plan.ToDelete))
+		for i, binding := range plan.ToDelete {
+			creation, err := binding.ToCreation()
+			if err != nil {
+				return nil, newACLRequestError("Failed to delete ACLs", err)
+			}
+			// Filter that exactly matches the single binding
+			filter := kmsg.NewDeleteACLsRequestFilter()
+			filter.ResourceType = creation.ResourceType
+			filter.ResourceName = kmsg.StringPtr(creation.ResourceName)
+			filter.ResourcePatternType = creation.ResourcePatternType
+			filter.Principal = kmsg.StringPtr(creation.Principal)
+			filter.Host = kmsg.StringPtr(creation.Host)
+			filter.Operation = creation.Operation
+			filter.PermissionType = creation.PermissionType
+			filters[i] = filter
+		}
+		deleted, restErr := s.DeleteACLs(ctx, filters, false)
+		if restErr != nil {
+			return nil, restErr
+		}
+		result.Deleted = deleted
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	QuotaEntityTypeUser     = "user"
+	QuotaEntityTypeClientID = "client-id"
+	QuotaEntityTypeIP       = "ip"
+
+	QuotaKeyProducerByteRate       = "producer_byte_rate"
+	QuotaKeyConsumerByteRate       = "consumer_byte_rate"
+	QuotaKeyRequestPercentage      = "request_percentage"
+	QuotaKeyControllerMutationRate = "controller_mutation_rate"
+	QuotaKeyConnectionCreationRate = "connection_creation_rate"
+)
+
+// QuotaEntityComponent is a part of a quota entity, e.g. the user of a user+client-id entity. A nil name refers
+// to the default entity of the given type.
+type QuotaEntityComponent struct {
+	Type string  `json:"type"`
+	Name *string `json:"name"`
+}
+
+// AlterQuotasResponse is the response that is sent after altering (or validating) client quotas.
+type AlterQuotasResponse struct {
+	ValidateOnly bool                     `json:"validateOnly"`
+	Results      []AlterQuotasEntryResult `json:"results"`
+}
+
+// AlterQuotasEntryResult is the result for a single quota entity.
+type AlterQuotasEntryResult struct {
+	Entity []QuotaEntityComponent `json:"entity"`
+	Error  *string                `json:"error"`
+}
+
+// ValidateQuotaEntity checks whether the given entity components form a valid quota entity. Valid entities
+// are user, client-id, user+client-id and ip.
+func ValidateQuotaEntity(entity []QuotaEntityComponent) error {
+	types := make(map[string]bool)
+	for _, component := range entity {
+		switch component.Type {
+		case QuotaEntityTypeUser, QuotaEntityTypeClientID, QuotaEntityTypeIP:
+		default:
+			return fmt.Errorf("entity type '%v' is invalid, must be one of: user, client-id, ip", component.Type)
+		}
+		if types[component.Type] {
+			return fmt.Errorf("entity type '%v' must not be specified more than once", component.Type)
+		}
+		types[component.Type] = true
+	}
+
+	switch {
+	case len(entity) == 0:
+		return fmt.Errorf("entity must have at least one component")
+	case types[QuotaEntityTypeIP] && len(entity) > 1:
+		return fmt.Errorf("ip entities can not be combined with other entity types")
+	}
+
+	return nil
+}
+
+// ValidateQuotaKey checks whether the given quota key can be set for the given entity.
+func ValidateQuotaKey(entity []QuotaEntityComponent, key string) error {
+	isIPEntity := len(entity) == 1 && entity[0].Type == QuotaEntityTypeIP
+	switch key {
+	case QuotaKeyProducerByteRate, QuotaKeyConsumerByteRate, QuotaKeyRequestPercentage, QuotaKeyControllerMutationRate:
+		if isIPEntity {
+			return fmt.Errorf("quota '%v' can not be set for ip entities", key)
+		}
+	case QuotaKeyConnectionCreationRate:
+		if !isIPEntity {
+			return fmt.Errorf("quota '%v' can only be set for ip entities", key)
+		}
+	default:
+		return fmt.Errorf("quota key '%v' is unknown", key)
+	}
+
+	return nil
+}
+
+// AlterQuotas sets or removes the given client quotas. If validateOnly is true the changes will only be validated
+// by Kafka but not applied.
+func (s *Service) AlterQuotas(ctx context.Context, entries []kmsg.AlterClientQuotasRequestEntry, validateOnly bool) (*AlterQuotasResponse, *rest.Error) {
+	req := kmsg.NewAlterClientQuotasRequest()
+	req.Entries = entries
+	req.ValidateOnly = validateOnly
+
+	res, err := s.kafkaSvc.AlterClientQuotas(ctx, req)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to alter client quotas: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	results := make([]AlterQuotasEntryResult, len(res.Entries))
+	for i, entry := range res.Entries {
+		entity := make([]QuotaEntityComponent, len(entry.Entity))
+		for j, component := range entry.Entity {
+			entity[j] = QuotaEntityComponent{Type: component.Type, Name: component.Name}
+		}
+
+		var errMsg *string
+		if err := kerr.ErrorForCode(entry.ErrorCode); err != nil {
+			msg := err.Error()
+			if entry.ErrorMessage != nil && *entry.ErrorMessage != "" {
+				msg = fmt.Sprintf("%v: %v", msg, *entry.ErrorMessage)
+			}
+			errMsg = &msg
+		}
+		results[i] = AlterQuotasEntryResult{Entity: entity, Error: errMsg}
+	}
+
+	return &AlterQuotasResponse{ValidateOnly: validateOnly, Results: results}, nil
+}
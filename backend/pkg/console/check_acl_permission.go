@@ -0,0 +1,148 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	ACLDecisionAllowed = "ALLOWED"
+	ACLDecisionDenied  = "DENIED"
+)
+
+// ACLPermissionCheck describes the question "can principal X do Y on Z from host H?".
+type ACLPermissionCheck struct {
+	Principal    string
+	Host         string
+	Operation    kmsg.ACLOperation
+	ResourceType kmsg.ACLResourceType
+	ResourceName string
+}
+
+// ACLPermissionCheckResult is the outcome of evaluating all ACL bindings for an ACLPermissionCheck.
+type ACLPermissionCheckResult struct {
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+
+	// MatchingBindings are all bindings that apply to the principal, host, operation and resource. If the
+	// decision is DENIED because of a DENY binding, only the DENY bindings are relevant.
+	MatchingBindings []AclBinding `json:"matchingBindings"`
+}
+
+// impliedOperations are operations that are implicitly allowed if one of the listed operations is allowed.
+// See: https://docs.confluent.io/platform/current/kafka/authorization.html#implicitly-derived-operations
+var impliedOperations = map[kmsg.ACLOperation][]kmsg.ACLOperation{
+	kmsg.ACLOperationDescribe: {
+		kmsg.ACLOperationRead, kmsg.ACLOperationWrite, kmsg.ACLOperationDelete, kmsg.ACLOperationAlter,
+	},
+	kmsg.ACLOperationDescribeConfigs: {kmsg.ACLOperationAlterConfigs},
+}
+
+// CheckACLPermission evaluates all ACL bindings of the requested resource type and decides whether the
+// principal is allowed to perform the operation on the resource. The evaluation follows the rules of Kafka's
+// AclAuthorizer. Super users and 'allow.everyone.if.no.acl.found' are not known and therefore not considered.
+func (s *Service) CheckACLPermission(ctx context.Context, check ACLPermissionCheck) (*ACLPermissionCheckResult, *rest.Error) {
+	req := newDescribeAllACLsRequest()
+	req.ResourceType = check.ResourceType
+	bindings, err := s.ListACLBindings(ctx, req)
+	if err != nil {
+		return nil, newACLRequestError("Failed to list ACLs", err)
+	}
+
+	result := evaluateACLPermission(bindings, check)
+	return &result, nil
+}
+
+// evaluateACLPermission decides whether the given bindings allow the requested check. DENY bindings take
+// precedence over ALLOW bindings. If no binding matches, the request is denied.
+func evaluateACLPermission(bindings []AclBinding, check ACLPermissionCheck) ACLPermissionCheckResult {
+	denyOperations := []kmsg.ACLOperation{check.Operation, kmsg.ACLOperationAll}
+	allowOperations := append([]kmsg.ACLOperation{check.Operation, kmsg.ACLOperationAll}, impliedOperations[check.Operation]...)
+
+	denyBindings := make([]AclBinding, 0)
+	allowBindings := make([]AclBinding, 0)
+	for _, binding := range bindings {
+		if !aclBindingMatchesResource(binding, check.ResourceType, check.ResourceName) ||
+			!aclBindingMatchesPrincipal(binding, check.Principal) ||
+			!aclBindingMatchesHost(binding, check.Host) {
+			continue
+		}
+
+		switch binding.PermissionType {
+		case kmsg.ACLPermissionTypeDeny.String():
+			if containsACLOperation(denyOperations, binding.Operation) {
+				denyBindings = append(denyBindings, binding)
+			}
+		case kmsg.ACLPermissionTypeAllow.String():
+			if containsACLOperation(allowOperations, binding.Operation) {
+				allowBindings = append(allowBindings, binding)
+			}
+		}
+	}
+
+	if len(denyBindings) > 0 {
+		return ACLPermissionCheckResult{
+			Decision:         ACLDecisionDenied,
+			Reason:           fmt.Sprintf("%d DENY binding(s) match, DENY takes precedence over ALLOW", len(denyBindings)),
+			MatchingBindings: denyBindings,
+		}
+	}
+	if len(allowBindings) > 0 {
+		return ACLPermissionCheckResult{
+			Decision:         ACLDecisionAllowed,
+			Reason:           fmt.Sprintf("%d ALLOW binding(s) match", len(allowBindings)),
+			MatchingBindings: allowBindings,
+		}
+	}
+
+	return ACLPermissionCheckResult{
+		Decision:         ACLDecisionDenied,
+		Reason:           "No ACL binding matches (unless the principal is a super user or allow.everyone.if.no.acl.found is enabled)",
+		MatchingBindings: allowBindings,
+	}
+}
+
+func aclBindingMatchesResource(binding AclBinding, resourceType kmsg.ACLResourceType, resourceName string) bool {
+	if binding.ResourceType != resourceType.String() {
+		return false
+	}
+
+	switch binding.ResourcePatternType {
+	case kmsg.ACLResourcePatternTypeLiteral.String():
+		return binding.ResourceName == resourceName || binding.ResourceName == "*"
+	case kmsg.ACLResourcePatternTypePrefixed.String():
+		return strings.HasPrefix(resourceName, binding.ResourceName)
+	default:
+		return false
+	}
+}
+
+func aclBindingMatchesPrincipal(binding AclBinding, principal string) bool {
+	return binding.Principal == principal || binding.Principal == "User:*"
+}
+
+func aclBindingMatchesHost(binding AclBinding, host string) bool {
+	return binding.Host == host || binding.Host == "*"
+}
+
+func containsACLOperation(operations []kmsg.ACLOperation, operation string) bool {
+	for _, op := range operations {
+		if op.String() == operation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestEvaluateACLPermission(t *testing.T) {
+	check := ACLPermissionCheck{
+		Principal:    "User:alice",
+		Host:         "10.0.0.1",
+		Operation:    kmsg.ACLOperationDescribe,
+		ResourceType: kmsg.ACLResourceTypeTopic,
+		ResourceName: "orders-eu",
+	}
+
+	prefixedWrite := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "orders-",
+		ResourcePatternType: "PREFIXED",
+		Principal:           "User:alice",
+		Host:                "*",
+		Operation:           "WRITE",
+		PermissionType:      "ALLOW",
+	}
+	wildcardDeny := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "*",
+		ResourcePatternType: "LITERAL",
+		Principal:           "User:*",
+		Host:                "*",
+		Operation:           "ALL",
+		PermissionType:      "DENY",
+	}
+	literalDenyWrite := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "orders-eu",
+		ResourcePatternType: "LITERAL",
+		Principal:           "User:alice",
+		Host:                "*",
+		Operation:           "WRITE",
+		PermissionType:      "DENY",
+	}
+	otherPrincipal := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "orders-eu",
+		ResourcePatternType: "LITERAL",
+		Principal:           "User:bob",
+		Host:                "*",
+		Operation:           "READ",
+		PermissionType:      "ALLOW",
+	}
+	otherResource := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "orders",
+		ResourcePatternType: "LITERAL",
+		Principal:           "User:alice",
+		Host:                "*",
+		Operation:           "READ",
+		PermissionType:      "ALLOW",
+	}
+	otherHost := AclBinding{
+		ResourceType:        "TOPIC",
+		ResourceName:        "orders-eu",
+		ResourcePatternType: "LITERAL",
+		Principal:           "User:alice",
+		Host:                "10.0.0.2",
+		Operation:           "READ",
+		PermissionType:      "ALLOW",
+	}
+
+	tt := []struct {
+		name             string
+		bindings         []AclBinding
+		expectedDecision string
+		expectedMatches  []AclBinding
+	}{
+		{
+			name:             "no bindings at all",
+			bindings:         nil,
+			expectedDecision: ACLDecisionDenied,
+		},
+		{
+			name:             "prefixed WRITE implies DESCRIBE",
+			bindings:         []AclBinding{prefixedWrite},
+			expectedDecision: ACLDecisionAllowed,
+			expectedMatches:  []AclBinding{prefixedWrite},
+		},
+		{
+			name:             "wildcard literal DENY for all principals takes precedence",
+			bindings:         []AclBinding{prefixedWrite, wildcardDeny},
+			expectedDecision: ACLDecisionDenied,
+			expectedMatches:  []AclBinding{wildcardDeny},
+		},
+		{
+			name:             "implied operations do not apply to DENY bindings",
+			bindings:         []AclBinding{prefixedWrite, literalDenyWrite},
+			expectedDecision: ACLDecisionAllowed,
+			expectedMatches:  []AclBinding{prefixedWrite},
+		},
+		{
+			name:             "other principals, resources and hosts don't match",
+			bindings:         []AclBinding{otherPrincipal, otherResource, otherHost},
+			expectedDecision: ACLDecisionDenied,
+		},
+	}
+
+	for _, test := range tt {
+		res := evaluateACLPermission(test.bindings, check)
+		assert.Equal(t, test.expectedDecision, res.Decision, test.name)
+		assert.ElementsMatch(t, test.expectedMatches, res.MatchingBindings, test.name)
+	}
+}
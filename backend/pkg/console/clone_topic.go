@@ -0,0 +1,104 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// CloneTopicRequest describes the topic that shall be created as a copy of an existing topic. Unset fields are
+// copied from the source topic.
+type CloneTopicRequest struct {
+	TopicName         string `json:"topicName"`
+	PartitionCount    *int32 `json:"partitionCount"`
+	ReplicationFactor *int16 `json:"replicationFactor"`
+
+	// Configs override the configs of the source topic. A config with a null value is not copied, so that the
+	// broker default applies.
+	Configs []CloneTopicRequestConfig `json:"configs"`
+}
+
+type CloneTopicRequestConfig struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value"`
+}
+
+// CloneTopic creates a new topic with the partition count, replication factor and all explicitly set (non-default)
+// configs of the source topic. Sensitive configs can not be copied, because their values are not returned by Kafka.
+func (s *Service) CloneTopic(ctx context.Context, sourceTopicName string, req CloneTopicRequest) (CreateTopicResponse, *rest.Error) {
+	topicMetadata, restErr := s.kafkaSvc.GetSingleMetadata(ctx, sourceTopicName)
+	if restErr != nil {
+		return CreateTopicResponse{}, restErr
+	}
+	topicConfig, restErr := s.GetTopicConfigs(ctx, sourceTopicName, nil)
+	if restErr != nil {
+		return CreateTopicResponse{}, restErr
+	}
+	if topicConfig.Error != nil {
+		return CreateTopicResponse{}, &rest.Error{
+			Err:      topicConfig.Error,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe configs of source topic: %v", topicConfig.Error.Error()),
+			IsSilent: false,
+		}
+	}
+
+	createTopicReq := kmsg.NewCreateTopicsRequestTopic()
+	createTopicReq.Topic = req.TopicName
+	createTopicReq.NumPartitions = int32(len(topicMetadata.Partitions))
+	if req.PartitionCount != nil {
+		createTopicReq.NumPartitions = *req.PartitionCount
+	}
+	createTopicReq.ReplicationFactor = -1
+	if len(topicMetadata.Partitions) > 0 {
+		createTopicReq.ReplicationFactor = int16(len(topicMetadata.Partitions[0].Replicas))
+	}
+	if req.ReplicationFactor != nil {
+		createTopicReq.ReplicationFactor = *req.ReplicationFactor
+	}
+	createTopicReq.Configs = cloneTopicConfigs(topicConfig.ConfigEntries, req.Configs)
+
+	return s.CreateTopic(ctx, createTopicReq)
+}
+
+// cloneTopicConfigs returns all explicitly set configs with the given overrides applied.
+func cloneTopicConfigs(entries []*TopicConfigEntry, overrides []CloneTopicRequestConfig) []kmsg.CreateTopicsRequestTopicConfig {
+	values := make(map[string]*string)
+	for _, entry := range entries {
+		if !entry.IsExplicitlySet || entry.IsSensitive || entry.Value == nil {
+			continue
+		}
+		values[entry.Name] = entry.Value
+	}
+	for _, override := range overrides {
+		if override.Value == nil {
+			delete(values, override.Name)
+			continue
+		}
+		values[override.Name] = override.Value
+	}
+
+	configs := make([]kmsg.CreateTopicsRequestTopicConfig, 0, len(values))
+	for name, value := range values {
+		cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+		cfg.Name = name
+		cfg.Value = value
+		configs = append(configs, cfg)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+
+	return configs
+}
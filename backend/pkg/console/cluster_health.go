@@ -0,0 +1,249 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+)
+
+// ClusterHealth aggregates the replica state of all partitions in the cluster.
+type ClusterHealth struct {
+	IsHealthy bool `json:"isHealthy"`
+
+	// UnderReplicatedPartitions have less in sync replicas than replicas.
+	UnderReplicatedPartitions []ClusterHealthPartition `json:"underReplicatedPartitions"`
+
+	// UnderMinISRPartitions have less in sync replicas than the topic's min.insync.replicas, producers with
+	// acks=all can not write to these partitions.
+	UnderMinISRPartitions []ClusterHealthPartition `json:"underMinIsrPartitions"`
+
+	// OfflinePartitions have no replica on a live broker.
+	OfflinePartitions []ClusterHealthPartition `json:"offlinePartitions"`
+
+	// LeaderlessPartitions have no leader or their leader is not a live broker.
+	LeaderlessPartitions []ClusterHealthPartition `json:"leaderlessPartitions"`
+
+	Brokers []ClusterHealthBroker `json:"brokers"`
+
+	// MinISRError is set if the min.insync.replicas configs could not be described. Partitions under min ISR
+	// can not be reported in that case.
+	MinISRError string `json:"minIsrError,omitempty"`
+}
+
+type ClusterHealthPartition struct {
+	TopicName       string  `json:"topicName"`
+	PartitionID     int32   `json:"partitionId"`
+	Leader          int32   `json:"leader"`
+	Replicas        []int32 `json:"replicas"`
+	InSyncReplicas  []int32 `json:"inSyncReplicas"`
+	OfflineReplicas []int32 `json:"offlineReplicas"`
+
+	// MinInSyncReplicas is the topic's min.insync.replicas config, it is -1 if unknown.
+	MinInSyncReplicas int `json:"minInSyncReplicas"`
+}
+
+// ClusterHealthBroker summarizes the replicas that are hosted on a broker. Brokers which are not alive are only
+// known if they are still assigned as replica.
+type ClusterHealthBroker struct {
+	BrokerID     int32 `json:"brokerId"`
+	IsAlive      bool  `json:"isAlive"`
+	LeaderCount  int   `json:"leaderCount"`
+	ReplicaCount int   `json:"replicaCount"`
+
+	// OutOfSyncReplicaCount is the number of replicas on this broker that are not in sync.
+	OutOfSyncReplicaCount int `json:"outOfSyncReplicaCount"`
+
+	// OfflineReplicaCount is the number of replicas on this broker that are offline (e.g. due to a failed log dir).
+	OfflineReplicaCount int `json:"offlineReplicaCount"`
+}
+
+// GetClusterHealth reports under replicated, under min ISR, offline and leaderless partitions along with a per
+// broker summary.
+func (s *Service) GetClusterHealth(ctx context.Context) (*ClusterHealth, *rest.Error) {
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get metadata from cluster: %v", err.Error()),
+		}
+	}
+
+	topicNames := make([]string, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if kerr.ErrorForCode(topic.ErrorCode) == nil {
+			topicNames = append(topicNames, *topic.Topic)
+		}
+	}
+
+	var minISRByTopic map[string]int
+	var minISRErr error
+	if len(topicNames) > 0 {
+		minISRByTopic, minISRErr = s.getMinInSyncReplicas(ctx, topicNames)
+		if minISRErr != nil {
+			s.logger.Warn("failed to describe min.insync.replicas of topics", zap.Error(minISRErr))
+		}
+	}
+
+	health := computeClusterHealth(metadata, minISRByTopic)
+	if minISRErr != nil {
+		health.MinISRError = fmt.Sprintf("Failed to describe min.insync.replicas: %v", minISRErr.Error())
+	}
+
+	return health, nil
+}
+
+// evaluateClusterHealth evaluates the cluster health for the cluster health exporter.
+func (s *Service) evaluateClusterHealth(ctx context.Context) (*ClusterHealth, error) {
+	health, restErr := s.GetClusterHealth(ctx)
+	if restErr != nil {
+		return nil, restErr.Err
+	}
+	return health, nil
+}
+
+// getMinInSyncReplicas returns the min.insync.replicas config of the given topics.
+func (s *Service) getMinInSyncReplicas(ctx context.Context, topicNames []string) (map[string]int, error) {
+	configs, err := s.GetTopicsConfigs(ctx, topicNames, []string{"min.insync.replicas"})
+	if err != nil {
+		return nil, err
+	}
+
+	minISRByTopic := make(map[string]int, len(configs))
+	for topicName, topicConfig := range configs {
+		if topicConfig.Error != nil {
+			continue
+		}
+		entry := topicConfig.GetConfigEntryByName("min.insync.replicas")
+		if entry == nil || entry.Value == nil {
+			continue
+		}
+		minISR, err := strconv.Atoi(*entry.Value)
+		if err != nil {
+			continue
+		}
+		minISRByTopic[topicName] = minISR
+	}
+
+	return minISRByTopic, nil
+}
+
+// computeClusterHealth evaluates the partition states of the given metadata. Partitions of topics that are
+// missing in minISRByTopic are not checked for min ISR.
+func computeClusterHealth(metadata *kmsg.MetadataResponse, minISRByTopic map[string]int) *ClusterHealth {
+	health := &ClusterHealth{
+		UnderReplicatedPartitions: make([]ClusterHealthPartition, 0),
+		UnderMinISRPartitions:     make([]ClusterHealthPartition, 0),
+		OfflinePartitions:         make([]ClusterHealthPartition, 0),
+		LeaderlessPartitions:      make([]ClusterHealthPartition, 0),
+		Brokers:                   make([]ClusterHealthBroker, 0),
+	}
+
+	brokers := make(map[int32]*ClusterHealthBroker)
+	getBroker := func(brokerID int32) *ClusterHealthBroker {
+		broker, exists := brokers[brokerID]
+		if !exists {
+			broker = &ClusterHealthBroker{BrokerID: brokerID}
+			brokers[brokerID] = broker
+		}
+		return broker
+	}
+	for _, broker := range metadata.Brokers {
+		getBroker(broker.NodeID).IsAlive = true
+	}
+
+	for _, topic := range metadata.Topics {
+		if kerr.ErrorForCode(topic.ErrorCode) != nil {
+			continue
+		}
+		topicName := *topic.Topic
+		minISR, hasMinISR := minISRByTopic[topicName]
+		for _, partition := range topic.Partitions {
+			p := ClusterHealthPartition{
+				TopicName:         topicName,
+				PartitionID:       partition.Partition,
+				Leader:            partition.Leader,
+				Replicas:          partition.Replicas,
+				InSyncReplicas:    partition.ISR,
+				OfflineReplicas:   partition.OfflineReplicas,
+				MinInSyncReplicas: -1,
+			}
+			if hasMinISR {
+				p.MinInSyncReplicas = minISR
+			}
+
+			hasLiveReplica := false
+			for _, replicaID := range partition.Replicas {
+				broker := getBroker(replicaID)
+				broker.ReplicaCount++
+				if !containsInt32(partition.ISR, replicaID) {
+					broker.OutOfSyncReplicaCount++
+				}
+				isOffline := containsInt32(partition.OfflineReplicas, replicaID)
+				if isOffline {
+					broker.OfflineReplicaCount++
+				}
+				if broker.IsAlive && !isOffline {
+					hasLiveReplica = true
+				}
+			}
+			leader, leaderExists := brokers[partition.Leader]
+			isLeaderless := partition.Leader < 0 || !leaderExists || !leader.IsAlive
+			if !isLeaderless {
+				leader.LeaderCount++
+			}
+
+			if len(partition.ISR) < len(partition.Replicas) {
+				health.UnderReplicatedPartitions = append(health.UnderReplicatedPartitions, p)
+			}
+			if hasMinISR && len(partition.ISR) < minISR {
+				health.UnderMinISRPartitions = append(health.UnderMinISRPartitions, p)
+			}
+			if !hasLiveReplica {
+				health.OfflinePartitions = append(health.OfflinePartitions, p)
+			}
+			if isLeaderless {
+				health.LeaderlessPartitions = append(health.LeaderlessPartitions, p)
+			}
+		}
+	}
+
+	for _, broker := range brokers {
+		health.Brokers = append(health.Brokers, *broker)
+	}
+	sort.Slice(health.Brokers, func(i, j int) bool { return health.Brokers[i].BrokerID < health.Brokers[j].BrokerID })
+	for _, partitions := range [][]ClusterHealthPartition{health.UnderReplicatedPartitions, health.UnderMinISRPartitions,
+		health.OfflinePartitions, health.LeaderlessPartitions} {
+		sortClusterHealthPartitions(partitions)
+	}
+
+	health.IsHealthy = len(health.UnderReplicatedPartitions) == 0 && len(health.UnderMinISRPartitions) == 0 &&
+		len(health.OfflinePartitions) == 0 && len(health.LeaderlessPartitions) == 0
+
+	return health
+}
+
+func sortClusterHealthPartitions(partitions []ClusterHealthPartition) {
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].TopicName != partitions[j].TopicName {
+			return partitions[i].TopicName < partitions[j].TopicName
+		}
+		return partitions[i].PartitionID < partitions[j].PartitionID
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// clusterHealthExporter periodically evaluates the cluster health and exports the number of unhealthy partitions
+// as Prometheus metrics.
+type clusterHealthExporter struct {
+	cfg    ConfigClusterHealth
+	logger *zap.Logger
+
+	// getHealth evaluates the cluster health.
+	getHealth func(ctx context.Context) (*ClusterHealth, error)
+
+	underReplicatedPartitions prometheus.Gauge
+	underMinISRPartitions     prometheus.Gauge
+	offlinePartitions         prometheus.Gauge
+	leaderlessPartitions      prometheus.Gauge
+	brokerOutOfSyncReplicas   *prometheus.GaugeVec
+	brokerOfflineReplicas     *prometheus.GaugeVec
+	lastSuccessTimestamp      prometheus.Gauge
+}
+
+func newClusterHealthExporter(cfg ConfigClusterHealth, logger *zap.Logger, metricsNamespace string, getHealth func(ctx context.Context) (*ClusterHealth, error)) *clusterHealthExporter {
+	return &clusterHealthExporter{
+		cfg:       cfg,
+		logger:    logger.With(zap.String("source", "cluster_health_exporter")),
+		getHealth: getHealth,
+
+		underReplicatedPartitions: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "under_replicated_partitions",
+			Help:      "Number of partitions with less in sync replicas than replicas",
+		}),
+		underMinISRPartitions: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "under_min_isr_partitions",
+			Help:      "Number of partitions with less in sync replicas than the topic's min.insync.replicas",
+		}),
+		offlinePartitions: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "offline_partitions",
+			Help:      "Number of partitions without any replica on a live broker",
+		}),
+		leaderlessPartitions: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "leaderless_partitions",
+			Help:      "Number of partitions without a leader on a live broker",
+		}),
+		brokerOutOfSyncReplicas: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "broker_out_of_sync_replicas",
+			Help:      "Number of replicas on a broker that are not in sync",
+		}, []string{"broker_id"}),
+		brokerOfflineReplicas: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "broker_offline_replicas",
+			Help:      "Number of replicas on a broker that are offline",
+		}, []string{"broker_id"}),
+		lastSuccessTimestamp: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "cluster",
+			Name:      "health_last_success_timestamp",
+			Help:      "Unix timestamp (in seconds) of the last successful cluster health evaluation",
+		}),
+	}
+}
+
+// Start evaluates the cluster health in the background.
+func (c *clusterHealthExporter) Start() {
+	go c.run()
+}
+
+func (c *clusterHealthExporter) run() {
+	// Stop evaluating when we receive a signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	c.evaluate()
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			c.logger.Info("stopped cluster health exporter", zap.String("reason", "received signal"))
+			return
+		case <-ticker.C:
+			c.evaluate()
+		}
+	}
+}
+
+func (c *clusterHealthExporter) evaluate() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Interval)
+	defer cancel()
+
+	// The metrics keep their last values if the evaluation fails, the last success timestamp tells how old they are
+	health, err := c.getHealth(ctx)
+	if err != nil {
+		c.logger.Warn("failed to evaluate cluster health", zap.Error(err))
+		return
+	}
+	c.updateMetrics(health)
+	c.lastSuccessTimestamp.SetToCurrentTime()
+}
+
+func (c *clusterHealthExporter) updateMetrics(health *ClusterHealth) {
+	c.underReplicatedPartitions.Set(float64(len(health.UnderReplicatedPartitions)))
+	c.offlinePartitions.Set(float64(len(health.OfflinePartitions)))
+	c.leaderlessPartitions.Set(float64(len(health.LeaderlessPartitions)))
+	// Keep the last known value if min.insync.replicas could not be described
+	if health.MinISRError == "" {
+		c.underMinISRPartitions.Set(float64(len(health.UnderMinISRPartitions)))
+	}
+
+	c.brokerOutOfSyncReplicas.Reset()
+	c.brokerOfflineReplicas.Reset()
+	for _, broker := range health.Brokers {
+		brokerID := strconv.Itoa(int(broker.BrokerID))
+		c.brokerOutOfSyncReplicas.WithLabelValues(brokerID).Set(float64(broker.OutOfSyncReplicaCount))
+		c.brokerOfflineReplicas.WithLabelValues(brokerID).Set(float64(broker.OfflineReplicaCount))
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestComputeClusterHealth(t *testing.T) {
+	metadata := kmsg.NewMetadataResponse()
+	for _, brokerID := range []int32{1, 2, 3} {
+		broker := kmsg.NewMetadataResponseBroker()
+		broker.NodeID = brokerID
+		metadata.Brokers = append(metadata.Brokers, broker)
+	}
+	topic := kmsg.NewMetadataResponseTopic()
+	topic.Topic = kmsg.StringPtr("orders")
+	topic.Partitions = []kmsg.MetadataResponseTopicPartition{
+		{Partition: 0, Leader: 1, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2, 3}},
+		{Partition: 1, Leader: 2, Replicas: []int32{2, 3, 1}, ISR: []int32{2}, OfflineReplicas: []int32{3}},
+		{Partition: 2, Leader: -1, Replicas: []int32{4}, ISR: []int32{}},
+	}
+	metadata.Topics = append(metadata.Topics, topic)
+
+	health := computeClusterHealth(&metadata, map[string]int{"orders": 2})
+	assert.False(t, health.IsHealthy)
+
+	partitionIDs := func(partitions []ClusterHealthPartition) []int32 {
+		ids := make([]int32, len(partitions))
+		for i, p := range partitions {
+			ids[i] = p.PartitionID
+		}
+		return ids
+	}
+	assert.Equal(t, []int32{1, 2}, partitionIDs(health.UnderReplicatedPartitions))
+	assert.Equal(t, []int32{1, 2}, partitionIDs(health.UnderMinISRPartitions))
+	assert.Equal(t, []int32{2}, partitionIDs(health.OfflinePartitions))
+	assert.Equal(t, []int32{2}, partitionIDs(health.LeaderlessPartitions))
+
+	// Broker 4 is not alive, but still known because it is assigned as replica
+	require.Len(t, health.Brokers, 4)
+	assert.Equal(t, ClusterHealthBroker{BrokerID: 1, IsAlive: true, LeaderCount: 1, ReplicaCount: 2, OutOfSyncReplicaCount: 1}, health.Brokers[0])
+	assert.Equal(t, ClusterHealthBroker{BrokerID: 3, IsAlive: true, ReplicaCount: 2, OutOfSyncReplicaCount: 1, OfflineReplicaCount: 1}, health.Brokers[2])
+	assert.Equal(t, ClusterHealthBroker{BrokerID: 4, ReplicaCount: 1, OutOfSyncReplicaCount: 1}, health.Brokers[3])
+
+	// Without min.insync.replicas configs only the other checks apply
+	health = computeClusterHealth(&metadata, nil)
+	assert.Empty(t, health.UnderMinISRPartitions)
+}
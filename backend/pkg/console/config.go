@@ -16,14 +16,27 @@ import (
 
 type Config struct {
 	TopicDocumentation ConfigTopicDocumentation `yaml:"topicDocumentation"`
+	LagHistory         ConfigLagHistory         `yaml:"lagHistory"`
+	GroupEvents        ConfigGroupEvents        `yaml:"groupEvents"`
+	TopicTemplates     []ConfigTopicTemplate    `yaml:"topicTemplates"`
+	TopicManagement    ConfigTopicManagement    `yaml:"topicManagement"`
+	TopicActivity      ConfigTopicActivity      `yaml:"topicActivity"`
+	TopicConfigPolicy  ConfigTopicConfigPolicy  `yaml:"topicConfigPolicy"`
+	ClusterHealth      ConfigClusterHealth      `yaml:"clusterHealth"`
 }
 
 func (c *Config) SetDefaults() {
 	c.TopicDocumentation.SetDefaults()
+	c.LagHistory.SetDefaults()
+	c.GroupEvents.SetDefaults()
+	c.TopicManagement.SetDefaults()
+	c.TopicActivity.SetDefaults()
+	c.ClusterHealth.SetDefaults()
 }
 
 func (c *Config) RegisterFlags(f *flag.FlagSet) {
 	c.TopicDocumentation.RegisterFlags(f)
+	c.TopicManagement.RegisterFlags(f)
 }
 
 func (c *Config) Validate() error {
@@ -31,6 +44,41 @@ func (c *Config) Validate() error {
 	if err != nil {
 		return fmt.Errorf("failed to validate topic documentation config: %w", err)
 	}
+	err = c.LagHistory.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate lag history config: %w", err)
+	}
+	err = c.GroupEvents.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate group events config: %w", err)
+	}
+	err = c.TopicManagement.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate topic management config: %w", err)
+	}
+	err = c.TopicActivity.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate topic activity config: %w", err)
+	}
+	err = c.TopicConfigPolicy.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate topic config policy: %w", err)
+	}
+	err = c.ClusterHealth.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate cluster health config: %w", err)
+	}
+	templateNames := make(map[string]struct{}, len(c.TopicTemplates))
+	for i, template := range c.TopicTemplates {
+		err = template.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate topic template at index %d: %w", i, err)
+		}
+		if _, exists := templateNames[template.Name]; exists {
+			return fmt.Errorf("topic template name '%v' is used more than once", template.Name)
+		}
+		templateNames[template.Name] = struct{}{}
+	}
 
 	return nil
 }
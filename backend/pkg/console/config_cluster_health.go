@@ -0,0 +1,39 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConfigClusterHealth configures the background exporter that periodically evaluates the cluster health and
+// exports the number of unhealthy partitions as Prometheus metrics.
+type ConfigClusterHealth struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval at which the cluster health is evaluated.
+	Interval time.Duration `yaml:"interval"`
+}
+
+func (c *ConfigClusterHealth) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("cluster health interval must be at least 1s")
+	}
+
+	return nil
+}
+
+func (c *ConfigClusterHealth) SetDefaults() {
+	c.Interval = time.Minute
+}
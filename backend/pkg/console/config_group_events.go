@@ -0,0 +1,47 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConfigGroupEvents configures the background watcher that tracks membership and state changes of all consumer
+// groups.
+type ConfigGroupEvents struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval at which all consumer groups are described. Rebalances that start and complete within one interval
+	// can only be detected by the resulting membership or assignment changes.
+	Interval time.Duration `yaml:"interval"`
+
+	// MaxEvents is the number of events that are retained per group. Older events are dropped.
+	MaxEvents int `yaml:"maxEvents"`
+}
+
+func (c *ConfigGroupEvents) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("group events interval must be at least 1s")
+	}
+	if c.MaxEvents < 1 {
+		return fmt.Errorf("group events must retain at least one event")
+	}
+
+	return nil
+}
+
+func (c *ConfigGroupEvents) SetDefaults() {
+	c.Interval = 10 * time.Second
+	c.MaxEvents = 500
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConfigLagHistory configures the background collector that periodically records the lag of all consumer groups.
+type ConfigLagHistory struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval at which the lag of all consumer groups is recorded.
+	Interval time.Duration `yaml:"interval"`
+
+	// MaxSamples is the number of samples that are retained per partition. Older samples are overwritten.
+	MaxSamples int `yaml:"maxSamples"`
+
+	// PersistencePath is an optional file path. If set, the recorded history is written to this file after each
+	// collection and restored on startup.
+	PersistencePath string `yaml:"persistencePath"`
+}
+
+func (c *ConfigLagHistory) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("lag history interval must be at least 1s")
+	}
+	if c.MaxSamples < 2 {
+		return fmt.Errorf("lag history must retain at least 2 samples, so that lag rates can be estimated")
+	}
+
+	return nil
+}
+
+func (c *ConfigLagHistory) SetDefaults() {
+	c.Interval = 30 * time.Second
+	c.MaxSamples = 240 // 2h at the default interval
+}
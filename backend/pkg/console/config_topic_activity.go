@@ -0,0 +1,46 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConfigTopicActivity configures the background sampler that periodically records the high water marks of all
+// topics, so that the producer activity of each topic can be reported.
+type ConfigTopicActivity struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval at which the high water marks of all topics are recorded.
+	Interval time.Duration `yaml:"interval"`
+
+	// MaxSamples is the number of samples that are retained per topic. Older samples are dropped.
+	MaxSamples int `yaml:"maxSamples"`
+}
+
+func (c *ConfigTopicActivity) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("topic activity interval must be at least 1s")
+	}
+	if c.MaxSamples < 2 {
+		return fmt.Errorf("topic activity must retain at least 2 samples, so that write rates can be calculated")
+	}
+
+	return nil
+}
+
+func (c *ConfigTopicActivity) SetDefaults() {
+	c.Interval = 5 * time.Minute
+	c.MaxSamples = 288 // 24h at the default interval
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+)
+
+// ConfigTopicConfigPolicy contains the rules that the configs of all topics are checked against.
+type ConfigTopicConfigPolicy struct {
+	Rules []ConfigTopicConfigRule `yaml:"rules"`
+}
+
+// ConfigTopicConfigRule restricts the value of a single topic config for all matching topics.
+type ConfigTopicConfigRule struct {
+	// Name describes the rule, it is reported along with each violation.
+	Name string `yaml:"name"`
+
+	// TopicNames is an optional list of topic names this rule shall be limited to. Names can be provided
+	// as regex string (e. g. "/.*-changelog/") or as plain topic name. If empty the rule applies to all topics.
+	TopicNames []string `yaml:"topicNames"`
+
+	// ReplicationFactor optionally limits the rule to topics with the given replication factor.
+	ReplicationFactor int16 `yaml:"replicationFactor"`
+
+	// ConfigName is the name of the topic config that is checked (e.g. "retention.ms").
+	ConfigName string `yaml:"configName"`
+
+	// MinValue and MaxValue are inclusive bounds for numeric configs. Negative values (e.g. retention.ms=-1)
+	// are considered unlimited, they always satisfy MinValue and always violate MaxValue.
+	MinValue *int64 `yaml:"minValue"`
+	MaxValue *int64 `yaml:"maxValue"`
+
+	// AllowedValues is a list of values that the config must be set to (e.g. ["compact"]).
+	AllowedValues []string `yaml:"allowedValues"`
+
+	// SuggestedValue is the value that is suggested to fix a violation. Defaults to the violated bound or the first
+	// allowed value.
+	SuggestedValue string `yaml:"suggestedValue"`
+}
+
+func (c *ConfigTopicConfigPolicy) Validate() error {
+	names := make(map[string]struct{}, len(c.Rules))
+	for i, rule := range c.Rules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate topic config rule with index '%d': %w", i, err)
+		}
+		if _, exists := names[rule.Name]; exists {
+			return fmt.Errorf("topic config rule name '%v' is used more than once", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *ConfigTopicConfigRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name must be set")
+	}
+	if r.ConfigName == "" {
+		return fmt.Errorf("config name must be set")
+	}
+	if r.MinValue == nil && r.MaxValue == nil && len(r.AllowedValues) == 0 {
+		return fmt.Errorf("at least one of minValue, maxValue or allowedValues must be set")
+	}
+	if r.MinValue != nil && r.MaxValue != nil && *r.MinValue > *r.MaxValue {
+		return fmt.Errorf("minValue must not be greater than maxValue")
+	}
+	if r.ReplicationFactor < 0 {
+		return fmt.Errorf("replication factor must not be negative")
+	}
+	for _, topic := range r.TopicNames {
+		_, err := regexutil.Compile(topic)
+		if err != nil {
+			return fmt.Errorf("topic name '%v' is not valid regex", topic)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/cloudhut/kowl/backend/pkg/git"
+)
+
+// ConfigTopicManagement configures the declarative topic management. The desired topics can optionally be
+// pulled from a git repository.
+type ConfigTopicManagement struct {
+	Git git.Config `yaml:"git"`
+
+	// Filename of the desired state document (YAML or JSON) in the git repository, e.g. "topics.yaml".
+	Filename string `yaml:"filename"`
+}
+
+func (c *ConfigTopicManagement) RegisterFlags(f *flag.FlagSet) {
+	c.Git.RegisterFlagsWithPrefix(f, "owl.topic-management.")
+}
+
+func (c *ConfigTopicManagement) Validate() error {
+	if !c.Git.Enabled {
+		return nil
+	}
+	if c.Filename == "" {
+		return fmt.Errorf("git is enabled for topic management, but no filename is set")
+	}
+
+	return c.Git.Validate()
+}
+
+func (c *ConfigTopicManagement) SetDefaults() {
+	c.Git.SetDefaults()
+	c.Git.AllowedFileExtensions = []string{"yaml", "yml", "json"}
+	c.Filename = "topics.yaml"
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+)
+
+// ConfigTopicTemplate is a named set of topic settings which can be referenced when creating a topic.
+type ConfigTopicTemplate struct {
+	Name string `yaml:"name" json:"name"`
+
+	// PartitionCount and ReplicationFactor are used if they are not set in the create topic request. If they are
+	// not set in the template either, the broker defaults apply.
+	PartitionCount    int32 `yaml:"partitionCount" json:"partitionCount"`
+	ReplicationFactor int16 `yaml:"replicationFactor" json:"replicationFactor"`
+
+	// Configs are topic configs (e.g. cleanup.policy) which can be overridden by the create topic request.
+	Configs map[string]string `yaml:"configs" json:"configs"`
+}
+
+func (c *ConfigTopicTemplate) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("topic template name must be set")
+	}
+	if c.PartitionCount < -1 {
+		return fmt.Errorf("partition count of topic template '%v' must be -1 (broker default) or more", c.Name)
+	}
+	if c.ReplicationFactor < -1 {
+		return fmt.Errorf("replication factor of topic template '%v' must be -1 (broker default) or more", c.Name)
+	}
+
+	return nil
+}
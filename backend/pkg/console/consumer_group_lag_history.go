@@ -0,0 +1,104 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ConsumerGroupLagHistory is the recorded lag of a consumer group over time.
+type ConsumerGroupLagHistory struct {
+	GroupID string `json:"groupId"`
+	// IntervalMs is the interval at which lag samples are recorded.
+	IntervalMs int64             `json:"intervalMs"`
+	Topics     []TopicLagHistory `json:"topics"`
+}
+
+// TopicLagHistory contains the summed lag samples of all partitions of a topic, along with the samples of each
+// partition.
+type TopicLagHistory struct {
+	TopicName  string                `json:"topicName"`
+	Samples    []LagSample           `json:"samples"`
+	Estimate   LagEstimate           `json:"estimate"`
+	Partitions []PartitionLagHistory `json:"partitions"`
+}
+
+// PartitionLagHistory contains the lag samples of a single partition.
+type PartitionLagHistory struct {
+	PartitionID int32       `json:"partitionId"`
+	Samples     []LagSample `json:"samples"`
+	Estimate    LagEstimate `json:"estimate"`
+}
+
+// GetConsumerGroupLagHistory returns the recorded lag of a consumer group. If topicName is set, only the history
+// of this topic is returned. If window is greater than zero, only samples within this window are returned and
+// considered for the estimates.
+func (s *Service) GetConsumerGroupLagHistory(groupID string, topicName string, window time.Duration) (*ConsumerGroupLagHistory, error) {
+	if s.lagCollector == nil {
+		return nil, ErrLagHistoryNotEnabled
+	}
+
+	var since int64
+	if window > 0 {
+		since = unixMillis(time.Now().Add(-window))
+	}
+
+	res := &ConsumerGroupLagHistory{
+		GroupID:    groupID,
+		IntervalMs: int64(s.lagCollector.cfg.Interval / time.Millisecond),
+		Topics:     make([]TopicLagHistory, 0),
+	}
+	for topic, partitions := range s.lagCollector.history.groupSamples(groupID) {
+		if topicName != "" && topic != topicName {
+			continue
+		}
+
+		topicHistory := TopicLagHistory{
+			TopicName:  topic,
+			Partitions: make([]PartitionLagHistory, 0, len(partitions)),
+		}
+		for partitionID, samples := range partitions {
+			samples = samplesSince(samples, since)
+			partitions[partitionID] = samples
+			topicHistory.Partitions = append(topicHistory.Partitions, PartitionLagHistory{
+				PartitionID: partitionID,
+				Samples:     samples,
+				Estimate:    estimateLag(samples),
+			})
+		}
+		sort.Slice(topicHistory.Partitions, func(i, j int) bool {
+			return topicHistory.Partitions[i].PartitionID < topicHistory.Partitions[j].PartitionID
+		})
+		topicHistory.Samples = sumLagSamples(partitions)
+		topicHistory.Estimate = estimateLag(topicHistory.Samples)
+
+		res.Topics = append(res.Topics, topicHistory)
+	}
+	sort.Slice(res.Topics, func(i, j int) bool { return res.Topics[i].TopicName < res.Topics[j].TopicName })
+
+	return res, nil
+}
+
+// getAllConsumerGroupLags returns the lags of all consumer groups, where the group id is the key.
+func (s *Service) getAllConsumerGroupLags(ctx context.Context) (map[string][]GroupTopicOffsets, error) {
+	groups, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	groupIDs := groups.GetGroupIDs()
+	if len(groupIDs) == 0 {
+		return map[string][]GroupTopicOffsets{}, nil
+	}
+
+	return s.getConsumerGroupOffsets(ctx, groupIDs)
+}
@@ -11,6 +11,7 @@ package console
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -43,6 +44,14 @@ type GroupMemberDescription struct {
 	ClientID    string                  `json:"clientId"`
 	ClientHost  string                  `json:"clientHost"`
 	Assignments []GroupMemberAssignment `json:"assignments"`
+
+	// ConnectAssignment is set for Kafka Connect workers (protocol type "connect").
+	ConnectAssignment *GroupMemberConnectAssignment `json:"connectAssignment,omitempty"`
+
+	// DecodedMetadata and DecodedAssignment are set for other protocol types that use JSON encoded metadata and
+	// assignments, such as the leader election of Schema Registry (protocol type "sr").
+	DecodedMetadata   interface{} `json:"decodedMetadata,omitempty"`
+	DecodedAssignment interface{} `json:"decodedAssignment,omitempty"`
 }
 
 // GroupMemberAssignment represents a partition assignment for a group member
@@ -51,6 +60,28 @@ type GroupMemberAssignment struct {
 	PartitionIDs []int32 `json:"partitionIds"`
 }
 
+// GroupMemberConnectAssignment describes which connectors and tasks are run by a Kafka Connect worker.
+type GroupMemberConnectAssignment struct {
+	WorkerURL    string `json:"workerUrl"`
+	LeaderID     string `json:"leaderId"`
+	LeaderURL    string `json:"leaderUrl"`
+	ConfigOffset int64  `json:"configOffset"`
+	Error        string `json:"error,omitempty"`
+
+	Connectors []string                `json:"connectors"`
+	Tasks      []ConnectTaskAssignment `json:"tasks"`
+
+	// RevokedConnectors and RevokedTasks are only set during incremental cooperative rebalances.
+	RevokedConnectors []string                `json:"revokedConnectors,omitempty"`
+	RevokedTasks      []ConnectTaskAssignment `json:"revokedTasks,omitempty"`
+}
+
+// ConnectTaskAssignment is a single connector task.
+type ConnectTaskAssignment struct {
+	Connector string `json:"connector"`
+	TaskID    int32  `json:"taskId"`
+}
+
 // GetConsumerGroupsOverview returns a ConsumerGroupOverview for all available consumer groups
 // Pass nil for groupIDs if you want to fetch all available groups.
 func (s *Service) GetConsumerGroupsOverview(ctx context.Context, groupIDs []string) ([]ConsumerGroupOverview, *rest.Error) {
@@ -130,7 +161,7 @@ func (s *Service) convertKgoGroupDescriptions(describedGroups *kafka.DescribeCon
 				continue
 			}
 
-			members, err := s.convertGroupMembers(d.Members)
+			members, err := s.convertGroupMembers(d.ProtocolType, d.Members)
 			if err != nil {
 				s.logger.Warn("failed to convert group members from described groups to kowl result type",
 					zap.Error(err),
@@ -153,51 +184,139 @@ func (s *Service) convertKgoGroupDescriptions(describedGroups *kafka.DescribeCon
 	return result
 }
 
-func (s *Service) convertGroupMembers(members []kmsg.DescribeGroupsResponseGroupMember) ([]GroupMemberDescription, error) {
+func (s *Service) convertGroupMembers(protocolType string, members []kmsg.DescribeGroupsResponseGroupMember) ([]GroupMemberDescription, error) {
 	response := make([]GroupMemberDescription, 0)
 
 	for _, m := range members {
-		// MemberAssignments is a byte array which will be set by kafka clients. All clients which use protocol
-		// type "consumer" are supposed to follow a schema which we will try to parse below. If the protocol type
-		// is different we won't even try to deserialize the byte array as this will likely fail.
-		//
-		// Confluent's Schema registry for instance does not follow that schema and does therefore set a different
-		// protocol type.
+		// MemberMetadata and MemberAssignments are byte arrays which will be set by kafka clients. The schema
+		// depends on the protocol type: All clients which use protocol type "consumer" are supposed to follow the
+		// consumer schema, Kafka Connect workers use protocol type "connect". Other protocol types such as
+		// Confluent's Schema registry ("sr") use JSON.
 		// see: https://cwiki.apache.org/confluence/display/KAFKA/A+Guide+To+The+Kafka+Protocol
+		member := GroupMemberDescription{
+			ID:          m.MemberID,
+			ClientID:    m.ClientID,
+			ClientHost:  m.ClientHost,
+			Assignments: make([]GroupMemberAssignment, 0),
+		}
 
-		// Try to decode Group member assignments
-		convertedAssignments := make([]GroupMemberAssignment, 0)
-		memberAssignments := kmsg.GroupMemberAssignment{}
-		err := memberAssignments.ReadFrom(m.MemberAssignment)
-		if err != nil {
-			s.logger.Debug("failed to decode member assignments", zap.String("client_id", m.ClientID), zap.Error(err))
-		} else {
-			for _, topic := range memberAssignments.Topics {
-				partitionIDs := topic.Partitions
-				if partitionIDs == nil {
-					partitionIDs = make([]int32, 0)
-				}
-				sort.Slice(partitionIDs, func(i, j int) bool { return partitionIDs[i] < partitionIDs[j] })
-				a := GroupMemberAssignment{
-					TopicName:    topic.Topic,
-					PartitionIDs: partitionIDs,
-				}
-				convertedAssignments = append(convertedAssignments, a)
+		switch protocolType {
+		case "consumer":
+			member.Assignments = s.convertConsumerAssignments(m)
+		case "connect":
+			member.ConnectAssignment = s.convertConnectAssignment(m)
+		default:
+			// Unknown protocol types may still use the consumer schema, hence we try that if they are not JSON
+			var metadata, assignment interface{}
+			if json.Unmarshal(m.ProtocolMetadata, &metadata) == nil {
+				member.DecodedMetadata = metadata
+			}
+			if json.Unmarshal(m.MemberAssignment, &assignment) == nil {
+				member.DecodedAssignment = assignment
+			}
+			if member.DecodedMetadata == nil && member.DecodedAssignment == nil {
+				member.Assignments = s.convertConsumerAssignments(m)
 			}
 		}
 
-		// Sort all assignments by topicname
-		sort.Slice(convertedAssignments, func(i, j int) bool {
-			return convertedAssignments[i].TopicName < convertedAssignments[j].TopicName
-		})
-
-		response = append(response, GroupMemberDescription{
-			ID:          m.MemberID,
-			ClientID:    m.ClientID,
-			ClientHost:  m.ClientHost,
-			Assignments: convertedAssignments,
-		})
+		response = append(response, member)
 	}
 
 	return response, nil
 }
+
+// convertConsumerAssignments decodes the member assignment of the "consumer" protocol type.
+func (s *Service) convertConsumerAssignments(m kmsg.DescribeGroupsResponseGroupMember) []GroupMemberAssignment {
+	convertedAssignments := make([]GroupMemberAssignment, 0)
+	memberAssignments := kmsg.GroupMemberAssignment{}
+	err := memberAssignments.ReadFrom(m.MemberAssignment)
+	if err != nil {
+		s.logger.Debug("failed to decode member assignments", zap.String("client_id", m.ClientID), zap.Error(err))
+		return convertedAssignments
+	}
+
+	for _, topic := range memberAssignments.Topics {
+		partitionIDs := topic.Partitions
+		if partitionIDs == nil {
+			partitionIDs = make([]int32, 0)
+		}
+		sort.Slice(partitionIDs, func(i, j int) bool { return partitionIDs[i] < partitionIDs[j] })
+		a := GroupMemberAssignment{
+			TopicName:    topic.Topic,
+			PartitionIDs: partitionIDs,
+		}
+		convertedAssignments = append(convertedAssignments, a)
+	}
+
+	// Sort all assignments by topicname
+	sort.Slice(convertedAssignments, func(i, j int) bool {
+		return convertedAssignments[i].TopicName < convertedAssignments[j].TopicName
+	})
+
+	return convertedAssignments
+}
+
+// convertConnectAssignment decodes the worker URL and the assigned connectors and tasks of a Kafka Connect worker.
+// Members that have not yet received an assignment only report their worker URL.
+func (s *Service) convertConnectAssignment(m kmsg.DescribeGroupsResponseGroupMember) *GroupMemberConnectAssignment {
+	res := &GroupMemberConnectAssignment{
+		Connectors: make([]string, 0),
+		Tasks:      make([]ConnectTaskAssignment, 0),
+	}
+
+	metadata, err := kafka.DecodeConnectMemberMetadata(m.ProtocolMetadata)
+	if err != nil {
+		s.logger.Debug("failed to decode connect member metadata", zap.String("client_id", m.ClientID), zap.Error(err))
+	} else {
+		res.WorkerURL = metadata.URL
+	}
+
+	if len(m.MemberAssignment) == 0 {
+		return res
+	}
+	assignment, err := kafka.DecodeConnectMemberAssignment(m.MemberAssignment)
+	if err != nil {
+		s.logger.Debug("failed to decode connect member assignment", zap.String("client_id", m.ClientID), zap.Error(err))
+		return res
+	}
+	res.LeaderID = assignment.Leader
+	res.LeaderURL = assignment.LeaderURL
+	res.ConfigOffset = assignment.ConfigOffset
+	switch assignment.Error {
+	case 0:
+	case 1:
+		res.Error = "CONFIG_MISMATCH"
+	default:
+		res.Error = fmt.Sprintf("UNKNOWN_ERROR (%d)", assignment.Error)
+	}
+	res.Connectors, res.Tasks = convertConnectorTaskAssignments(assignment.Assigned)
+	if len(assignment.Revoked) > 0 {
+		res.RevokedConnectors, res.RevokedTasks = convertConnectorTaskAssignments(assignment.Revoked)
+	}
+
+	return res
+}
+
+// convertConnectorTaskAssignments splits the assignments into connector instances and tasks, both sorted.
+func convertConnectorTaskAssignments(assignments []kafka.ConnectorTaskAssignment) ([]string, []ConnectTaskAssignment) {
+	connectors := make([]string, 0)
+	tasks := make([]ConnectTaskAssignment, 0)
+	for _, assignment := range assignments {
+		for _, taskID := range assignment.TaskIDs {
+			if taskID == kafka.ConnectorTaskID {
+				connectors = append(connectors, assignment.Connector)
+				continue
+			}
+			tasks = append(tasks, ConnectTaskAssignment{Connector: assignment.Connector, TaskID: taskID})
+		}
+	}
+	sort.Strings(connectors)
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Connector != tasks[j].Connector {
+			return tasks[i].Connector < tasks[j].Connector
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
+
+	return connectors, tasks
+}
@@ -0,0 +1,132 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+)
+
+// CreateACLsResponse is the response that is sent after creating ACLs (or simulating it in a dry run).
+type CreateACLsResponse struct {
+	DryRun  bool              `json:"dryRun"`
+	Results []CreateACLResult `json:"results"`
+}
+
+// CreateACLResult is the result of a single ACL creation.
+type CreateACLResult struct {
+	ACL AclBinding `json:"acl"`
+
+	// AlreadyExists is only set in dry runs. Kafka does not return an error for ACLs that exist already.
+	AlreadyExists bool    `json:"alreadyExists"`
+	Error         *string `json:"error"`
+}
+
+// CreateACLs creates all given ACL bindings. In a dry run no ACLs are created, instead it is reported which of
+// the ACLs exist already.
+func (s *Service) CreateACLs(ctx context.Context, creations []kmsg.CreateACLsRequestCreation, dryRun bool) (*CreateACLsResponse, *rest.Error) {
+	results := make([]CreateACLResult, len(creations))
+	for i, creation := range creations {
+		results[i] = CreateACLResult{ACL: aclBindingFromCreation(creation)}
+	}
+
+	if dryRun {
+		existing, err := s.ListACLBindings(ctx, newDescribeAllACLsRequest())
+		if err != nil {
+			return nil, newACLRequestError("Failed to list existing ACLs", err)
+		}
+		existingSet := make(map[AclBinding]struct{}, len(existing))
+		for _, binding := range existing {
+			existingSet[binding] = struct{}{}
+		}
+		for i := range results {
+			_, results[i].AlreadyExists = existingSet[results[i].ACL]
+		}
+		return &CreateACLsResponse{DryRun: true, Results: results}, nil
+	}
+
+	req := kmsg.NewCreateACLsRequest()
+	req.Creations = creations
+	res, err := s.kafkaSvc.CreateACLs(ctx, req)
+	if err != nil {
+		return nil, newACLRequestError("Failed to create ACLs", err)
+	}
+	if len(res.Results) != len(creations) {
+		return nil, newACLRequestError("Failed to create ACLs",
+			fmt.Errorf("unexpected number of results, expected '%d' but got '%d'", len(creations), len(res.Results)))
+	}
+
+	for i, result := range res.Results {
+		results[i].Error = aclErrorMessage(result.ErrorCode, result.ErrorMessage)
+		if results[i].Error != nil {
+			s.logger.Warn("failed to create ACL",
+				zap.String("principal", results[i].ACL.Principal),
+				zap.String("resource_name", results[i].ACL.ResourceName),
+				zap.String("error", *results[i].Error))
+		}
+	}
+
+	return &CreateACLsResponse{DryRun: false, Results: results}, nil
+}
+
+func aclBindingFromCreation(creation kmsg.CreateACLsRequestCreation) AclBinding {
+	return AclBinding{
+		ResourceType:        creation.ResourceType.String(),
+		ResourceName:        creation.ResourceName,
+		ResourcePatternType: creation.ResourcePatternType.String(),
+		Principal:           creation.Principal,
+		Host:                creation.Host,
+		Operation:           creation.Operation.String(),
+		PermissionType:      creation.PermissionType.String(),
+	}
+}
+
+// newDescribeAllACLsRequest returns a DescribeACLs request that matches all ACL bindings.
+func newDescribeAllACLsRequest() kmsg.DescribeACLsRequest {
+	req := kmsg.NewDescribeACLsRequest()
+	req.ResourceType = kmsg.ACLResourceTypeAny
+	req.ResourcePatternType = kmsg.ACLResourcePatternTypeAny
+	req.Operation = kmsg.ACLOperationAny
+	req.PermissionType = kmsg.ACLPermissionTypeAny
+	return req
+}
+
+// aclErrorMessage returns the error message for the given error code or nil if there is no error.
+func aclErrorMessage(errorCode int16, errorMessage *string) *string {
+	err := kerr.ErrorForCode(errorCode)
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	if errorMessage != nil && *errorMessage != "" {
+		msg = fmt.Sprintf("%v: %v", msg, *errorMessage)
+	}
+	return &msg
+}
+
+func newACLRequestError(message string, err error) *rest.Error {
+	status := http.StatusServiceUnavailable
+	if errors.Is(err, kerr.SecurityDisabled) {
+		status = http.StatusBadRequest
+	}
+	return &rest.Error{
+		Err:      err,
+		Status:   status,
+		Message:  fmt.Sprintf("%v: %v", message, err.Error()),
+		IsSilent: false,
+	}
+}
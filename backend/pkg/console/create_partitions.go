@@ -0,0 +1,172 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+// CreatePartitionsResponse is the response after increasing the partition count of a topic.
+type CreatePartitionsResponse struct {
+	TopicName              string `json:"topicName"`
+	PreviousPartitionCount int32  `json:"previousPartitionCount"`
+	PartitionCount         int32  `json:"partitionCount"`
+	ValidateOnly           bool   `json:"validateOnly"`
+
+	// HasKeyedMessages is true if the latest message of any partition has a key. Adding partitions changes
+	// the partition that a key is mapped to, so that messages with the same key may be consumed out of order.
+	HasKeyedMessages bool     `json:"hasKeyedMessages"`
+	Warnings         []string `json:"warnings"`
+}
+
+// CreatePartitions increases the partition count of a topic to the given partition count. Optionally a replica
+// assignment for each new partition can be passed. If validateOnly is true, the request is only validated by
+// Kafka but the partitions will not be created.
+func (s *Service) CreatePartitions(ctx context.Context, topicName string, partitionCount int32, assignments [][]int32, validateOnly bool) (*CreatePartitionsResponse, *rest.Error) {
+	topicMetadata, restErr := s.kafkaSvc.GetSingleMetadata(ctx, topicName)
+	if restErr != nil {
+		return nil, restErr
+	}
+	previousPartitionCount := int32(len(topicMetadata.Partitions))
+	restErr = validatePartitionCount(previousPartitionCount, partitionCount, len(assignments))
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res := &CreatePartitionsResponse{
+		TopicName:              topicName,
+		PreviousPartitionCount: previousPartitionCount,
+		PartitionCount:         partitionCount,
+		ValidateOnly:           validateOnly,
+	}
+	hasKeyedMessages, err := s.hasKeyedMessages(ctx, topicName, previousPartitionCount)
+	if err != nil {
+		s.logger.Warn("failed to check whether topic has keyed messages", zap.String("topic_name", topicName), zap.Error(err))
+	}
+	res.HasKeyedMessages = hasKeyedMessages
+	res.Warnings = keyedMessagesWarnings(hasKeyedMessages, err)
+
+	kafkaRes, err := s.kafkaSvc.CreatePartitions(ctx, topicName, partitionCount, assignments, validateOnly)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to execute create partitions request: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if len(kafkaRes.Topics) != 1 {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("topics array in response is empty"),
+			Status:   http.StatusServiceUnavailable,
+			Message:  "Unexpected Kafka response: No topics set in the response",
+			IsSilent: false,
+		}
+	}
+	topicRes := kafkaRes.Topics[0]
+	err = kerr.ErrorForCode(topicRes.ErrorCode)
+	if err != nil {
+		errMessage := err.Error()
+		if topicRes.ErrorMessage != nil {
+			errMessage = fmt.Sprintf("%v: %v", errMessage, *topicRes.ErrorMessage)
+		}
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to create partitions: %v", errMessage),
+			IsSilent: false,
+		}
+	}
+
+	return res, nil
+}
+
+// hasKeyedMessages checks whether the latest message in any of the topic's partitions has a key.
+func (s *Service) hasKeyedMessages(ctx context.Context, topicName string, partitionCount int32) (bool, error) {
+	partitionIDs := make([]int32, partitionCount)
+	for i := range partitionIDs {
+		partitionIDs[i] = int32(i)
+	}
+	marks, err := s.kafkaSvc.GetPartitionMarks(ctx, topicName, partitionIDs)
+	if err != nil {
+		return false, fmt.Errorf("failed to get partition watermarks: %w", err)
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	latestRecords, err := s.kafkaSvc.FetchLatestRecords(fetchCtx, map[string]map[int32]*kafka.PartitionMarks{topicName: marks})
+	if err != nil {
+		return false, err
+	}
+	latest := latestRecords[topicName]
+	if latest.Error != nil {
+		return false, latest.Error
+	}
+
+	return hasKeyedRecord(latest.Records), nil
+}
+
+// hasKeyedRecord returns true if any of the given records has a key.
+func hasKeyedRecord(records map[int32]*kgo.Record) bool {
+	for _, record := range records {
+		if len(record.Key) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePartitionCount checks that the partition count is increased and that a replica assignment is passed
+// for each new partition if assignments are set at all.
+func validatePartitionCount(previousPartitionCount int32, partitionCount int32, assignmentCount int) *rest.Error {
+	if partitionCount <= previousPartitionCount {
+		return &rest.Error{
+			Err:      fmt.Errorf("partition count must be larger than the current partition count"),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("The partition count must be larger than the current partition count (%d), partitions can not be removed", previousPartitionCount),
+			IsSilent: false,
+		}
+	}
+	if assignmentCount > 0 && int32(assignmentCount) != partitionCount-previousPartitionCount {
+		return &rest.Error{
+			Err:      fmt.Errorf("number of replica assignments does not match the number of new partitions"),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("One replica assignment is required for each of the %d new partitions", partitionCount-previousPartitionCount),
+			IsSilent: false,
+		}
+	}
+
+	return nil
+}
+
+// keyedMessagesWarnings returns the warnings that are shown before adding partitions, depending on whether the
+// topic has keyed messages or whether this could not be checked.
+func keyedMessagesWarnings(hasKeyedMessages bool, checkErr error) []string {
+	warnings := make([]string, 0)
+	if checkErr != nil {
+		warnings = append(warnings, "Could not check whether the topic contains messages with keys. "+
+			"If it does, adding partitions will change the partition each key is mapped to.")
+	}
+	if hasKeyedMessages {
+		warnings = append(warnings, "The topic contains messages with keys. Adding partitions will change the "+
+			"partition each key is mapped to, so that messages with the same key may no longer be consumed in order.")
+	}
+	return warnings
+}
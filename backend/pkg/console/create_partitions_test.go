@@ -0,0 +1,74 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestValidatePartitionCount(t *testing.T) {
+	tt := []struct {
+		name            string
+		previousCount   int32
+		partitionCount  int32
+		assignmentCount int
+		expectErr       bool
+	}{
+		{"increase without assignments", 3, 6, 0, false},
+		{"increase with an assignment per new partition", 3, 6, 3, false},
+		{"same partition count", 3, 3, 0, true},
+		{"decrease", 3, 2, 0, true},
+		{"too few assignments", 3, 6, 2, true},
+		{"too many assignments", 3, 6, 4, true},
+	}
+
+	for _, test := range tt {
+		restErr := validatePartitionCount(test.previousCount, test.partitionCount, test.assignmentCount)
+		if !test.expectErr {
+			assert.Nil(t, restErr, test.name)
+			continue
+		}
+		require.NotNil(t, restErr, test.name)
+		assert.Equal(t, http.StatusBadRequest, restErr.Status, test.name)
+	}
+}
+
+func TestHasKeyedRecord(t *testing.T) {
+	assert.False(t, hasKeyedRecord(map[int32]*kgo.Record{}))
+	assert.False(t, hasKeyedRecord(map[int32]*kgo.Record{0: {Value: []byte("a")}, 1: {Key: []byte{}}}))
+	assert.True(t, hasKeyedRecord(map[int32]*kgo.Record{0: {Value: []byte("a")}, 1: {Key: []byte("customer-1")}}))
+}
+
+func TestKeyedMessagesWarnings(t *testing.T) {
+	tt := []struct {
+		name             string
+		hasKeyedMessages bool
+		checkErr         error
+		expectedWarnings int
+	}{
+		{"no keys", false, nil, 0},
+		{"keyed messages", true, nil, 1},
+		{"check failed", false, fmt.Errorf("timeout"), 1},
+	}
+
+	for _, test := range tt {
+		warnings := keyedMessagesWarnings(test.hasKeyedMessages, test.checkErr)
+		assert.NotNil(t, warnings, test.name)
+		assert.Len(t, warnings, test.expectedWarnings, test.name)
+	}
+	assert.Contains(t, keyedMessagesWarnings(true, nil)[0], "The topic contains messages with keys")
+	assert.Contains(t, keyedMessagesWarnings(false, fmt.Errorf("timeout"))[0], "Could not check")
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DeleteACLsResponse is the response that is sent after deleting ACLs (or simulating it in a dry run).
+type DeleteACLsResponse struct {
+	DryRun  bool                     `json:"dryRun"`
+	Results []DeleteACLsFilterResult `json:"results"`
+}
+
+// DeleteACLsFilterResult contains all ACL bindings that matched a single delete filter.
+type DeleteACLsFilterResult struct {
+	MatchingACLs []DeletedACL `json:"matchingAcls"`
+	Error        *string      `json:"error"`
+}
+
+// DeletedACL is an ACL binding that has been (or would be in a dry run) deleted.
+type DeletedACL struct {
+	AclBinding
+	Error *string `json:"error"`
+}
+
+// DeleteACLs deletes all ACL bindings that match one of the given filters. In a dry run no ACLs are deleted,
+// instead the bindings that would be deleted are returned.
+func (s *Service) DeleteACLs(ctx context.Context, filters []kmsg.DeleteACLsRequestFilter, dryRun bool) (*DeleteACLsResponse, *rest.Error) {
+	results := make([]DeleteACLsFilterResult, len(filters))
+
+	if dryRun {
+		for i, filter := range filters {
+			bindings, err := s.ListACLBindings(ctx, describeRequestFromDeleteFilter(filter))
+			if err != nil {
+				return nil, newACLRequestError("Failed to list matching ACLs", err)
+			}
+			matching := make([]DeletedACL, len(bindings))
+			for j, binding := range bindings {
+				matching[j] = DeletedACL{AclBinding: binding}
+			}
+			results[i] = DeleteACLsFilterResult{MatchingACLs: matching}
+		}
+		return &DeleteACLsResponse{DryRun: true, Results: results}, nil
+	}
+
+	req := kmsg.NewDeleteACLsRequest()
+	req.Filters = filters
+	res, err := s.kafkaSvc.DeleteACLs(ctx, req)
+	if err != nil {
+		return nil, newACLRequestError("Failed to delete ACLs", err)
+	}
+
+	for i, result := range res.Results {
+		matching := make([]DeletedACL, len(result.MatchingACLs))
+		for j, acl := range result.MatchingACLs {
+			matching[j] = DeletedACL{
+				AclBinding: AclBinding{
+					ResourceType:        acl.ResourceType.String(),
+					ResourceName:        acl.ResourceName,
+					ResourcePatternType: acl.ResourcePatternType.String(),
+					Principal:           acl.Principal,
+					Host:                acl.Host,
+					Operation:           acl.Operation.String(),
+					PermissionType:      acl.PermissionType.String(),
+				},
+				Error: aclErrorMessage(acl.ErrorCode, acl.ErrorMessage),
+			}
+		}
+		if i < len(results) {
+			results[i] = DeleteACLsFilterResult{
+				MatchingACLs: matching,
+				Error:        aclErrorMessage(result.ErrorCode, result.ErrorMessage),
+			}
+		}
+	}
+
+	return &DeleteACLsResponse{DryRun: false, Results: results}, nil
+}
+
+func describeRequestFromDeleteFilter(filter kmsg.DeleteACLsRequestFilter) kmsg.DescribeACLsRequest {
+	req := kmsg.NewDescribeACLsRequest()
+	req.ResourceType = filter.ResourceType
+	req.ResourceName = filter.ResourceName
+	req.ResourcePatternType = filter.ResourcePatternType
+	req.Principal = filter.Principal
+	req.Host = filter.Host
+	req.Operation = filter.Operation
+	req.PermissionType = filter.PermissionType
+	return req
+}
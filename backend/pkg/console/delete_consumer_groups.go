@@ -0,0 +1,166 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DeleteConsumerGroupResult is the outcome of deleting a single consumer group.
+type DeleteConsumerGroupResult struct {
+	GroupID string `json:"groupId"`
+
+	// IsDeletable is true if the group exists and has no active members.
+	IsDeletable bool   `json:"isDeletable"`
+	IsDeleted   bool   `json:"isDeleted"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ListConsumerGroupIDsByPattern returns the sorted IDs of all consumer groups that match the given regex.
+func (s *Service) ListConsumerGroupIDsByPattern(ctx context.Context, pattern string) ([]string, *rest.Error) {
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to compile group id pattern: %w", err),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("The group id pattern is not a valid regex: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	groups, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to list consumer groups: %v", err.Error()),
+		}
+	}
+
+	groupIDs := make([]string, 0)
+	for _, groupID := range groups.GetGroupIDs() {
+		if expr.MatchString(groupID) {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	sort.Strings(groupIDs)
+
+	return groupIDs, nil
+}
+
+// DeleteConsumerGroups deletes the given consumer groups. Groups that still have active members are refused,
+// so that running consumers are not affected. If dryRun is true, the groups are only checked but not deleted.
+// The results are returned in the same order as the given group IDs.
+func (s *Service) DeleteConsumerGroups(ctx context.Context, groupIDs []string, dryRun bool) ([]DeleteConsumerGroupResult, *rest.Error) {
+	if len(groupIDs) == 0 {
+		return make([]DeleteConsumerGroupResult, 0), nil
+	}
+
+	// 1. Describe all groups so that we can refuse the deletion of groups with active members
+	describedGroups, err := s.kafkaSvc.DescribeConsumerGroups(ctx, groupIDs)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     fmt.Errorf("failed to describe consumer groups: %w", err),
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to check consumer group states before proceeding: %v", err.Error()),
+		}
+	}
+	describedByID := make(map[string]kmsg.DescribeGroupsResponseGroup)
+	for _, group := range describedGroups.GetDescribedGroups() {
+		describedByID[group.Group] = group
+	}
+
+	results := checkConsumerGroupDeletions(groupIDs, describedByID)
+	groupsToDelete := make([]string, 0, len(groupIDs))
+	for _, result := range results {
+		if result.IsDeletable {
+			groupsToDelete = append(groupsToDelete, result.GroupID)
+		}
+	}
+	if dryRun || len(groupsToDelete) == 0 {
+		return results, nil
+	}
+
+	// 2. Delete all groups without active members
+	res, err := s.kafkaSvc.DeleteConsumerGroups(ctx, groupsToDelete)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to delete consumer groups: %v", err.Error()),
+		}
+	}
+	deleteErrors := make(map[string]error, len(res.Groups))
+	for _, group := range res.Groups {
+		deleteErrors[group.Group] = kerr.ErrorForCode(group.ErrorCode)
+	}
+	applyConsumerGroupDeleteErrors(results, deleteErrors)
+
+	return results, nil
+}
+
+// checkConsumerGroupDeletions reports for each group whether it can be deleted. Groups that could not be
+// described, do not exist or still have active members are not deletable.
+func checkConsumerGroupDeletions(groupIDs []string, describedByID map[string]kmsg.DescribeGroupsResponseGroup) []DeleteConsumerGroupResult {
+	results := make([]DeleteConsumerGroupResult, len(groupIDs))
+	for i, groupID := range groupIDs {
+		results[i] = DeleteConsumerGroupResult{GroupID: groupID}
+		group, exists := describedByID[groupID]
+		if !exists {
+			results[i].Error = "Failed to describe the consumer group, because its coordinator did not respond"
+			continue
+		}
+		if err := kerr.ErrorForCode(group.ErrorCode); err != nil {
+			results[i].Error = fmt.Sprintf("Failed to describe the consumer group: %v", err.Error())
+			continue
+		}
+		if strings.EqualFold(group.State, "dead") {
+			results[i].Error = "Consumer group does not exist"
+			continue
+		}
+		if len(group.Members) > 0 {
+			results[i].Error = fmt.Sprintf("Consumer group has %d active member(s) and can therefore not be deleted. Current Group State is: %v",
+				len(group.Members), group.State)
+			continue
+		}
+		results[i].IsDeletable = true
+	}
+
+	return results
+}
+
+// applyConsumerGroupDeleteErrors sets the outcome of the delete request on all deletable groups.
+func applyConsumerGroupDeleteErrors(results []DeleteConsumerGroupResult, deleteErrors map[string]error) {
+	for i, result := range results {
+		if !result.IsDeletable {
+			continue
+		}
+		err, exists := deleteErrors[result.GroupID]
+		switch {
+		case !exists:
+			results[i].Error = "Kafka did not return a result for this consumer group"
+		case err == kerr.NonEmptyGroup:
+			results[i].Error = "Consumer group has active members and can therefore not be deleted"
+		case err != nil:
+			results[i].Error = fmt.Sprintf("Failed to delete consumer group: %v", err.Error())
+		default:
+			results[i].IsDeleted = true
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCheckConsumerGroupDeletions(t *testing.T) {
+	describedByID := map[string]kmsg.DescribeGroupsResponseGroup{
+		"empty":          {Group: "empty", State: "Empty"},
+		"dead":           {Group: "dead", State: "Dead"},
+		"active":         {Group: "active", State: "Stable", Members: []kmsg.DescribeGroupsResponseGroupMember{{MemberID: "consumer-1"}}},
+		"not-authorized": {Group: "not-authorized", ErrorCode: kerr.GroupAuthorizationFailed.Code},
+	}
+
+	tt := []struct {
+		groupID             string
+		expectedIsDeletable bool
+		expectedError       string
+	}{
+		{"empty", true, ""},
+		{"dead", false, "Consumer group does not exist"},
+		{"active", false, "Consumer group has 1 active member(s) and can therefore not be deleted. Current Group State is: Stable"},
+		{"not-authorized", false, "Failed to describe the consumer group: " + kerr.GroupAuthorizationFailed.Error()},
+		{"unknown", false, "Failed to describe the consumer group, because its coordinator did not respond"},
+	}
+
+	groupIDs := make([]string, len(tt))
+	for i, test := range tt {
+		groupIDs[i] = test.groupID
+	}
+	results := checkConsumerGroupDeletions(groupIDs, describedByID)
+	for i, test := range tt {
+		assert.Equal(t, test.groupID, results[i].GroupID)
+		assert.Equal(t, test.expectedIsDeletable, results[i].IsDeletable, test.groupID)
+		assert.Equal(t, test.expectedError, results[i].Error, test.groupID)
+		assert.False(t, results[i].IsDeleted, test.groupID)
+	}
+}
+
+func TestApplyConsumerGroupDeleteErrors(t *testing.T) {
+	results := []DeleteConsumerGroupResult{
+		{GroupID: "deleted", IsDeletable: true},
+		{GroupID: "rejoined", IsDeletable: true},
+		{GroupID: "missing", IsDeletable: true},
+		{GroupID: "active", Error: "Consumer group has 1 active member(s)"},
+	}
+	applyConsumerGroupDeleteErrors(results, map[string]error{
+		"deleted":  nil,
+		"rejoined": kerr.NonEmptyGroup,
+	})
+
+	assert.Equal(t, []DeleteConsumerGroupResult{
+		{GroupID: "deleted", IsDeletable: true, IsDeleted: true},
+		{GroupID: "rejoined", IsDeletable: true, Error: "Consumer group has active members and can therefore not be deleted"},
+		{GroupID: "missing", IsDeletable: true, Error: "Kafka did not return a result for this consumer group"},
+		{GroupID: "active", Error: "Consumer group has 1 active member(s)"},
+	}, results)
+}
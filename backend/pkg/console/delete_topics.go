@@ -0,0 +1,347 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+)
+
+// topicDeletionConfirmationTTL is the duration for which a confirmation token of a topic deletion pre-flight
+// can be used to execute the deletion.
+const topicDeletionConfirmationTTL = 5 * time.Minute
+
+// topicDeletionPreflightTimeout returns how long the log dirs and the latest messages of the given number of topics
+// may take to fetch. It grows with the number of topics, but stays below the default HTTP write timeout of 30s.
+func topicDeletionPreflightTimeout(topicCount int) time.Duration {
+	const baseTimeout = 10 * time.Second
+	const timeoutPerTopic = 50 * time.Millisecond
+	const maxTimeout = 25 * time.Second
+
+	timeout := baseTimeout + time.Duration(topicCount)*timeoutPerTopic
+	if timeout > maxTimeout {
+		return maxTimeout
+	}
+	return timeout
+}
+
+// TopicDeletionPreflight reports the state of all topics that have been selected for deletion. The topics that
+// can be deleted are deleted by passing the ConfirmationToken, as long as the token has not expired.
+type TopicDeletionPreflight struct {
+	Topics            []TopicDeletionReport `json:"topics"`
+	ConfirmationToken string                `json:"confirmationToken,omitempty"`
+	ExpiresAt         *time.Time            `json:"expiresAt,omitempty"`
+}
+
+// TopicDeletionReport describes a topic that has been selected for deletion, so that the requester can check
+// whether the topic is still in use.
+type TopicDeletionReport struct {
+	TopicName      string `json:"topicName"`
+	IsInternal     bool   `json:"isInternal"`
+	PartitionCount int    `json:"partitionCount"`
+
+	// LastMessageTimestamp is the timestamp (in ms) of the latest message in all partitions. It is -1 if no
+	// message has been consumed, LastMessageTimestampStatus (one of the LatestTimestampStatus* constants)
+	// describes why.
+	LastMessageTimestamp       int64              `json:"lastMessageTimestamp"`
+	LastMessageTimestampStatus string             `json:"lastMessageTimestampStatus"`
+	LogDirSummary              TopicLogDirSummary `json:"logDirSummary"`
+
+	// ConsumerGroups that have committed offsets on this topic.
+	ConsumerGroups []CommittedConsumerGroup `json:"consumerGroups"`
+
+	// IsDeletable is false if the topic will not be deleted when the deletion is confirmed. Error describes why.
+	IsDeletable bool   `json:"isDeletable"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DeleteTopicResult is the outcome of deleting a single topic.
+type DeleteTopicResult struct {
+	TopicName string `json:"topicName"`
+	IsDeleted bool   `json:"isDeleted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ListTopicNamesByPattern returns the sorted names of all topics that match the given regex.
+func (s *Service) ListTopicNamesByPattern(ctx context.Context, pattern string) ([]string, *rest.Error) {
+	expr, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to compile topic name pattern: %w", err),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("The topic name pattern is not a valid regex: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get topic metadata from cluster: %v", err.Error()),
+		}
+	}
+
+	topicNames := make([]string, 0)
+	for _, topic := range metadata.Topics {
+		if expr.MatchString(*topic.Topic) {
+			topicNames = append(topicNames, *topic.Topic)
+		}
+	}
+	sort.Strings(topicNames)
+
+	return topicNames, nil
+}
+
+// PreflightTopicDeletion reports the consumer groups, the latest message timestamp and the size of each given
+// topic. Internal topics and topics that do not exist can not be deleted. If at least one topic can be deleted
+// a confirmation token is returned, which is required to execute the deletion.
+func (s *Service) PreflightTopicDeletion(ctx context.Context, topicNames []string) (*TopicDeletionPreflight, *rest.Error) {
+	preflight := &TopicDeletionPreflight{Topics: make([]TopicDeletionReport, 0, len(topicNames))}
+	if len(topicNames) == 0 {
+		return preflight, nil
+	}
+
+	// 1. Get metadata of all requested topics
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get topic metadata from cluster: %v", err.Error()),
+		}
+	}
+	existingTopics := make([]kmsg.MetadataResponseTopic, 0, len(metadata.Topics))
+	topicErrors := make(map[string]error)
+	topicPartitions := make(map[string][]int32)
+	for _, topic := range metadata.Topics {
+		if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+			topicErrors[*topic.Topic] = err
+			continue
+		}
+		existingTopics = append(existingTopics, topic)
+		for _, partition := range topic.Partitions {
+			topicPartitions[*topic.Topic] = append(topicPartitions[*topic.Topic], partition.Partition)
+		}
+	}
+
+	// 2. Get log dir sizes and the latest message of each topic. Use a shorter ctx timeout so that we don't wait
+	// for too long if one broker is currently down.
+	childCtx, cancel := context.WithTimeout(ctx, topicDeletionPreflightTimeout(len(existingTopics)))
+	defer cancel()
+
+	filteredMetadata := *metadata
+	filteredMetadata.Topics = existingTopics
+	logDirsByTopic := s.logDirsByTopic(childCtx, &filteredMetadata)
+
+	lastTimestamps := make(map[string]latestMessageTimestamp)
+	marks, err := s.kafkaSvc.GetPartitionMarksBulk(childCtx, topicPartitions)
+	if err != nil {
+		s.logger.Warn("failed to get partition watermarks for topic deletion pre-flight", zap.Error(err))
+	} else {
+		lastTimestamps = s.getLatestMessageTimestamps(childCtx, marks)
+	}
+
+	// 3. Get consumer groups with committed offsets on the topics along with their members
+	groupsByTopic, err := s.getConsumerGroupsByTopic(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get consumer groups of topics: %v", err.Error()),
+		}
+	}
+
+	// 4. Construct reports
+	partitionCounts := make(map[string]int, len(existingTopics))
+	isInternal := make(map[string]bool, len(existingTopics))
+	for _, topic := range existingTopics {
+		partitionCounts[*topic.Topic] = len(topic.Partitions)
+		isInternal[*topic.Topic] = topic.IsInternal
+	}
+	deletableTopicNames := make([]string, 0, len(topicNames))
+	for _, topicName := range topicNames {
+		lastTimestamp := latestTimestampOf(lastTimestamps, topicName)
+		report := TopicDeletionReport{
+			TopicName:                  topicName,
+			IsInternal:                 isInternal[topicName],
+			PartitionCount:             partitionCounts[topicName],
+			LastMessageTimestamp:       lastTimestamp.Timestamp,
+			LastMessageTimestampStatus: lastTimestamp.Status,
+			LogDirSummary:              logDirsByTopic[topicName],
+			ConsumerGroups:             groupsByTopic[topicName],
+		}
+
+		_, exists := partitionCounts[topicName]
+		switch {
+		case topicErrors[topicName] == kerr.UnknownTopicOrPartition:
+			report.Error = "Topic does not exist"
+		case topicErrors[topicName] != nil:
+			report.Error = fmt.Sprintf("Failed to get topic metadata: %v", topicErrors[topicName].Error())
+		case !exists:
+			report.Error = "Kafka did not return metadata for this topic"
+		case report.IsInternal:
+			report.Error = "Internal topics can not be deleted"
+		default:
+			report.IsDeletable = true
+			deletableTopicNames = append(deletableTopicNames, topicName)
+		}
+		preflight.Topics = append(preflight.Topics, report)
+	}
+
+	if len(deletableTopicNames) > 0 {
+		token, expiresAt, err := s.topicDeletionConfirmations.add(deletableTopicNames)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:     err,
+				Status:  http.StatusInternalServerError,
+				Message: fmt.Sprintf("Failed to create confirmation token: %v", err.Error()),
+			}
+		}
+		preflight.ConfirmationToken = token
+		preflight.ExpiresAt = &expiresAt
+	}
+
+	return preflight, nil
+}
+
+// ConfirmTopicDeletion returns the names of the topics that have been reported as deletable by the pre-flight
+// that issued the given token. Each token can only be used once.
+func (s *Service) ConfirmTopicDeletion(token string) ([]string, *rest.Error) {
+	topicNames, exists := s.topicDeletionConfirmations.consume(token)
+	if !exists {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("topic deletion confirmation token is unknown or expired"),
+			Status:   http.StatusBadRequest,
+			Message:  "The confirmation token is unknown, has already been used or has expired. Please run the pre-flight again",
+			IsSilent: true,
+		}
+	}
+
+	return topicNames, nil
+}
+
+// DeleteTopics deletes all given topics at once. The results are returned in the same order as the given topic names.
+func (s *Service) DeleteTopics(ctx context.Context, topicNames []string) ([]DeleteTopicResult, *rest.Error) {
+	results := make([]DeleteTopicResult, len(topicNames))
+	for i, topicName := range topicNames {
+		results[i] = DeleteTopicResult{TopicName: topicName}
+	}
+	if len(topicNames) == 0 {
+		return results, nil
+	}
+
+	res, err := s.kafkaSvc.DeleteTopics(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to execute delete topics command: %v", err.Error()),
+		}
+	}
+	deleteErrors := make(map[string]error, len(res.Topics))
+	for _, topic := range res.Topics {
+		if topic.Topic == nil {
+			continue
+		}
+		deleteErrors[*topic.Topic] = kerr.ErrorForCode(topic.ErrorCode)
+	}
+
+	for i, result := range results {
+		err, exists := deleteErrors[result.TopicName]
+		switch {
+		case !exists:
+			results[i].Error = "Kafka did not return a result for this topic"
+		case err != nil:
+			results[i].Error = fmt.Sprintf("Failed to delete topic: %v", err.Error())
+		default:
+			results[i].IsDeleted = true
+		}
+	}
+
+	return results, nil
+}
+
+// topicDeletionConfirmations stores the topics of all pre-flights that have not yet been confirmed. Tokens are
+// only kept in memory, therefore the deletion must be confirmed on the same instance that ran the pre-flight.
+type topicDeletionConfirmations struct {
+	mutex   sync.Mutex
+	pending map[string]pendingTopicDeletion
+	ttl     time.Duration
+}
+
+type pendingTopicDeletion struct {
+	TopicNames []string
+	ExpiresAt  time.Time
+}
+
+func newTopicDeletionConfirmations(ttl time.Duration) *topicDeletionConfirmations {
+	return &topicDeletionConfirmations{
+		pending: make(map[string]pendingTopicDeletion),
+		ttl:     ttl,
+	}
+}
+
+// add stores the topic names and returns a random token along with its expiry.
+func (c *topicDeletionConfirmations) add(topicNames []string) (string, time.Time, error) {
+	tokenBytes := make([]byte, 16)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	for existingToken, deletion := range c.pending {
+		if now.After(deletion.ExpiresAt) {
+			delete(c.pending, existingToken)
+		}
+	}
+
+	expiresAt := now.Add(c.ttl)
+	c.pending[token] = pendingTopicDeletion{TopicNames: topicNames, ExpiresAt: expiresAt}
+
+	return token, expiresAt, nil
+}
+
+// consume returns the topic names of the given token and removes the token. False is returned if the token does
+// not exist or has expired.
+func (c *topicDeletionConfirmations) consume(token string) ([]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	deletion, exists := c.pending[strings.TrimSpace(token)]
+	if !exists {
+		return nil, false
+	}
+	delete(c.pending, strings.TrimSpace(token))
+	if time.Now().After(deletion.ExpiresAt) {
+		return nil, false
+	}
+
+	return deletion.TopicNames, true
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicDeletionConfirmations(t *testing.T) {
+	confirmations := newTopicDeletionConfirmations(time.Minute)
+	token, expiresAt, err := confirmations.add([]string{"test-a", "test-b"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	_, exists := confirmations.consume("unknown")
+	assert.False(t, exists)
+
+	topicNames, exists := confirmations.consume(token)
+	require.True(t, exists)
+	assert.Equal(t, []string{"test-a", "test-b"}, topicNames)
+
+	// Tokens can only be used once
+	_, exists = confirmations.consume(token)
+	assert.False(t, exists)
+
+	// Expired tokens are refused
+	confirmations = newTopicDeletionConfirmations(-time.Second)
+	token, _, err = confirmations.add([]string{"test-a"})
+	require.NoError(t, err)
+	_, exists = confirmations.consume(token)
+	assert.False(t, exists)
+}
+
+func TestTopicDeletionPreflightTimeout(t *testing.T) {
+	tt := []struct {
+		topicCount int
+		want       time.Duration
+	}{
+		{0, 10 * time.Second},
+		{100, 15 * time.Second},
+		{1000, 25 * time.Second},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.want, topicDeletionPreflightTimeout(test.topicCount), "topic count %d", test.topicCount)
+	}
+}
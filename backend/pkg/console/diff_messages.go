@@ -0,0 +1,267 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+const (
+	DiffOpAdded   = "added"
+	DiffOpRemoved = "removed"
+	DiffOpChanged = "changed"
+)
+
+// MessageReference identifies a single record via its topic, partition and offset.
+type MessageReference struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+	Offset      int64  `json:"offset"`
+}
+
+// MessageDiff is the structured difference between two records.
+type MessageDiff struct {
+	Left    MessageReference `json:"left"`
+	Right   MessageReference `json:"right"`
+	IsEqual bool             `json:"isEqual"`
+	Key     PayloadDiff      `json:"key"`
+	Value   PayloadDiff      `json:"value"`
+	Headers PayloadDiff      `json:"headers"`
+}
+
+// PayloadDiff describes the differences between two deserialized payloads.
+type PayloadDiff struct {
+	IsEqual bool `json:"isEqual"`
+
+	LeftEncoding    string `json:"leftEncoding,omitempty"`
+	RightEncoding   string `json:"rightEncoding,omitempty"`
+	LeftSchemaID    uint32 `json:"leftSchemaId,omitempty"`
+	RightSchemaID   uint32 `json:"rightSchemaId,omitempty"`
+	SchemaIDChanged bool   `json:"schemaIdChanged"`
+
+	Changes []DiffEntry `json:"changes"`
+}
+
+// DiffEntry is a single difference at the given JSON path.
+type DiffEntry struct {
+	Path  string      `json:"path"`
+	Op    string      `json:"op"`
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// DiffMessages fetches two records, deserializes them and returns the differences of key, value and headers.
+// Configured redaction rules are applied to both records unless bypassRedaction is true.
+func (s *Service) DiffMessages(ctx context.Context, left MessageReference, right MessageReference, bypassRedaction bool) (*MessageDiff, *rest.Error) {
+	var leftMsg, rightMsg *kafka.TopicMessage
+	var leftErr, rightErr *rest.Error
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		leftMsg, leftErr = s.GetMessage(ctx, left.TopicName, left.PartitionID, left.Offset, bypassRedaction)
+	}()
+	go func() {
+		defer wg.Done()
+		rightMsg, rightErr = s.GetMessage(ctx, right.TopicName, right.PartitionID, right.Offset, bypassRedaction)
+	}()
+	wg.Wait()
+	if leftErr != nil {
+		return nil, leftErr
+	}
+	if rightErr != nil {
+		return nil, rightErr
+	}
+
+	diff, err := diffTopicMessages(leftMsg, rightMsg)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusInternalServerError,
+			Message:  fmt.Sprintf("Failed to compute message diff: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	diff.Left = left
+	diff.Right = right
+
+	return diff, nil
+}
+
+func diffTopicMessages(left *kafka.TopicMessage, right *kafka.TopicMessage) (*MessageDiff, error) {
+	keyDiff, err := diffPayloads(left.Key, right.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff keys: %w", err)
+	}
+	valueDiff, err := diffPayloads(left.Value, right.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff values: %w", err)
+	}
+
+	// Headers are compared as a JSON object that is keyed by the header key
+	leftHeaders, err := headersToObject(left.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert left headers: %w", err)
+	}
+	rightHeaders, err := headersToObject(right.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert right headers: %w", err)
+	}
+	headerChanges := diffValues("$", leftHeaders, rightHeaders)
+	headersDiff := PayloadDiff{
+		IsEqual: len(headerChanges) == 0,
+		Changes: headerChanges,
+	}
+
+	return &MessageDiff{
+		IsEqual: keyDiff.IsEqual && valueDiff.IsEqual && headersDiff.IsEqual,
+		Key:     keyDiff,
+		Value:   valueDiff,
+		Headers: headersDiff,
+	}, nil
+}
+
+// diffPayloads compares the normalized JSON representation of two deserialized payloads, so that payloads with
+// different encodings (e.g. JSON and Avro) can be compared too.
+func diffPayloads(left interface{}, right interface{}) (PayloadDiff, error) {
+	leftObj, leftEncoding, leftSchemaID, err := normalizePayloadForDiff(left)
+	if err != nil {
+		return PayloadDiff{}, err
+	}
+	rightObj, rightEncoding, rightSchemaID, err := normalizePayloadForDiff(right)
+	if err != nil {
+		return PayloadDiff{}, err
+	}
+
+	changes := diffValues("$", leftObj, rightObj)
+	schemaIDChanged := leftSchemaID != rightSchemaID
+	return PayloadDiff{
+		IsEqual:         len(changes) == 0 && !schemaIDChanged,
+		LeftEncoding:    leftEncoding,
+		RightEncoding:   rightEncoding,
+		LeftSchemaID:    leftSchemaID,
+		RightSchemaID:   rightSchemaID,
+		SchemaIDChanged: schemaIDChanged,
+		Changes:         changes,
+	}, nil
+}
+
+// normalizePayloadForDiff marshals the deserialized payload as it would be sent to the frontend and unmarshals it
+// again into a generic Go type. Numbers are kept as json.Number, so that large integers are compared exactly.
+func normalizePayloadForDiff(payload interface{}) (interface{}, string, uint32, error) {
+	if payload == nil || reflect.ValueOf(payload).IsNil() {
+		return nil, "", 0, nil
+	}
+
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	var envelope struct {
+		Payload  interface{} `json:"payload"`
+		Encoding string      `json:"encoding"`
+		SchemaID uint32      `json:"schemaId"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(serialized))
+	decoder.UseNumber()
+	err = decoder.Decode(&envelope)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	return envelope.Payload, envelope.Encoding, envelope.SchemaID, nil
+}
+
+func headersToObject(headers []kafka.MessageHeader) (map[string]interface{}, error) {
+	obj := make(map[string]interface{}, len(headers))
+	for _, header := range headers {
+		value, _, _, err := normalizePayloadForDiff(header.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to normalize header '%v': %w", header.Key, err)
+		}
+		obj[header.Key] = value
+	}
+
+	return obj, nil
+}
+
+// diffValues recursively compares two generic JSON values and returns all differences. Objects are compared
+// key by key and arrays index by index, all other values are compared as a whole.
+func diffValues(path string, left interface{}, right interface{}) []DiffEntry {
+	changes := make([]DiffEntry, 0)
+
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(l)+len(r))
+		for key := range l {
+			keys = append(keys, key)
+		}
+		for key := range r {
+			if _, exists := l[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := fmt.Sprintf("%v.%v", path, key)
+			leftChild, leftExists := l[key]
+			rightChild, rightExists := r[key]
+			switch {
+			case !rightExists:
+				changes = append(changes, DiffEntry{Path: childPath, Op: DiffOpRemoved, Left: leftChild})
+			case !leftExists:
+				changes = append(changes, DiffEntry{Path: childPath, Op: DiffOpAdded, Right: rightChild})
+			default:
+				changes = append(changes, diffValues(childPath, leftChild, rightChild)...)
+			}
+		}
+		return changes
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(l) || i < len(r); i++ {
+			childPath := fmt.Sprintf("%v[%d]", path, i)
+			switch {
+			case i >= len(r):
+				changes = append(changes, DiffEntry{Path: childPath, Op: DiffOpRemoved, Left: l[i]})
+			case i >= len(l):
+				changes = append(changes, DiffEntry{Path: childPath, Op: DiffOpAdded, Right: r[i]})
+			default:
+				changes = append(changes, diffValues(childPath, l[i], r[i])...)
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		changes = append(changes, DiffEntry{Path: path, Op: DiffOpChanged, Left: left, Right: right})
+	}
+
+	return changes
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffValues(t *testing.T) {
+	var left, right interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"a","tags":["x","y"],"nested":{"removed":true}}`), &left))
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"b","tags":["x"],"nested":{"added":true}}`), &right))
+
+	expected := []DiffEntry{
+		{Path: "$.name", Op: DiffOpChanged, Left: "a", Right: "b"},
+		{Path: "$.nested.added", Op: DiffOpAdded, Right: true},
+		{Path: "$.nested.removed", Op: DiffOpRemoved, Left: true},
+		{Path: "$.tags[1]", Op: DiffOpRemoved, Left: "y"},
+	}
+	assert.Equal(t, expected, diffValues("$", left, right))
+	assert.Empty(t, diffValues("$", left, left))
+}
+
+func TestDiffValues_TypeChange(t *testing.T) {
+	changes := diffValues("$", map[string]interface{}{"a": 1.0}, "text")
+	require.Len(t, changes, 1)
+	assert.Equal(t, DiffOpChanged, changes[0].Op)
+	assert.Equal(t, "$", changes[0].Path)
+}
+
+func TestDiffPayloads_LargeIntegers(t *testing.T) {
+	newPayload := func(value string) map[string]interface{} {
+		return map[string]interface{}{"payload": json.RawMessage(value), "encoding": "json"}
+	}
+
+	tt := []struct {
+		name      string
+		left      string
+		right     string
+		wantEqual bool
+	}{
+		{"beyond float64 precision", `{"id":9007199254740993}`, `{"id":9007199254740992}`, false},
+		{"same large integer", `{"id":9007199254740993}`, `{"id":9007199254740993}`, true},
+		{"decimals", `{"amount":12.5}`, `{"amount":12.51}`, false},
+	}
+
+	for _, test := range tt {
+		diff, err := diffPayloads(newPayload(test.left), newPayload(test.right))
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.wantEqual, diff.IsEqual, test.name)
+	}
+
+	diff, err := diffPayloads(newPayload(`{"id":9007199254740993}`), newPayload(`{"id":9007199254740992}`))
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, json.Number("9007199254740993"), diff.Changes[0].Left)
+	assert.Equal(t, json.Number("9007199254740992"), diff.Changes[0].Right)
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// EffectiveQuotaResponse contains the quotas that apply to a connection of the given user and client id.
+type EffectiveQuotaResponse struct {
+	User     string           `json:"user"`
+	ClientID string           `json:"clientId"`
+	Quotas   []EffectiveQuota `json:"quotas"`
+}
+
+// EffectiveQuota is the resolved value of a single quota along with the entity it has been configured on.
+// If no entity configures this quota, Value and Entity are nil and the broker's defaults (usually unlimited)
+// apply.
+type EffectiveQuota struct {
+	Key    string                 `json:"key"`
+	Value  *float64               `json:"value"`
+	Entity []QuotaEntityComponent `json:"entity"`
+}
+
+// quotaEntity is a (possibly partial) quota entity that is used to look up configured quotas. A nil name refers
+// to the default entity, an unset type is not part of the entity.
+type quotaEntity struct {
+	HasUser     bool
+	User        *string
+	HasClientID bool
+	ClientID    *string
+}
+
+func (e quotaEntity) String() string {
+	parts := make([]string, 0, 2)
+	name := func(n *string) string {
+		if n == nil {
+			return "<default>"
+		}
+		return "=" + *n
+	}
+	if e.HasUser {
+		parts = append(parts, QuotaEntityTypeUser+name(e.User))
+	}
+	if e.HasClientID {
+		parts = append(parts, QuotaEntityTypeClientID+name(e.ClientID))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (e quotaEntity) components() []QuotaEntityComponent {
+	components := make([]QuotaEntityComponent, 0, 2)
+	if e.HasUser {
+		components = append(components, QuotaEntityComponent{Type: QuotaEntityTypeUser, Name: e.User})
+	}
+	if e.HasClientID {
+		components = append(components, QuotaEntityComponent{Type: QuotaEntityTypeClientID, Name: e.ClientID})
+	}
+	return components
+}
+
+// quotaEntityPrecedence returns the quota entities in the order of Kafka's precedence for the given user and client id.
+// See: https://kafka.apache.org/documentation/#design_quotasconfig
+func quotaEntityPrecedence(user string, clientID string) []quotaEntity {
+	userName := &user
+	clientName := &clientID
+
+	entities := make([]quotaEntity, 0, 8)
+	if user != "" {
+		entities = append(entities,
+			quotaEntity{HasUser: true, User: userName, HasClientID: true, ClientID: clientName},
+			quotaEntity{HasUser: true, User: userName, HasClientID: true, ClientID: nil},
+			quotaEntity{HasUser: true, User: userName},
+			quotaEntity{HasUser: true, User: nil, HasClientID: true, ClientID: clientName},
+			quotaEntity{HasUser: true, User: nil, HasClientID: true, ClientID: nil},
+			quotaEntity{HasUser: true, User: nil},
+		)
+	}
+	entities = append(entities,
+		quotaEntity{HasClientID: true, ClientID: clientName},
+		quotaEntity{HasClientID: true, ClientID: nil},
+	)
+	return entities
+}
+
+// ResolveEffectiveQuota returns the quotas that apply to connections of the given user and client id by applying
+// Kafka's precedence rules to all configured quotas. Each quota key is resolved on its own, so that different
+// quotas may be inherited from different entities. If user is empty, only client-id quotas are considered.
+func (s *Service) ResolveEffectiveQuota(ctx context.Context, user string, clientID string) (*EffectiveQuotaResponse, *rest.Error) {
+	quotas, err := s.kafkaSvc.DescribeQuotas(ctx)
+	if err == nil {
+		err = kerr.ErrorForCode(quotas.ErrorCode)
+	}
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe client quotas: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	return &EffectiveQuotaResponse{
+		User:     user,
+		ClientID: clientID,
+		Quotas:   resolveEffectiveQuotas(quotas.Entries, user, clientID),
+	}, nil
+}
+
+func resolveEffectiveQuotas(entries []kmsg.DescribeClientQuotasResponseEntry, user string, clientID string) []EffectiveQuota {
+	// Index the configured quota values by entity
+	valuesByEntity := make(map[string]map[string]float64)
+	for _, entry := range entries {
+		entity := quotaEntity{}
+		for _, component := range entry.Entity {
+			switch component.Type {
+			case QuotaEntityTypeUser:
+				entity.HasUser = true
+				entity.User = component.Name
+			case QuotaEntityTypeClientID:
+				entity.HasClientID = true
+				entity.ClientID = component.Name
+			}
+		}
+		values := make(map[string]float64, len(entry.Values))
+		for _, value := range entry.Values {
+			values[value.Key] = value.Value
+		}
+		valuesByEntity[entity.String()] = values
+	}
+
+	keys := []string{QuotaKeyProducerByteRate, QuotaKeyConsumerByteRate, QuotaKeyRequestPercentage, QuotaKeyControllerMutationRate}
+	precedence := quotaEntityPrecedence(user, clientID)
+	effective := make([]EffectiveQuota, len(keys))
+	for i, key := range keys {
+		effective[i] = EffectiveQuota{Key: key}
+		for _, entity := range precedence {
+			value, exists := valuesByEntity[entity.String()][key]
+			if !exists {
+				continue
+			}
+			v := value
+			effective[i].Value = &v
+			effective[i].Entity = entity.components()
+			break
+		}
+	}
+
+	return effective
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestResolveEffectiveQuotas(t *testing.T) {
+	alice := "alice"
+	app := "app"
+	rate100, rate200, rate400 := 100.0, 200.0, 400.0
+	entries := []kmsg.DescribeClientQuotasResponseEntry{
+		{
+			Entity: []kmsg.DescribeClientQuotasResponseEntryEntity{{Type: QuotaEntityTypeClientID, Name: &app}},
+			Values: []kmsg.DescribeClientQuotasResponseEntryValue{{Key: QuotaKeyProducerByteRate, Value: 100}},
+		},
+		{
+			Entity: []kmsg.DescribeClientQuotasResponseEntryEntity{{Type: QuotaEntityTypeUser, Name: nil}},
+			Values: []kmsg.DescribeClientQuotasResponseEntryValue{
+				{Key: QuotaKeyProducerByteRate, Value: 200},
+				{Key: QuotaKeyConsumerByteRate, Value: 300},
+			},
+		},
+		{
+			Entity: []kmsg.DescribeClientQuotasResponseEntryEntity{
+				{Type: QuotaEntityTypeUser, Name: &alice},
+				{Type: QuotaEntityTypeClientID, Name: nil},
+			},
+			Values: []kmsg.DescribeClientQuotasResponseEntryValue{{Key: QuotaKeyConsumerByteRate, Value: 400}},
+		},
+	}
+
+	tt := []struct {
+		name           string
+		user           string
+		clientID       string
+		key            string
+		expectedValue  *float64
+		expectedEntity []QuotaEntityComponent
+	}{
+		{
+			name:           "default user quota takes precedence over the client-id quota",
+			user:           alice,
+			clientID:       app,
+			key:            QuotaKeyProducerByteRate,
+			expectedValue:  &rate200,
+			expectedEntity: []QuotaEntityComponent{{Type: QuotaEntityTypeUser, Name: nil}},
+		},
+		{
+			name:          "user=alice,client-id=<default> takes precedence over user=<default>",
+			user:          alice,
+			clientID:      app,
+			key:           QuotaKeyConsumerByteRate,
+			expectedValue: &rate400,
+			expectedEntity: []QuotaEntityComponent{
+				{Type: QuotaEntityTypeUser, Name: &alice},
+				{Type: QuotaEntityTypeClientID, Name: nil},
+			},
+		},
+		{
+			name:     "not configured at all",
+			user:     alice,
+			clientID: app,
+			key:      QuotaKeyRequestPercentage,
+		},
+		{
+			name:           "without user only client-id quotas apply",
+			user:           "",
+			clientID:       app,
+			key:            QuotaKeyProducerByteRate,
+			expectedValue:  &rate100,
+			expectedEntity: []QuotaEntityComponent{{Type: QuotaEntityTypeClientID, Name: &app}},
+		},
+	}
+
+	for _, test := range tt {
+		quotas := resolveEffectiveQuotas(entries, test.user, test.clientID)
+		var quota *EffectiveQuota
+		for i := range quotas {
+			if quotas[i].Key == test.key {
+				quota = &quotas[i]
+			}
+		}
+		require.NotNil(t, quota, test.name)
+		assert.Equal(t, test.expectedValue, quota.Value, test.name)
+		if test.expectedValue != nil {
+			assert.Equal(t, test.expectedEntity, quota.Entity, test.name)
+		}
+	}
+}
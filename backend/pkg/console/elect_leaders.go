@@ -0,0 +1,229 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	// ElectionTypePreferred elects the preferred replica (the first replica) as leader.
+	ElectionTypePreferred = "preferred"
+
+	// ElectionTypeUnclean elects an out of sync replica as leader if no in sync replica is available. This
+	// may cause data loss.
+	ElectionTypeUnclean = "unclean"
+)
+
+// ElectLeadersTopic selects the partitions of a topic whose leaders shall be elected. All partitions of the topic
+// are selected if no partition ids are set.
+type ElectLeadersTopic struct {
+	TopicName    string  `json:"topicName"`
+	PartitionIDs []int32 `json:"partitionIds"`
+}
+
+// ElectLeadersPartitionResult is the result of a leader election for a single partition.
+type ElectLeadersPartitionResult struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+
+	// IsElected is false if the election failed or if it was not needed, because the partition is already led by
+	// the preferred replica or by an in sync replica.
+	IsElected bool   `json:"isElected"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ElectLeaders triggers a preferred or unclean leader election for the given topics. If no topic is passed leaders
+// will be elected for all partitions in the cluster.
+func (s *Service) ElectLeaders(ctx context.Context, electionType string, topics []ElectLeadersTopic) ([]ElectLeadersPartitionResult, *rest.Error) {
+	var kafkaElectionType int8
+	switch electionType {
+	case ElectionTypePreferred:
+		kafkaElectionType = 0
+	case ElectionTypeUnclean:
+		kafkaElectionType = 1
+	default:
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("unknown election type '%v'", electionType),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Election type must be either '%v' or '%v'", ElectionTypePreferred, ElectionTypeUnclean),
+			IsSilent: false,
+		}
+	}
+
+	electTopics, restErr := s.buildElectLeadersTopics(ctx, topics)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res, err := s.kafkaSvc.ElectLeaders(ctx, kafkaElectionType, electTopics)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to execute elect leaders request: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to elect leaders: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	results := make([]ElectLeadersPartitionResult, 0)
+	for _, topic := range res.Topics {
+		for _, partition := range topic.Partitions {
+			result := ElectLeadersPartitionResult{
+				TopicName:   topic.Topic,
+				PartitionID: partition.Partition,
+				IsElected:   true,
+			}
+			err := kerr.ErrorForCode(partition.ErrorCode)
+			if err != nil {
+				result.IsElected = false
+				if !errors.Is(err, kerr.ElectionNotNeeded) {
+					result.Error = err.Error()
+					if partition.ErrorMessage != nil {
+						result.Error = fmt.Sprintf("%v: %v", err.Error(), *partition.ErrorMessage)
+					}
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].TopicName != results[j].TopicName {
+			return results[i].TopicName < results[j].TopicName
+		}
+		return results[i].PartitionID < results[j].PartitionID
+	})
+
+	return results, nil
+}
+
+// buildElectLeadersTopics converts the requested topics into elect leader request topics. Topics without
+// partition ids are expanded to all partitions of that topic.
+func (s *Service) buildElectLeadersTopics(ctx context.Context, topics []ElectLeadersTopic) ([]kmsg.ElectLeadersRequestTopic, *rest.Error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+
+	topicNamesToExpand := make([]string, 0)
+	for _, topic := range topics {
+		if len(topic.PartitionIDs) == 0 {
+			topicNamesToExpand = append(topicNamesToExpand, topic.TopicName)
+		}
+	}
+	partitionIDsByTopic := make(map[string][]int32)
+	if len(topicNamesToExpand) > 0 {
+		metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNamesToExpand)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to get topic metadata from cluster: '%v'", err.Error()),
+				IsSilent: false,
+			}
+		}
+		for _, topic := range metadata.Topics {
+			if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+				return nil, &rest.Error{
+					Err:      err,
+					Status:   http.StatusBadRequest,
+					Message:  fmt.Sprintf("Failed to get metadata for topic '%v': %v", *topic.Topic, err.Error()),
+					IsSilent: false,
+				}
+			}
+			for _, partition := range topic.Partitions {
+				partitionIDsByTopic[*topic.Topic] = append(partitionIDsByTopic[*topic.Topic], partition.Partition)
+			}
+		}
+	}
+
+	electTopics := make([]kmsg.ElectLeadersRequestTopic, len(topics))
+	for i, topic := range topics {
+		electTopic := kmsg.NewElectLeadersRequestTopic()
+		electTopic.Topic = topic.TopicName
+		electTopic.Partitions = topic.PartitionIDs
+		if len(topic.PartitionIDs) == 0 {
+			electTopic.Partitions = partitionIDsByTopic[topic.TopicName]
+		}
+		electTopics[i] = electTopic
+	}
+
+	return electTopics, nil
+}
+
+// NonPreferredLeaderPartition is a partition that is not led by its preferred replica.
+type NonPreferredLeaderPartition struct {
+	TopicName       string  `json:"topicName"`
+	PartitionID     int32   `json:"partitionId"`
+	Leader          int32   `json:"leader"`
+	PreferredLeader int32   `json:"preferredLeader"`
+	Replicas        []int32 `json:"replicas"`
+	InSyncReplicas  []int32 `json:"inSyncReplicas"`
+
+	// IsPreferredLeaderInSync indicates whether a preferred leader election would succeed for this partition.
+	IsPreferredLeaderInSync bool `json:"isPreferredLeaderInSync"`
+}
+
+// GetNonPreferredLeaders returns all partitions whose current leader is not the preferred replica.
+func (s *Service) GetNonPreferredLeaders(ctx context.Context) ([]NonPreferredLeaderPartition, *rest.Error) {
+	topicMetadata, restErr := s.getTopicPartitionMetadata(ctx, nil)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	return nonPreferredLeaders(topicMetadata), nil
+}
+
+func nonPreferredLeaders(topicMetadata map[string]TopicDetails) []NonPreferredLeaderPartition {
+	partitions := make([]NonPreferredLeaderPartition, 0)
+	for _, topic := range topicMetadata {
+		for _, partition := range topic.Partitions {
+			if partition.PartitionError != "" || len(partition.Replicas) == 0 {
+				continue
+			}
+			preferredLeader := partition.Replicas[0]
+			if partition.Leader == preferredLeader {
+				continue
+			}
+			partitions = append(partitions, NonPreferredLeaderPartition{
+				TopicName:               topic.TopicName,
+				PartitionID:             partition.ID,
+				Leader:                  partition.Leader,
+				PreferredLeader:         preferredLeader,
+				Replicas:                partition.Replicas,
+				InSyncReplicas:          partition.InSyncReplicas,
+				IsPreferredLeaderInSync: containsInt32(partition.InSyncReplicas, preferredLeader),
+			})
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].TopicName != partitions[j].TopicName {
+			return partitions[i].TopicName < partitions[j].TopicName
+		}
+		return partitions[i].PartitionID < partitions[j].PartitionID
+	})
+
+	return partitions
+}
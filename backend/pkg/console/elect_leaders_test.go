@@ -0,0 +1,39 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonPreferredLeaders(t *testing.T) {
+	topicMetadata := map[string]TopicDetails{
+		"orders": {
+			TopicName: "orders",
+			Partitions: []TopicPartitionDetails{
+				{TopicPartitionMetadata: &TopicPartitionMetadata{ID: 0, Replicas: []int32{1, 2}, InSyncReplicas: []int32{1, 2}, Leader: 1}},
+				{TopicPartitionMetadata: &TopicPartitionMetadata{ID: 1, Replicas: []int32{2, 3}, InSyncReplicas: []int32{2, 3}, Leader: 3}},
+				{TopicPartitionMetadata: &TopicPartitionMetadata{ID: 2, Replicas: []int32{3, 1}, InSyncReplicas: []int32{1}, Leader: 1}},
+				{TopicPartitionMetadata: &TopicPartitionMetadata{ID: 3, PartitionError: "LEADER_NOT_AVAILABLE"}},
+			},
+		},
+	}
+
+	partitions := nonPreferredLeaders(topicMetadata)
+	require.Len(t, partitions, 2)
+	assert.Equal(t, int32(1), partitions[0].PartitionID)
+	assert.Equal(t, int32(2), partitions[0].PreferredLeader)
+	assert.True(t, partitions[0].IsPreferredLeaderInSync)
+	assert.Equal(t, int32(2), partitions[1].PartitionID)
+	assert.False(t, partitions[1].IsPreferredLeaderInSync)
+}
@@ -52,6 +52,11 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Method:   "GET",
 			Requests: []kmsg.Request{&kmsg.DescribeConfigsRequest{}},
 		},
+		{
+			URL:      "/api/topics/{topicName}/partitions",
+			Method:   "PATCH",
+			Requests: []kmsg.Request{&kmsg.CreatePartitionsRequest{}},
+		},
 		{
 			URL:      "/api/consumer-groups",
 			Method:   "GET",
@@ -82,6 +87,11 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Method:   "PATCH",
 			Requests: []kmsg.Request{&kmsg.IncrementalAlterConfigsRequest{}, &kmsg.AlterPartitionAssignmentsRequest{}},
 		},
+		{
+			URL:      "/api/operations/elect-leaders",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.ElectLeadersRequest{}},
+		},
 		{
 			URL:      "/api/quotas",
 			Method:   "GET",
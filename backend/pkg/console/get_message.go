@@ -0,0 +1,70 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// GetMessage fetches the record at the given offset and returns it deserialized. Configured redaction rules are
+// applied unless bypassRedaction is true.
+func (s *Service) GetMessage(ctx context.Context, topicName string, partitionID int32, offset int64, bypassRedaction bool) (*kafka.TopicMessage, *rest.Error) {
+	record, restErr := s.fetchRecord(ctx, topicName, partitionID, offset)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	return s.kafkaSvc.DeserializeTopicMessage(record, bypassRedaction), nil
+}
+
+// fetchRecord consumes the record at the given offset and converts all possible errors into REST errors.
+func (s *Service) fetchRecord(ctx context.Context, topicName string, partitionID int32, offset int64) (*kgo.Record, *rest.Error) {
+	internalLogs := []zapcore.Field{
+		zap.String("topic_name", topicName),
+		zap.Int32("partition_id", partitionID),
+		zap.Int64("offset", offset),
+	}
+
+	// Do not wait forever in case the partition leader is unavailable
+	fetchCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	record, err := s.kafkaSvc.FetchRecord(fetchCtx, topicName, partitionID, offset)
+	if err != nil {
+		if errors.Is(err, kafka.ErrRecordNotFound) {
+			return nil, &rest.Error{
+				Err:          err,
+				Status:       http.StatusNotFound,
+				Message:      fmt.Sprintf("Requested record does not exist: %v", err.Error()),
+				InternalLogs: internalLogs,
+				IsSilent:     true,
+			}
+		}
+		return nil, &rest.Error{
+			Err:          err,
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to fetch record: %v", err.Error()),
+			InternalLogs: internalLogs,
+			IsSilent:     false,
+		}
+	}
+
+	return record, nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrGroupEventsNotEnabled is returned if group events are requested, but the group watcher is not enabled.
+var ErrGroupEventsNotEnabled = errors.New("group events are not enabled")
+
+// Types of events that are recorded for consumer groups.
+const (
+	GroupEventGroupCreated      = "GROUP_CREATED"
+	GroupEventStateChanged      = "STATE_CHANGED"
+	GroupEventMemberJoined      = "MEMBER_JOINED"
+	GroupEventMemberLeft        = "MEMBER_LEFT"
+	GroupEventAssignmentChanged = "ASSIGNMENT_CHANGED"
+)
+
+// GroupEvent is a single observed change of a consumer group.
+type GroupEvent struct {
+	Timestamp int64  `json:"timestamp"` // Unix timestamp in ms
+	Type      string `json:"type"`
+
+	// PreviousState and State are set for state changes and group creations.
+	PreviousState string `json:"previousState,omitempty"`
+	State         string `json:"state,omitempty"`
+
+	// Member and assignment details are set for membership and assignment changes.
+	MemberID            string                  `json:"memberId,omitempty"`
+	ClientID            string                  `json:"clientId,omitempty"`
+	ClientHost          string                  `json:"clientHost,omitempty"`
+	PreviousAssignments []GroupMemberAssignment `json:"previousAssignments,omitempty"`
+	Assignments         []GroupMemberAssignment `json:"assignments,omitempty"`
+
+	// PreviousConnectAssignment and ConnectAssignment are set for Kafka Connect workers instead.
+	PreviousConnectAssignment *GroupMemberConnectAssignment `json:"previousConnectAssignment,omitempty"`
+	ConnectAssignment         *GroupMemberConnectAssignment `json:"connectAssignment,omitempty"`
+}
+
+// ConsumerGroupEventLog contains all recorded events of a consumer group, oldest first.
+type ConsumerGroupEventLog struct {
+	GroupID string `json:"groupId"`
+	State   string `json:"state"`
+
+	// LastRebalanceTimestamp is the time of the last observed rebalance, 0 if no rebalance has been observed.
+	LastRebalanceTimestamp int64 `json:"lastRebalanceTimestamp"`
+
+	// RebalanceCount, MemberJoinCount and MemberLeaveCount count the observed changes since the group is tracked.
+	// Frequently changing counts indicate flapping consumers.
+	RebalanceCount   int `json:"rebalanceCount"`
+	MemberJoinCount  int `json:"memberJoinCount"`
+	MemberLeaveCount int `json:"memberLeaveCount"`
+
+	Events []GroupEvent `json:"events"`
+}
+
+// groupSnapshot is the described state of a consumer group at a point in time.
+type groupSnapshot struct {
+	State   string
+	Members map[string]GroupMemberDescription
+}
+
+// isRebalanceState returns true if the group state indicates a rebalance in progress.
+func isRebalanceState(state string) bool {
+	return strings.EqualFold(state, "PreparingRebalance") || strings.EqualFold(state, "CompletingRebalance")
+}
+
+// diffGroupSnapshots returns all events that explain the changes from the previous to the current snapshot. It
+// also reports whether these changes imply that the group has rebalanced.
+func diffGroupSnapshots(timestamp int64, previous groupSnapshot, current groupSnapshot) ([]GroupEvent, bool) {
+	events := make([]GroupEvent, 0)
+	hasRebalanced := false
+
+	if previous.State != current.State {
+		events = append(events, GroupEvent{
+			Timestamp:     timestamp,
+			Type:          GroupEventStateChanged,
+			PreviousState: previous.State,
+			State:         current.State,
+		})
+		if isRebalanceState(previous.State) || isRebalanceState(current.State) {
+			hasRebalanced = true
+		}
+	}
+
+	memberIDs := make([]string, 0, len(previous.Members)+len(current.Members))
+	for memberID := range previous.Members {
+		memberIDs = append(memberIDs, memberID)
+	}
+	for memberID := range current.Members {
+		if _, exists := previous.Members[memberID]; !exists {
+			memberIDs = append(memberIDs, memberID)
+		}
+	}
+	sort.Strings(memberIDs)
+
+	for _, memberID := range memberIDs {
+		previousMember, wasMember := previous.Members[memberID]
+		currentMember, isMember := current.Members[memberID]
+		switch {
+		case !isMember:
+			events = append(events, GroupEvent{
+				Timestamp:           timestamp,
+				Type:                GroupEventMemberLeft,
+				MemberID:            memberID,
+				ClientID:            previousMember.ClientID,
+				ClientHost:          previousMember.ClientHost,
+				PreviousAssignments: previousMember.Assignments,
+
+				PreviousConnectAssignment: previousMember.ConnectAssignment,
+			})
+			hasRebalanced = true
+		case !wasMember:
+			events = append(events, GroupEvent{
+				Timestamp:   timestamp,
+				Type:        GroupEventMemberJoined,
+				MemberID:    memberID,
+				ClientID:    currentMember.ClientID,
+				ClientHost:  currentMember.ClientHost,
+				Assignments: currentMember.Assignments,
+
+				ConnectAssignment: currentMember.ConnectAssignment,
+			})
+			hasRebalanced = true
+		case hasAssignmentChanged(previousMember, currentMember):
+			events = append(events, GroupEvent{
+				Timestamp:           timestamp,
+				Type:                GroupEventAssignmentChanged,
+				MemberID:            memberID,
+				ClientID:            currentMember.ClientID,
+				ClientHost:          currentMember.ClientHost,
+				PreviousAssignments: previousMember.Assignments,
+				Assignments:         currentMember.Assignments,
+
+				PreviousConnectAssignment: previousMember.ConnectAssignment,
+				ConnectAssignment:         currentMember.ConnectAssignment,
+			})
+			hasRebalanced = true
+		}
+	}
+
+	return events, hasRebalanced
+}
+
+// hasAssignmentChanged returns true if the member has been assigned different partitions, connectors or tasks.
+// Other changes, such as the config offset of Kafka Connect workers, are ignored.
+func hasAssignmentChanged(previous GroupMemberDescription, current GroupMemberDescription) bool {
+	if !reflect.DeepEqual(previous.Assignments, current.Assignments) {
+		return true
+	}
+	if previous.ConnectAssignment == nil || current.ConnectAssignment == nil {
+		return previous.ConnectAssignment != current.ConnectAssignment
+	}
+	return !reflect.DeepEqual(previous.ConnectAssignment.Connectors, current.ConnectAssignment.Connectors) ||
+		!reflect.DeepEqual(previous.ConnectAssignment.Tasks, current.ConnectAssignment.Tasks)
+}
+
+// groupEventLog is the recorded event log of a single group.
+type groupEventLog struct {
+	snapshot               groupSnapshot
+	events                 []GroupEvent
+	lastRebalanceTimestamp int64
+	rebalanceCount         int
+	memberJoinCount        int
+	memberLeaveCount       int
+}
+
+// groupEvents stores the event logs of all tracked consumer groups.
+type groupEvents struct {
+	mutex     sync.RWMutex
+	maxEvents int
+	logs      map[string]*groupEventLog
+}
+
+func newGroupEvents(maxEvents int) *groupEvents {
+	return &groupEvents{
+		maxEvents: maxEvents,
+		logs:      make(map[string]*groupEventLog),
+	}
+}
+
+// observe compares the given group snapshot with the previous one and records the resulting events. Groups that
+// are observed for the first time only record a creation event if isNewGroup is true, because we can not know
+// what happened before we started tracking.
+func (g *groupEvents) observe(timestamp int64, groupID string, snapshot groupSnapshot, isNewGroup bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	log, exists := g.logs[groupID]
+	if !exists {
+		log = &groupEventLog{snapshot: snapshot, events: make([]GroupEvent, 0)}
+		g.logs[groupID] = log
+		if !isNewGroup {
+			return
+		}
+		g.append(log, GroupEvent{Timestamp: timestamp, Type: GroupEventGroupCreated, State: snapshot.State})
+		// Diff against an empty group so that the initial members are recorded as well
+		log.snapshot = groupSnapshot{State: snapshot.State}
+	}
+
+	events, hasRebalanced := diffGroupSnapshots(timestamp, log.snapshot, snapshot)
+	for _, event := range events {
+		switch event.Type {
+		case GroupEventMemberJoined:
+			log.memberJoinCount++
+		case GroupEventMemberLeft:
+			log.memberLeaveCount++
+		}
+		g.append(log, event)
+	}
+	if hasRebalanced {
+		log.lastRebalanceTimestamp = timestamp
+		log.rebalanceCount++
+	}
+	log.snapshot = snapshot
+}
+
+func (g *groupEvents) append(log *groupEventLog, event GroupEvent) {
+	log.events = append(log.events, event)
+	if len(log.events) > g.maxEvents {
+		log.events = log.events[len(log.events)-g.maxEvents:]
+	}
+}
+
+// retain removes the event logs of all groups that are not in the given set of group ids.
+func (g *groupEvents) retain(groupIDs map[string]struct{}) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	for groupID := range g.logs {
+		if _, exists := groupIDs[groupID]; !exists {
+			delete(g.logs, groupID)
+		}
+	}
+}
+
+// eventLog returns a copy of the event log of the given group.
+func (g *groupEvents) eventLog(groupID string) ConsumerGroupEventLog {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	res := ConsumerGroupEventLog{GroupID: groupID, Events: make([]GroupEvent, 0)}
+	log, exists := g.logs[groupID]
+	if !exists {
+		return res
+	}
+	res.State = log.snapshot.State
+	res.LastRebalanceTimestamp = log.lastRebalanceTimestamp
+	res.RebalanceCount = log.rebalanceCount
+	res.MemberJoinCount = log.memberJoinCount
+	res.MemberLeaveCount = log.memberLeaveCount
+	res.Events = append(res.Events, log.events...)
+
+	return res
+}
+
+// GetConsumerGroupEvents returns the recorded membership and state change events of a consumer group.
+func (s *Service) GetConsumerGroupEvents(groupID string) (*ConsumerGroupEventLog, error) {
+	if s.groupWatcher == nil {
+		return nil, ErrGroupEventsNotEnabled
+	}
+
+	eventLog := s.groupWatcher.events.eventLog(groupID)
+	return &eventLog, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffGroupSnapshots(t *testing.T) {
+	memberA := GroupMemberDescription{ID: "a", ClientID: "app", Assignments: []GroupMemberAssignment{{TopicName: "orders", PartitionIDs: []int32{0, 1}}}}
+	memberB := GroupMemberDescription{ID: "b", ClientID: "app", Assignments: []GroupMemberAssignment{{TopicName: "orders", PartitionIDs: []int32{1}}}}
+	memberAReassigned := memberA
+	memberAReassigned.Assignments = []GroupMemberAssignment{{TopicName: "orders", PartitionIDs: []int32{0}}}
+
+	previous := groupSnapshot{State: "Stable", Members: map[string]GroupMemberDescription{"a": memberA}}
+	current := groupSnapshot{State: "Stable", Members: map[string]GroupMemberDescription{"a": memberAReassigned, "b": memberB}}
+
+	events, hasRebalanced := diffGroupSnapshots(1000, previous, current)
+	assert.True(t, hasRebalanced)
+	require.Len(t, events, 2)
+	assert.Equal(t, GroupEventAssignmentChanged, events[0].Type)
+	assert.Equal(t, "a", events[0].MemberID)
+	assert.Equal(t, GroupEventMemberJoined, events[1].Type)
+	assert.Equal(t, "b", events[1].MemberID)
+
+	// Member b leaves and the group starts rebalancing
+	next := groupSnapshot{State: "PreparingRebalance", Members: map[string]GroupMemberDescription{"a": memberAReassigned}}
+	events, hasRebalanced = diffGroupSnapshots(2000, current, next)
+	assert.True(t, hasRebalanced)
+	require.Len(t, events, 2)
+	assert.Equal(t, GroupEvent{Timestamp: 2000, Type: GroupEventStateChanged, PreviousState: "Stable", State: "PreparingRebalance"}, events[0])
+	assert.Equal(t, GroupEventMemberLeft, events[1].Type)
+	assert.Equal(t, "b", events[1].MemberID)
+
+	// Nothing changed
+	events, hasRebalanced = diffGroupSnapshots(3000, next, next)
+	assert.False(t, hasRebalanced)
+	assert.Empty(t, events)
+}
+
+func TestGroupEvents_Observe(t *testing.T) {
+	member := GroupMemberDescription{ID: "a", ClientID: "app"}
+	stable := groupSnapshot{State: "Stable", Members: map[string]GroupMemberDescription{"a": member}}
+	empty := groupSnapshot{State: "Empty", Members: map[string]GroupMemberDescription{}}
+
+	events := newGroupEvents(2)
+	// Groups that exist when tracking starts don't record any events, new groups are reported as created
+	events.observe(1000, "existing", stable, false)
+	events.observe(1000, "new", stable, true)
+	assert.Empty(t, events.eventLog("existing").Events)
+	newLog := events.eventLog("new")
+	require.Len(t, newLog.Events, 2)
+	assert.Equal(t, GroupEventGroupCreated, newLog.Events[0].Type)
+	assert.Equal(t, GroupEventMemberJoined, newLog.Events[1].Type)
+
+	// Only the most recent events are retained, counts are kept
+	events.observe(2000, "existing", empty, false)
+	events.observe(3000, "existing", stable, false)
+	log := events.eventLog("existing")
+	assert.Equal(t, "Stable", log.State)
+	assert.Len(t, log.Events, 2)
+	assert.Equal(t, int64(3000), log.LastRebalanceTimestamp)
+	assert.Equal(t, 2, log.RebalanceCount)
+	assert.Equal(t, 1, log.MemberJoinCount)
+	assert.Equal(t, 1, log.MemberLeaveCount)
+
+	events.retain(map[string]struct{}{"new": {}})
+	assert.Empty(t, events.eventLog("existing").Events)
+	assert.Equal(t, "", events.eventLog("existing").State)
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+	"go.uber.org/zap"
+)
+
+// groupWatcher periodically describes all consumer groups and records membership and state changes.
+type groupWatcher struct {
+	cfg      ConfigGroupEvents
+	logger   *zap.Logger
+	kafkaSvc *kafka.Service
+	events   *groupEvents
+
+	// convertGroups converts the described groups, including the decoded member assignments.
+	convertGroups func(describedGroups *kafka.DescribeConsumerGroupsResponseSharded) []ConsumerGroupOverview
+
+	// hasObserved is true after the first observation. Groups that appear afterwards are reported as created.
+	hasObserved bool
+}
+
+func newGroupWatcher(cfg ConfigGroupEvents, logger *zap.Logger, kafkaSvc *kafka.Service, convertGroups func(*kafka.DescribeConsumerGroupsResponseSharded) []ConsumerGroupOverview) *groupWatcher {
+	return &groupWatcher{
+		cfg:           cfg,
+		logger:        logger.With(zap.String("source", "group_watcher")),
+		kafkaSvc:      kafkaSvc,
+		events:        newGroupEvents(cfg.MaxEvents),
+		convertGroups: convertGroups,
+	}
+}
+
+// Start starts watching all consumer groups in the background.
+func (w *groupWatcher) Start() {
+	go w.run()
+}
+
+func (w *groupWatcher) run() {
+	// Stop watching when we receive a signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	w.observe()
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			w.logger.Info("stopped group watcher", zap.String("reason", "received signal"))
+			return
+		case <-ticker.C:
+			w.observe()
+		}
+	}
+}
+
+func (w *groupWatcher) observe() {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Interval)
+	defer cancel()
+
+	err := w.observeGroups(ctx)
+	if err != nil {
+		w.logger.Warn("failed to observe consumer groups", zap.Error(err))
+	}
+}
+
+func (w *groupWatcher) observeGroups(ctx context.Context) error {
+	groups, err := w.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	groupIDs := groups.GetGroupIDs()
+
+	// Groups are only forgotten if all brokers returned their groups, otherwise a single unavailable broker would
+	// reset the event logs of all groups it coordinates.
+	if groups.RequestsFailed == 0 {
+		existingGroupIDs := make(map[string]struct{}, len(groupIDs))
+		for _, groupID := range groupIDs {
+			existingGroupIDs[groupID] = struct{}{}
+		}
+		w.events.retain(existingGroupIDs)
+	}
+
+	if len(groupIDs) > 0 {
+		describedGroups, err := w.kafkaSvc.DescribeConsumerGroups(ctx, groupIDs)
+		if err != nil {
+			return fmt.Errorf("failed to describe consumer groups: %w", err)
+		}
+
+		timestamp := unixMillis(time.Now())
+		for _, group := range w.convertGroups(describedGroups) {
+			members := make(map[string]GroupMemberDescription, len(group.Members))
+			for _, member := range group.Members {
+				members[member.ID] = member
+			}
+			w.events.observe(timestamp, group.GroupID, groupSnapshot{State: group.State, Members: members}, w.hasObserved)
+		}
+	}
+	w.hasObserved = true
+
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+// InspectMessage fetches a single record and returns a byte-level breakdown of it, including the results of all
+// decoders in the deserializer chain. Because the raw bytes can not be redacted, records of topics with redaction
+// rules can only be inspected if bypassRedaction is true.
+func (s *Service) InspectMessage(ctx context.Context, topicName string, partitionID int32, offset int64, bypassRedaction bool) (*kafka.RecordInspection, *rest.Error) {
+	if !bypassRedaction && s.kafkaSvc.HasRedactionRules(topicName) {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("inspecting messages of topics with redaction rules requires permissions to bypass redaction"),
+			Status:   http.StatusForbidden,
+			Message:  fmt.Sprintf("Messages in topic '%v' are subject to redaction and can therefore not be inspected", topicName),
+			IsSilent: false,
+		}
+	}
+
+	record, restErr := s.fetchRecord(ctx, topicName, partitionID, offset)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	return s.kafkaSvc.Deserializer.InspectRecord(record), nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+// lagMetricsWindowSamples is the number of most recent samples that are used to compute the rates that are
+// exported as Prometheus metrics.
+const lagMetricsWindowSamples = 10
+
+// lagCollector periodically records the lag of all consumer groups into the lag history and exports the
+// summed lag per group and topic as Prometheus metrics.
+type lagCollector struct {
+	cfg     ConfigLagHistory
+	logger  *zap.Logger
+	history *lagHistory
+
+	// fetchLags returns the current lags of all consumer groups, where the group id is the key.
+	fetchLags func(ctx context.Context) (map[string][]GroupTopicOffsets, error)
+
+	topicLag            *prometheus.GaugeVec
+	topicLagRate        *prometheus.GaugeVec
+	topicCatchUpSeconds *prometheus.GaugeVec
+
+	// exportedLabels are the label values (group id and topic name) of all exported metrics, so that metrics of
+	// groups and topics that are no longer recorded can be removed. Only accessed by the collector goroutine.
+	exportedLabels map[[2]string]struct{}
+}
+
+func newLagCollector(cfg ConfigLagHistory, logger *zap.Logger, metricsNamespace string, fetchLags func(ctx context.Context) (map[string][]GroupTopicOffsets, error)) *lagCollector {
+	labels := []string{"group_id", "topic_name"}
+	return &lagCollector{
+		cfg:       cfg,
+		logger:    logger.With(zap.String("source", "lag_collector")),
+		history:   newLagHistory(cfg.MaxSamples),
+		fetchLags: fetchLags,
+
+		exportedLabels: make(map[[2]string]struct{}),
+
+		topicLag: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "consumer_group",
+			Name:      "topic_lag",
+			Help:      "Summed lag of all partitions of a topic for a consumer group",
+		}, labels),
+		topicLagRate: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "consumer_group",
+			Name:      "topic_lag_rate",
+			Help:      "Change of the summed topic lag per second. A positive rate means the group is falling behind",
+		}, labels),
+		topicCatchUpSeconds: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "consumer_group",
+			Name:      "topic_catch_up_seconds",
+			Help:      "Estimated seconds until the topic lag is zero. Not set if the lag does not decrease",
+		}, labels),
+	}
+}
+
+// Start restores the persisted lag history (if configured) and starts collecting lags in the background.
+func (c *lagCollector) Start() {
+	if c.cfg.PersistencePath != "" {
+		err := c.restore()
+		if err != nil {
+			c.logger.Warn("failed to restore persisted lag history", zap.String("path", c.cfg.PersistencePath), zap.Error(err))
+		}
+	}
+
+	go c.run()
+}
+
+func (c *lagCollector) run() {
+	// Stop collecting when we receive a signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	c.collect()
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			c.logger.Info("stopped lag collector", zap.String("reason", "received signal"))
+			return
+		case <-ticker.C:
+			c.collect()
+		}
+	}
+}
+
+func (c *lagCollector) collect() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Interval)
+	defer cancel()
+
+	lags, err := c.fetchLags(ctx)
+	if err != nil {
+		c.logger.Warn("failed to collect consumer group lags", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	retention := c.cfg.Interval * time.Duration(c.cfg.MaxSamples)
+	c.history.record(unixMillis(now), lags, unixMillis(now.Add(-retention)))
+	c.updateMetrics()
+
+	if c.cfg.PersistencePath != "" {
+		err := c.persist()
+		if err != nil {
+			c.logger.Warn("failed to persist lag history", zap.String("path", c.cfg.PersistencePath), zap.Error(err))
+		}
+	}
+}
+
+// updateMetrics sets the metrics of all recorded groups and topics. Metrics are updated in place rather than
+// being reset, so that scrapes in between never observe missing series.
+func (c *lagCollector) updateMetrics() {
+	labels := make(map[[2]string]struct{})
+	for groupID, topics := range c.history.samplesByGroup() {
+		for topicName, partitions := range topics {
+			samples := sumLagSamples(partitions)
+			if len(samples) == 0 {
+				continue
+			}
+			if len(samples) > lagMetricsWindowSamples {
+				samples = samples[len(samples)-lagMetricsWindowSamples:]
+			}
+			estimate := estimateLag(samples)
+
+			labels[[2]string{groupID, topicName}] = struct{}{}
+			c.topicLag.WithLabelValues(groupID, topicName).Set(float64(samples[len(samples)-1].Lag))
+			c.topicLagRate.WithLabelValues(groupID, topicName).Set(estimate.LagRate)
+			if estimate.EstimatedCatchUpSeconds != nil {
+				c.topicCatchUpSeconds.WithLabelValues(groupID, topicName).Set(*estimate.EstimatedCatchUpSeconds)
+			} else {
+				c.topicCatchUpSeconds.DeleteLabelValues(groupID, topicName)
+			}
+		}
+	}
+
+	for label := range c.exportedLabels {
+		if _, exists := labels[label]; exists {
+			continue
+		}
+		c.topicLag.DeleteLabelValues(label[0], label[1])
+		c.topicLagRate.DeleteLabelValues(label[0], label[1])
+		c.topicCatchUpSeconds.DeleteLabelValues(label[0], label[1])
+	}
+	c.exportedLabels = labels
+}
+
+// lagHistoryFile is the format in which the lag history is persisted.
+type lagHistoryFile struct {
+	Partitions []lagHistoryFilePartition `json:"partitions"`
+}
+
+type lagHistoryFilePartition struct {
+	GroupID     string      `json:"groupId"`
+	TopicName   string      `json:"topicName"`
+	PartitionID int32       `json:"partitionId"`
+	Samples     []LagSample `json:"samples"`
+}
+
+// persist writes the lag history to the configured file. The file is replaced atomically so that a crash can
+// not leave a partially written file behind.
+func (c *lagCollector) persist() error {
+	file := lagHistoryFile{Partitions: make([]lagHistoryFilePartition, 0)}
+	for groupID, topics := range c.history.samplesByGroup() {
+		for topicName, partitions := range topics {
+			for partitionID, samples := range partitions {
+				file.Partitions = append(file.Partitions, lagHistoryFilePartition{
+					GroupID:     groupID,
+					TopicName:   topicName,
+					PartitionID: partitionID,
+					Samples:     samples,
+				})
+			}
+		}
+	}
+
+	serialized, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to serialize lag history: %w", err)
+	}
+	tmpPath := c.cfg.PersistencePath + ".tmp"
+	err = ioutil.WriteFile(tmpPath, serialized, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write lag history: %w", err)
+	}
+
+	return os.Rename(tmpPath, c.cfg.PersistencePath)
+}
+
+// restore loads the persisted lag history. A missing file is not considered an error.
+func (c *lagCollector) restore() error {
+	serialized, err := ioutil.ReadFile(c.cfg.PersistencePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read lag history: %w", err)
+	}
+
+	var file lagHistoryFile
+	err = json.Unmarshal(serialized, &file)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize lag history: %w", err)
+	}
+
+	restoredSamples := 0
+	for _, partition := range file.Partitions {
+		key := lagHistoryKey{GroupID: partition.GroupID, TopicName: partition.TopicName, PartitionID: partition.PartitionID}
+		for _, sample := range partition.Samples {
+			c.history.add(key, sample)
+			restoredSamples++
+		}
+	}
+	c.logger.Info("restored persisted lag history", zap.Int("partitions", len(file.Partitions)), zap.Int("samples", restoredSamples))
+
+	return nil
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,258 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrLagHistoryNotEnabled is returned if the lag history is requested, but the lag collector is not enabled.
+var ErrLagHistoryNotEnabled = errors.New("lag history is not enabled")
+
+// LagSample is the lag of a consumer group on a partition (or the summed lag on a topic) at a point in time.
+type LagSample struct {
+	Timestamp     int64 `json:"timestamp"` // Unix timestamp in ms
+	GroupOffset   int64 `json:"groupOffset"`
+	HighWaterMark int64 `json:"highWaterMark"`
+	Lag           int64 `json:"lag"`
+}
+
+// LagEstimate describes how the lag developed within the recorded samples.
+type LagEstimate struct {
+	// ConsumeRate is the number of consumed messages per second.
+	ConsumeRate float64 `json:"consumeRate"`
+	// ProduceRate is the number of produced messages per second.
+	ProduceRate float64 `json:"produceRate"`
+	// LagRate is the change of the lag per second. A positive rate means the group is falling behind.
+	LagRate float64 `json:"lagRate"`
+	// EstimatedCatchUpSeconds is the estimated time until the lag is zero, assuming the rates stay constant.
+	// It is nil if the lag does not decrease.
+	EstimatedCatchUpSeconds *float64 `json:"estimatedCatchUpSeconds"`
+}
+
+// lagRingBuffer retains the most recent samples up to its capacity.
+type lagRingBuffer struct {
+	samples []LagSample
+	start   int // index of the oldest sample
+	size    int
+}
+
+func newLagRingBuffer(capacity int) *lagRingBuffer {
+	return &lagRingBuffer{samples: make([]LagSample, capacity)}
+}
+
+// Add appends a sample and overwrites the oldest sample if the buffer is full.
+func (b *lagRingBuffer) Add(sample LagSample) {
+	if b.size < len(b.samples) {
+		b.samples[(b.start+b.size)%len(b.samples)] = sample
+		b.size++
+		return
+	}
+	b.samples[b.start] = sample
+	b.start = (b.start + 1) % len(b.samples)
+}
+
+// Samples returns a copy of all retained samples, oldest first.
+func (b *lagRingBuffer) Samples() []LagSample {
+	samples := make([]LagSample, b.size)
+	for i := 0; i < b.size; i++ {
+		samples[i] = b.samples[(b.start+i)%len(b.samples)]
+	}
+	return samples
+}
+
+// Last returns the most recent sample.
+func (b *lagRingBuffer) Last() (LagSample, bool) {
+	if b.size == 0 {
+		return LagSample{}, false
+	}
+	return b.samples[(b.start+b.size-1)%len(b.samples)], true
+}
+
+type lagHistoryKey struct {
+	GroupID     string
+	TopicName   string
+	PartitionID int32
+}
+
+// lagHistory stores the recorded lag samples of all consumer group partitions.
+type lagHistory struct {
+	mutex      sync.RWMutex
+	maxSamples int
+	buffers    map[lagHistoryKey]*lagRingBuffer
+}
+
+func newLagHistory(maxSamples int) *lagHistory {
+	return &lagHistory{
+		maxSamples: maxSamples,
+		buffers:    make(map[lagHistoryKey]*lagRingBuffer),
+	}
+}
+
+// add records a single sample for the given group partition.
+func (h *lagHistory) add(key lagHistoryKey, sample LagSample) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	buffer, exists := h.buffers[key]
+	if !exists {
+		buffer = newLagRingBuffer(h.maxSamples)
+		h.buffers[key] = buffer
+	}
+	buffer.Add(sample)
+}
+
+// record adds a sample for each partition with a group offset. Partitions that have not been recorded since
+// minTimestamp (e.g. because the group has been deleted) are removed.
+func (h *lagHistory) record(timestamp int64, lagsByGroup map[string][]GroupTopicOffsets, minTimestamp int64) {
+	for groupID, topics := range lagsByGroup {
+		for _, topic := range topics {
+			for _, partition := range topic.PartitionOffsets {
+				if partition.Error != "" {
+					continue
+				}
+				key := lagHistoryKey{GroupID: groupID, TopicName: topic.Topic, PartitionID: partition.PartitionID}
+				h.add(key, LagSample{
+					Timestamp:     timestamp,
+					GroupOffset:   partition.GroupOffset,
+					HighWaterMark: partition.HighWaterMark,
+					Lag:           partition.Lag,
+				})
+			}
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for key, buffer := range h.buffers {
+		last, _ := buffer.Last()
+		if last.Timestamp < minTimestamp {
+			delete(h.buffers, key)
+		}
+	}
+}
+
+// samplesByGroup returns all samples as a nested map of: groupID -> topicName -> partitionID -> samples
+func (h *lagHistory) samplesByGroup() map[string]map[string]map[int32][]LagSample {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	res := make(map[string]map[string]map[int32][]LagSample)
+	for key, buffer := range h.buffers {
+		if _, exists := res[key.GroupID]; !exists {
+			res[key.GroupID] = make(map[string]map[int32][]LagSample)
+		}
+		if _, exists := res[key.GroupID][key.TopicName]; !exists {
+			res[key.GroupID][key.TopicName] = make(map[int32][]LagSample)
+		}
+		res[key.GroupID][key.TopicName][key.PartitionID] = buffer.Samples()
+	}
+	return res
+}
+
+// groupSamples returns all samples of the given group as a nested map of: topicName -> partitionID -> samples
+func (h *lagHistory) groupSamples(groupID string) map[string]map[int32][]LagSample {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	res := make(map[string]map[int32][]LagSample)
+	for key, buffer := range h.buffers {
+		if key.GroupID != groupID {
+			continue
+		}
+		if _, exists := res[key.TopicName]; !exists {
+			res[key.TopicName] = make(map[int32][]LagSample)
+		}
+		res[key.TopicName][key.PartitionID] = buffer.Samples()
+	}
+	return res
+}
+
+// sumLagSamples sums the samples of all partitions for each recorded timestamp. Partitions without a sample at
+// a timestamp (e.g. because their offsets could not be fetched) contribute their previous sample, so that a
+// missing sample does not show up as a drop of the summed lag.
+func sumLagSamples(samplesByPartition map[int32][]LagSample) []LagSample {
+	timestampSet := make(map[int64]struct{})
+	for _, samples := range samplesByPartition {
+		for _, sample := range samples {
+			timestampSet[sample.Timestamp] = struct{}{}
+		}
+	}
+	timestamps := make([]int64, 0, len(timestampSet))
+	for timestamp := range timestampSet {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	res := make([]LagSample, len(timestamps))
+	for i, timestamp := range timestamps {
+		res[i].Timestamp = timestamp
+	}
+	for _, samples := range samplesByPartition {
+		// Samples are ordered by timestamp, hence we can walk through both lists at once
+		next := 0
+		for i, timestamp := range timestamps {
+			for next < len(samples) && samples[next].Timestamp <= timestamp {
+				next++
+			}
+			if next == 0 {
+				// The partition has not been recorded yet
+				continue
+			}
+			last := samples[next-1]
+			res[i].GroupOffset += last.GroupOffset
+			res[i].HighWaterMark += last.HighWaterMark
+			res[i].Lag += last.Lag
+		}
+	}
+
+	return res
+}
+
+// estimateLag computes the consume, produce and lag rates between the first and the last sample and estimates
+// how long it takes until the lag is zero.
+func estimateLag(samples []LagSample) LagEstimate {
+	estimate := LagEstimate{}
+	if len(samples) == 0 {
+		return estimate
+	}
+	last := samples[len(samples)-1]
+	if last.Lag == 0 {
+		zero := float64(0)
+		estimate.EstimatedCatchUpSeconds = &zero
+	}
+	if len(samples) < 2 {
+		return estimate
+	}
+
+	first := samples[0]
+	seconds := float64(last.Timestamp-first.Timestamp) / 1000
+	if seconds <= 0 {
+		return estimate
+	}
+	estimate.ConsumeRate = float64(last.GroupOffset-first.GroupOffset) / seconds
+	estimate.ProduceRate = float64(last.HighWaterMark-first.HighWaterMark) / seconds
+	estimate.LagRate = estimate.ProduceRate - estimate.ConsumeRate
+
+	if last.Lag > 0 && estimate.LagRate < 0 {
+		catchUpSeconds := float64(last.Lag) / -estimate.LagRate
+		estimate.EstimatedCatchUpSeconds = &catchUpSeconds
+	}
+
+	return estimate
+}
+
+// samplesSince returns all samples whose timestamp is at or after the given timestamp.
+func samplesSince(samples []LagSample, timestamp int64) []LagSample {
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].Timestamp >= timestamp })
+	return samples[i:]
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLagRingBuffer(t *testing.T) {
+	buffer := newLagRingBuffer(3)
+	_, exists := buffer.Last()
+	assert.False(t, exists)
+
+	for i := int64(1); i <= 5; i++ {
+		buffer.Add(LagSample{Timestamp: i})
+	}
+
+	samples := buffer.Samples()
+	require.Len(t, samples, 3)
+	assert.Equal(t, []int64{3, 4, 5}, []int64{samples[0].Timestamp, samples[1].Timestamp, samples[2].Timestamp})
+	last, exists := buffer.Last()
+	assert.True(t, exists)
+	assert.Equal(t, int64(5), last.Timestamp)
+}
+
+func TestLagHistory_Record(t *testing.T) {
+	history := newLagHistory(10)
+	lags := map[string][]GroupTopicOffsets{
+		"group": {
+			{Topic: "orders", PartitionOffsets: []PartitionOffsets{
+				{PartitionID: 0, GroupOffset: 10, HighWaterMark: 15, Lag: 5},
+				{PartitionID: 1, Error: "leader not available"},
+			}},
+		},
+	}
+	history.record(1000, lags, 0)
+	history.add(lagHistoryKey{GroupID: "deleted", TopicName: "orders"}, LagSample{Timestamp: 500})
+	history.record(2000, lags, 1000)
+
+	samples := history.samplesByGroup()
+	require.Len(t, samples, 1)
+	require.Len(t, samples["group"]["orders"], 1)
+	assert.Len(t, samples["group"]["orders"][0], 2)
+}
+
+func TestSumLagSamples(t *testing.T) {
+	samples := sumLagSamples(map[int32][]LagSample{
+		0: {{Timestamp: 1000, GroupOffset: 10, HighWaterMark: 20, Lag: 10}, {Timestamp: 2000, GroupOffset: 15, HighWaterMark: 20, Lag: 5}},
+		1: {{Timestamp: 2000, GroupOffset: 5, HighWaterMark: 6, Lag: 1}},
+	})
+
+	assert.Equal(t, []LagSample{
+		{Timestamp: 1000, GroupOffset: 10, HighWaterMark: 20, Lag: 10},
+		{Timestamp: 2000, GroupOffset: 20, HighWaterMark: 26, Lag: 6},
+	}, samples)
+
+	// Partition 1 is missing at 2000, its sample of 1000 is carried forward
+	samples = sumLagSamples(map[int32][]LagSample{
+		0: {{Timestamp: 1000, GroupOffset: 10, HighWaterMark: 20, Lag: 10}, {Timestamp: 2000, GroupOffset: 15, HighWaterMark: 20, Lag: 5}},
+		1: {{Timestamp: 1000, GroupOffset: 5, HighWaterMark: 6, Lag: 1}, {Timestamp: 3000, GroupOffset: 6, HighWaterMark: 6, Lag: 0}},
+	})
+	assert.Equal(t, []LagSample{
+		{Timestamp: 1000, GroupOffset: 15, HighWaterMark: 26, Lag: 11},
+		{Timestamp: 2000, GroupOffset: 20, HighWaterMark: 26, Lag: 6},
+		{Timestamp: 3000, GroupOffset: 21, HighWaterMark: 26, Lag: 5},
+	}, samples)
+}
+
+func TestEstimateLag(t *testing.T) {
+	// Consuming 30 msg/s while producing 10 msg/s, the remaining lag of 100 is gone in 5s
+	estimate := estimateLag([]LagSample{
+		{Timestamp: 0, GroupOffset: 0, HighWaterMark: 140, Lag: 140},
+		{Timestamp: 2000, GroupOffset: 60, HighWaterMark: 160, Lag: 100},
+	})
+	assert.Equal(t, float64(30), estimate.ConsumeRate)
+	assert.Equal(t, float64(10), estimate.ProduceRate)
+	assert.Equal(t, float64(-20), estimate.LagRate)
+	require.NotNil(t, estimate.EstimatedCatchUpSeconds)
+	assert.Equal(t, float64(5), *estimate.EstimatedCatchUpSeconds)
+
+	// Falling behind
+	estimate = estimateLag([]LagSample{
+		{Timestamp: 0, GroupOffset: 0, HighWaterMark: 10, Lag: 10},
+		{Timestamp: 1000, GroupOffset: 5, HighWaterMark: 20, Lag: 15},
+	})
+	assert.Equal(t, float64(5), estimate.LagRate)
+	assert.Nil(t, estimate.EstimatedCatchUpSeconds)
+
+	// A single sample without lag has already caught up
+	estimate = estimateLag([]LagSample{{Timestamp: 0, GroupOffset: 10, HighWaterMark: 10}})
+	require.NotNil(t, estimate.EstimatedCatchUpSeconds)
+	assert.Equal(t, float64(0), *estimate.EstimatedCatchUpSeconds)
+}
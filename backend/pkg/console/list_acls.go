@@ -82,3 +82,94 @@ func (s *Service) ListAllACLs(ctx context.Context, req kmsg.DescribeACLsRequest)
 		IsAuthorizerEnabled: true,
 	}, nil
 }
+
+// AclBinding is a single ACL entry along with the resource it applies to.
+type AclBinding struct {
+	ResourceType        string `json:"resourceType" yaml:"resourceType"`
+	ResourceName        string `json:"resourceName" yaml:"resourceName"`
+	ResourcePatternType string `json:"resourcePatternType" yaml:"resourcePatternType"`
+	Principal           string `json:"principal" yaml:"principal"`
+	Host                string `json:"host" yaml:"host"`
+	Operation           string `json:"operation" yaml:"operation"`
+	PermissionType      string `json:"permissionType" yaml:"permissionType"`
+}
+
+// ListACLBindings returns all ACL bindings that match the given filter as a flat list.
+func (s *Service) ListACLBindings(ctx context.Context, req kmsg.DescribeACLsRequest) ([]AclBinding, error) {
+	aclResponses, err := s.kafkaSvc.ListACLs(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ACLs from Kafka: %w", err)
+	}
+
+	err = kerr.ErrorForCode(aclResponses.ErrorCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ACLs from Kafka: %w", err)
+	}
+
+	bindings := make([]AclBinding, 0)
+	for _, resource := range aclResponses.Resources {
+		for _, acl := range resource.ACLs {
+			bindings = append(bindings, AclBinding{
+				ResourceType:        resource.ResourceType.String(),
+				ResourceName:        resource.ResourceName,
+				ResourcePatternType: resource.ResourcePatternType.String(),
+				Principal:           acl.Principal,
+				Host:                acl.Host,
+				Operation:           acl.Operation.String(),
+				PermissionType:      acl.PermissionType.String(),
+			})
+		}
+	}
+
+	return bindings, nil
+}
+
+// ToCreation parses the ACL binding into a kmsg creation and validates it. The resource pattern type defaults
+// to LITERAL and the host defaults to "*".
+func (a AclBinding) ToCreation() (kmsg.CreateACLsRequestCreation, error) {
+	creation := kmsg.NewCreateACLsRequestCreation()
+
+	resourceType, err := kmsg.ParseACLResourceType(a.ResourceType)
+	if err != nil || resourceType == kmsg.ACLResourceTypeAny {
+		return creation, fmt.Errorf("resource type '%v' is invalid", a.ResourceType)
+	}
+	creation.ResourceType = resourceType
+
+	if a.ResourceName == "" {
+		return creation, fmt.Errorf("resource name must be set")
+	}
+	creation.ResourceName = a.ResourceName
+
+	creation.ResourcePatternType = kmsg.ACLResourcePatternTypeLiteral
+	if a.ResourcePatternType != "" {
+		patternType, err := kmsg.ParseACLResourcePatternType(a.ResourcePatternType)
+		if err != nil || (patternType != kmsg.ACLResourcePatternTypeLiteral && patternType != kmsg.ACLResourcePatternTypePrefixed) {
+			return creation, fmt.Errorf("resource pattern type '%v' is invalid, must be LITERAL or PREFIXED", a.ResourcePatternType)
+		}
+		creation.ResourcePatternType = patternType
+	}
+
+	if a.Principal == "" {
+		return creation, fmt.Errorf("principal must be set")
+	}
+	creation.Principal = a.Principal
+
+	creation.Host = "*"
+	if a.Host != "" {
+		creation.Host = a.Host
+	}
+
+	operation, err := kmsg.ParseACLOperation(a.Operation)
+	if err != nil || operation == kmsg.ACLOperationAny {
+		return creation, fmt.Errorf("operation '%v' is invalid", a.Operation)
+	}
+	creation.Operation = operation
+
+	permissionType, err := kmsg.ParseACLPermissionType(a.PermissionType)
+	if err != nil || permissionType == kmsg.ACLPermissionTypeAny {
+		return creation, fmt.Errorf("permission type '%v' is invalid, must be ALLOW or DENY", a.PermissionType)
+	}
+	creation.PermissionType = permissionType
+
+	return creation, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+)
+
+// AclPrincipal is a principal along with all ACL bindings it holds.
+type AclPrincipal struct {
+	Principal string       `json:"principal"`
+	ACLs      []AclBinding `json:"acls"`
+}
+
+// ListACLsByPrincipal returns all ACL bindings grouped by principal. If a principal is given, only the
+// bindings of this principal are returned.
+func (s *Service) ListACLsByPrincipal(ctx context.Context, principal *string) ([]*AclPrincipal, *rest.Error) {
+	req := newDescribeAllACLsRequest()
+	req.Principal = principal
+	bindings, err := s.ListACLBindings(ctx, req)
+	if err != nil {
+		return nil, newACLRequestError("Failed to list ACLs", err)
+	}
+	sortACLBindings(bindings)
+
+	principals := make([]*AclPrincipal, 0)
+	byPrincipal := make(map[string]*AclPrincipal)
+	for _, binding := range bindings {
+		p, exists := byPrincipal[binding.Principal]
+		if !exists {
+			p = &AclPrincipal{Principal: binding.Principal, ACLs: make([]AclBinding, 0)}
+			byPrincipal[binding.Principal] = p
+			principals = append(principals, p)
+		}
+		p.ACLs = append(p.ACLs, binding)
+	}
+	sort.Slice(principals, func(i, j int) bool {
+		return principals[i].Principal < principals[j].Principal
+	})
+
+	return principals, nil
+}
+
+// sortACLBindings sorts the bindings by resource, principal, host, operation and permission so that
+// the order is deterministic.
+func sortACLBindings(bindings []AclBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		keysA := []string{a.ResourceType, a.ResourceName, a.ResourcePatternType, a.Principal, a.Host, a.Operation, a.PermissionType}
+		keysB := []string{b.ResourceType, b.ResourceName, b.ResourcePatternType, b.Principal, b.Host, b.Operation, b.PermissionType}
+		for k := range keysA {
+			if keysA[k] != keysB[k] {
+				return keysA[k] < keysB[k]
+			}
+		}
+		return false
+	})
+}
@@ -44,6 +44,7 @@ type ListMessageRequest struct {
 	StartTimestamp        int64 // Start offset by unix timestamp in ms
 	MessageCount          int
 	FilterInterpreterCode string
+	BypassRedaction       bool // If true, the configured redaction rules will not be applied
 }
 
 // ListMessageResponse returns the requested kafka messages along with some metadata about the operation
@@ -127,6 +128,7 @@ func (s *Service) ListMessages(ctx context.Context, listReq ListMessageRequest,
 		MaxMessageCount:       listReq.MessageCount,
 		Partitions:            consumeRequests,
 		FilterInterpreterCode: listReq.FilterInterpreterCode,
+		BypassRedaction:       listReq.BypassRedaction,
 	}
 
 	progress.OnPhase("Consuming messages")
@@ -29,6 +29,25 @@ type PartitionReassignmentsPartition struct {
 	AddingReplicas   []int32 `json:"addingReplicas"`
 	RemovingReplicas []int32 `json:"removingReplicas"`
 	Replicas         []int32 `json:"replicas"`
+
+	// IsThrottled is true if a replication throttle has been set for this reassignment via Kowl.
+	IsThrottled bool `json:"isThrottled"`
+
+	// Size is the log dir size of the largest replica that is not being added.
+	Size int64 `json:"size"`
+
+	// BytesCopied is the sum of the log dir sizes of all adding replicas, BytesTotal is the sum that is expected once
+	// all adding replicas have caught up.
+	BytesCopied    int64                          `json:"bytesCopied"`
+	BytesTotal     int64                          `json:"bytesTotal"`
+	AddingProgress []PartitionReassignmentReplica `json:"addingProgress"`
+}
+
+// PartitionReassignmentReplica is the progress of a single adding replica.
+type PartitionReassignmentReplica struct {
+	BrokerID int32  `json:"brokerId"`
+	Size     int64  `json:"size"`
+	Error    string `json:"error,omitempty"`
 }
 
 // ListPartitionReassignments returns all partition reassignments that are currently in progress.
@@ -43,16 +62,35 @@ func (s *Service) ListPartitionReassignments(ctx context.Context) ([]PartitionRe
 		return nil, fmt.Errorf("failed to list partition reassignments. Inner error: %w", err)
 	}
 
+	// Describe the log dirs of all replicas so that we can tell how much data has been copied already
+	topicDetails := make(map[string]TopicDetails, len(reassignments.Topics))
+	for _, topic := range reassignments.Topics {
+		partitions := make([]TopicPartitionDetails, len(topic.Partitions))
+		for i, partition := range topic.Partitions {
+			partitions[i] = TopicPartitionDetails{
+				TopicPartitionMetadata: &TopicPartitionMetadata{ID: partition.Partition, Replicas: partition.Replicas},
+			}
+		}
+		topicDetails[topic.Topic] = TopicDetails{TopicName: topic.Topic, Partitions: partitions}
+	}
+	logDirsByTopicPartition := make(map[string]map[int32][]TopicPartitionLogDirs)
+	if len(topicDetails) > 0 {
+		logDirsByTopicPartition = s.describePartitionLogDirs(ctx, topicDetails)
+	}
+
 	topicReassignments := make([]PartitionReassignments, 0)
 	for _, topic := range reassignments.Topics {
 		partitionAssignments := make([]PartitionReassignmentsPartition, 0)
 		for _, partition := range topic.Partitions {
-			partitionAssignments = append(partitionAssignments, PartitionReassignmentsPartition{
+			reassignment := PartitionReassignmentsPartition{
 				PartitionID:      partition.Partition,
 				AddingReplicas:   partition.AddingReplicas,
 				RemovingReplicas: partition.RemovingReplicas,
-				Replicas:         partition.RemovingReplicas,
-			})
+				Replicas:         partition.Replicas,
+				IsThrottled:      s.reassignmentThrottler.isThrottled(topic.Topic, partition.Partition, partition.AddingReplicas),
+			}
+			setReassignmentProgress(&reassignment, logDirsByTopicPartition[topic.Topic][partition.Partition])
+			partitionAssignments = append(partitionAssignments, reassignment)
 		}
 
 		topicReassignments = append(topicReassignments, PartitionReassignments{
@@ -64,6 +102,40 @@ func (s *Service) ListPartitionReassignments(ctx context.Context) ([]PartitionRe
 	return topicReassignments, nil
 }
 
+// setReassignmentProgress compares the log dir sizes of the adding replicas with the largest replica that is
+// not being added.
+func setReassignmentProgress(reassignment *PartitionReassignmentsPartition, logDirs []TopicPartitionLogDirs) {
+	logDirsByBrokerID := make(map[int32]TopicPartitionLogDirs, len(logDirs))
+	for _, logDir := range logDirs {
+		logDirsByBrokerID[logDir.BrokerID] = logDir
+		if logDir.Error == "" && !containsInt32(reassignment.AddingReplicas, logDir.BrokerID) && logDir.Size > reassignment.Size {
+			reassignment.Size = logDir.Size
+		}
+	}
+
+	reassignment.AddingProgress = make([]PartitionReassignmentReplica, len(reassignment.AddingReplicas))
+	for i, brokerID := range reassignment.AddingReplicas {
+		progress := PartitionReassignmentReplica{BrokerID: brokerID}
+		logDir, exists := logDirsByBrokerID[brokerID]
+		switch {
+		case !exists:
+			// The replica has not been created yet
+		case logDir.Error != "":
+			progress.Error = logDir.Error
+		default:
+			progress.Size = logDir.Size
+		}
+		reassignment.AddingProgress[i] = progress
+
+		copied := progress.Size
+		if copied > reassignment.Size {
+			copied = reassignment.Size
+		}
+		reassignment.BytesCopied += copied
+		reassignment.BytesTotal += reassignment.Size
+	}
+}
+
 // PartitionReassignments
 type AlterPartitionReassignmentsResponse struct {
 	TopicName  string                                         `json:"topicName"`
@@ -0,0 +1,615 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+)
+
+// PlanPartitionReassignmentsRequest describes the target state the planner shall compute partition assignments for.
+type PlanPartitionReassignmentsRequest struct {
+	// TopicNames whose partitions shall be reassigned. All topics are considered if no topic name is set.
+	TopicNames []string `json:"topicNames"`
+
+	// TargetBrokerIDs are the brokers the replicas shall be spread over. If not set, all brokers of the cluster
+	// are used as target brokers.
+	TargetBrokerIDs []int32 `json:"targetBrokerIds"`
+
+	// ExcludedBrokerIDs are brokers that shall not host any replicas afterwards, e.g. because they are
+	// going to be decommissioned.
+	ExcludedBrokerIDs []int32 `json:"excludedBrokerIds"`
+
+	// Balance additionally moves replicas from more loaded to less loaded brokers. Otherwise only replicas on
+	// excluded (or non target) brokers and replicas that violate the rack awareness are moved.
+	Balance bool `json:"balance"`
+}
+
+// Validate the plan request.
+func (p *PlanPartitionReassignmentsRequest) Validate() error {
+	excluded := make(map[int32]struct{}, len(p.ExcludedBrokerIDs))
+	for _, brokerID := range p.ExcludedBrokerIDs {
+		excluded[brokerID] = struct{}{}
+	}
+	for _, brokerID := range p.TargetBrokerIDs {
+		if _, exists := excluded[brokerID]; exists {
+			return fmt.Errorf("broker '%v' can not be a target broker and an excluded broker at the same time", brokerID)
+		}
+	}
+	for _, topicName := range p.TopicNames {
+		if topicName == "" {
+			return fmt.Errorf("topic names must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// PartitionReassignmentPlan is the result of the reassignment planner. It only contains the partitions whose
+// replica assignment changes, so that the plan can be submitted as partition reassignment afterwards.
+type PartitionReassignmentPlan struct {
+	Topics  []PartitionReassignmentPlanTopic  `json:"topics"`
+	Brokers []PartitionReassignmentPlanBroker `json:"brokers"`
+
+	// SkippedPartitions could not be considered, e.g. because they are offline.
+	SkippedPartitions []PartitionReassignmentPlanSkipped `json:"skippedPartitions"`
+
+	// MovedReplicas is the number of replicas that have to be created on a new broker.
+	MovedReplicas int `json:"movedReplicas"`
+
+	// MovedBytes is the expected data movement. Each added replica has to copy the whole partition.
+	MovedBytes int64 `json:"movedBytes"`
+}
+
+type PartitionReassignmentPlanTopic struct {
+	TopicName  string                               `json:"topicName"`
+	Partitions []PartitionReassignmentPlanPartition `json:"partitions"`
+}
+
+type PartitionReassignmentPlanPartition struct {
+	PartitionID int32 `json:"partitionId"`
+
+	// Replicas is the planned replica assignment. The first replica is the preferred leader.
+	Replicas         []int32 `json:"replicas"`
+	CurrentReplicas  []int32 `json:"currentReplicas"`
+	AddingReplicas   []int32 `json:"addingReplicas"`
+	RemovingReplicas []int32 `json:"removingReplicas"`
+
+	// Size is the largest log dir size reported by any of the current replicas.
+	Size       int64 `json:"size"`
+	MovedBytes int64 `json:"movedBytes"`
+}
+
+// PartitionReassignmentPlanBroker summarizes the replicas and bytes a broker hosts before and after the
+// reassignment. Only partitions that are in scope of the plan are considered.
+type PartitionReassignmentPlanBroker struct {
+	BrokerID        int32   `json:"brokerId"`
+	Rack            *string `json:"rack"`
+	IsTarget        bool    `json:"isTarget"`
+	CurrentReplicas int     `json:"currentReplicas"`
+	PlannedReplicas int     `json:"plannedReplicas"`
+	CurrentSize     int64   `json:"currentSize"`
+	PlannedSize     int64   `json:"plannedSize"`
+}
+
+type PartitionReassignmentPlanSkipped struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+	Error       string `json:"error"`
+}
+
+// PlanPartitionReassignments generates a rack aware and size balanced replica assignment for the requested topics
+// and target brokers. Existing replicas are kept wherever possible, so that as little data as possible has to be
+// moved. Nothing is applied, the returned plan can be submitted via AlterPartitionAssignments.
+func (s *Service) PlanPartitionReassignments(ctx context.Context, req PlanPartitionReassignmentsRequest) (*PartitionReassignmentPlan, *rest.Error) {
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, req.TopicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get topic metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 1. Figure out which brokers are eligible to host replicas
+	racksByBrokerID := make(map[int32]*string, len(metadata.Brokers))
+	for _, broker := range metadata.Brokers {
+		racksByBrokerID[broker.NodeID] = broker.Rack
+	}
+	for _, brokerID := range append(append([]int32{}, req.TargetBrokerIDs...), req.ExcludedBrokerIDs...) {
+		if _, exists := racksByBrokerID[brokerID]; !exists {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("broker id '%v' does not exist", brokerID),
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Broker with id '%v' does not exist in the cluster", brokerID),
+				IsSilent: false,
+			}
+		}
+	}
+
+	targetBrokerIDs := req.TargetBrokerIDs
+	if len(targetBrokerIDs) == 0 {
+		targetBrokerIDs = make([]int32, 0, len(racksByBrokerID))
+		for brokerID := range racksByBrokerID {
+			targetBrokerIDs = append(targetBrokerIDs, brokerID)
+		}
+	}
+	excluded := make(map[int32]struct{}, len(req.ExcludedBrokerIDs))
+	for _, brokerID := range req.ExcludedBrokerIDs {
+		excluded[brokerID] = struct{}{}
+	}
+	targetBrokers := make([]reassignmentBroker, 0, len(targetBrokerIDs))
+	isTarget := make(map[int32]bool, len(targetBrokerIDs))
+	for _, brokerID := range targetBrokerIDs {
+		if _, isExcluded := excluded[brokerID]; isExcluded {
+			continue
+		}
+		rack := ""
+		if racksByBrokerID[brokerID] != nil {
+			rack = *racksByBrokerID[brokerID]
+		}
+		targetBrokers = append(targetBrokers, reassignmentBroker{ID: brokerID, Rack: rack})
+		isTarget[brokerID] = true
+	}
+
+	// 2. Collect partitions along with their sizes
+	topicDetails := s.convertTopicPartitionMetadata(metadata)
+	logDirsByTopicPartition := s.describePartitionLogDirs(ctx, topicDetails)
+
+	plan := &PartitionReassignmentPlan{
+		Topics:            make([]PartitionReassignmentPlanTopic, 0),
+		SkippedPartitions: make([]PartitionReassignmentPlanSkipped, 0),
+	}
+	partitions := make([]reassignmentPartition, 0)
+	for _, topic := range topicDetails {
+		if topic.Error != "" {
+			plan.SkippedPartitions = append(plan.SkippedPartitions, PartitionReassignmentPlanSkipped{
+				TopicName:   topic.TopicName,
+				PartitionID: -1,
+				Error:       topic.Error,
+			})
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			if partition.PartitionError != "" {
+				plan.SkippedPartitions = append(plan.SkippedPartitions, PartitionReassignmentPlanSkipped{
+					TopicName:   topic.TopicName,
+					PartitionID: partition.ID,
+					Error:       partition.PartitionError,
+				})
+				continue
+			}
+			size := int64(0)
+			for _, logDir := range logDirsByTopicPartition[topic.TopicName][partition.ID] {
+				if logDir.Error == "" && logDir.Size > size {
+					size = logDir.Size
+				}
+			}
+			partitions = append(partitions, reassignmentPartition{
+				TopicName:   topic.TopicName,
+				PartitionID: partition.ID,
+				Replicas:    partition.Replicas,
+				Size:        size,
+			})
+		}
+	}
+
+	// 3. Compute plan
+	plannedReplicas, err := planPartitionReassignments(ctx, targetBrokers, partitions, req.Balance)
+	if err != nil && ctx.Err() != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to plan partition reassignments in time: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to plan partition reassignments: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 4. Summarize changed partitions and the load per broker
+	brokerSummaries := make(map[int32]*PartitionReassignmentPlanBroker, len(racksByBrokerID))
+	for brokerID, rack := range racksByBrokerID {
+		brokerSummaries[brokerID] = &PartitionReassignmentPlanBroker{BrokerID: brokerID, Rack: rack, IsTarget: isTarget[brokerID]}
+	}
+	partitionsByTopic := make(map[string][]PartitionReassignmentPlanPartition)
+	for i, partition := range partitions {
+		planned := plannedReplicas[i]
+		for _, brokerID := range partition.Replicas {
+			if summary, exists := brokerSummaries[brokerID]; exists {
+				summary.CurrentReplicas++
+				summary.CurrentSize += partition.Size
+			}
+		}
+		for _, brokerID := range planned {
+			summary := brokerSummaries[brokerID]
+			summary.PlannedReplicas++
+			summary.PlannedSize += partition.Size
+		}
+		if int32SlicesEqual(partition.Replicas, planned) {
+			continue
+		}
+
+		adding := int32Difference(planned, partition.Replicas)
+		movedBytes := partition.Size * int64(len(adding))
+		plan.MovedReplicas += len(adding)
+		plan.MovedBytes += movedBytes
+		partitionsByTopic[partition.TopicName] = append(partitionsByTopic[partition.TopicName], PartitionReassignmentPlanPartition{
+			PartitionID:      partition.PartitionID,
+			Replicas:         planned,
+			CurrentReplicas:  partition.Replicas,
+			AddingReplicas:   adding,
+			RemovingReplicas: int32Difference(partition.Replicas, planned),
+			Size:             partition.Size,
+			MovedBytes:       movedBytes,
+		})
+	}
+
+	for topicName, topicPartitions := range partitionsByTopic {
+		sort.Slice(topicPartitions, func(i, j int) bool {
+			return topicPartitions[i].PartitionID < topicPartitions[j].PartitionID
+		})
+		plan.Topics = append(plan.Topics, PartitionReassignmentPlanTopic{TopicName: topicName, Partitions: topicPartitions})
+	}
+	sort.Slice(plan.Topics, func(i, j int) bool {
+		return plan.Topics[i].TopicName < plan.Topics[j].TopicName
+	})
+
+	plan.Brokers = make([]PartitionReassignmentPlanBroker, 0, len(brokerSummaries))
+	for _, summary := range brokerSummaries {
+		plan.Brokers = append(plan.Brokers, *summary)
+	}
+	sort.Slice(plan.Brokers, func(i, j int) bool {
+		return plan.Brokers[i].BrokerID < plan.Brokers[j].BrokerID
+	})
+	sort.Slice(plan.SkippedPartitions, func(i, j int) bool {
+		a, b := plan.SkippedPartitions[i], plan.SkippedPartitions[j]
+		if a.TopicName != b.TopicName {
+			return a.TopicName < b.TopicName
+		}
+		return a.PartitionID < b.PartitionID
+	})
+
+	return plan, nil
+}
+
+type reassignmentBroker struct {
+	ID int32
+
+	// Rack is empty if the broker has no rack configured
+	Rack string
+}
+
+type reassignmentPartition struct {
+	TopicName   string
+	PartitionID int32
+	Replicas    []int32
+	Size        int64
+}
+
+// reassignmentPlanner tracks the planned assignments along with the resulting load per target broker.
+type reassignmentPlanner struct {
+	racks      map[int32]string
+	brokerIDs  []int32
+	rackCount  int
+	partitions []reassignmentPartition
+
+	planned          [][]int32
+	bytesByBroker    map[int32]int64
+	replicasByBroker map[int32]int
+}
+
+// planPartitionReassignments computes the new replica assignment for each given partition, so that all replicas
+// are placed on the given brokers. The planner works in two phases:
+//  1. Replicas on brokers that are not a target broker (or that violate the rack awareness) are replaced by a
+//     replica on the least loaded broker of a rack the partition is not present yet.
+//  2. If balance is true, replicas are moved from the most loaded to less loaded brokers as long as this reduces
+//     the size imbalance. If the sizes are equal the number of replicas per broker is balanced instead. The
+//     preferred leader (first replica) is never moved in this phase.
+//
+// The replica order is kept, so that the preferred leader only changes if its broker is replaced.
+func planPartitionReassignments(ctx context.Context, brokers []reassignmentBroker, partitions []reassignmentPartition, balance bool) ([][]int32, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("no target brokers available")
+	}
+
+	p := &reassignmentPlanner{
+		racks:            make(map[int32]string, len(brokers)),
+		brokerIDs:        make([]int32, 0, len(brokers)),
+		partitions:       partitions,
+		planned:          make([][]int32, len(partitions)),
+		bytesByBroker:    make(map[int32]int64, len(brokers)),
+		replicasByBroker: make(map[int32]int, len(brokers)),
+	}
+	racks := make(map[string]struct{})
+	for _, broker := range brokers {
+		p.racks[broker.ID] = broker.Rack
+		p.brokerIDs = append(p.brokerIDs, broker.ID)
+		if broker.Rack != "" {
+			racks[broker.Rack] = struct{}{}
+		}
+	}
+	p.rackCount = len(racks)
+	sort.Slice(p.brokerIDs, func(i, j int) bool { return p.brokerIDs[i] < p.brokerIDs[j] })
+
+	for _, partition := range partitions {
+		if len(partition.Replicas) > len(brokers) {
+			return nil, fmt.Errorf("partition '%v' of topic '%v' has a replication factor of %v, but only %v target brokers are available",
+				partition.PartitionID, partition.TopicName, len(partition.Replicas), len(brokers))
+		}
+	}
+
+	// Phase 1: Keep all replicas that may stay where they are
+	for i := range partitions {
+		p.planned[i] = p.keptReplicas(partitions[i].Replicas)
+		for _, brokerID := range p.planned[i] {
+			if brokerID != -1 {
+				p.addLoad(brokerID, partitions[i].Size, 1)
+			}
+		}
+	}
+
+	// Fill the gaps, starting with the largest partitions so that they can be spread evenly
+	order := make([]int, len(partitions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := partitions[order[i]], partitions[order[j]]
+		if a.Size != b.Size {
+			return a.Size > b.Size
+		}
+		if a.TopicName != b.TopicName {
+			return a.TopicName < b.TopicName
+		}
+		return a.PartitionID < b.PartitionID
+	})
+	for _, i := range order {
+		for slot, brokerID := range p.planned[i] {
+			if brokerID != -1 {
+				continue
+			}
+			brokerID = p.leastLoadedBroker(p.planned[i])
+			p.planned[i][slot] = brokerID
+			p.addLoad(brokerID, partitions[i].Size, 1)
+		}
+	}
+
+	if !balance {
+		return p.planned, nil
+	}
+
+	// Phase 2: Balance the load between the brokers. Each move strictly reduces the imbalance, the total number
+	// of replicas is just a safety net.
+	maxMoves := 0
+	for _, replicas := range p.planned {
+		maxMoves += len(replicas)
+	}
+	for moves := 0; moves < maxMoves; moves++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !p.moveReplica() {
+			break
+		}
+	}
+
+	return p.planned, nil
+}
+
+// keptReplicas returns the replicas that can stay on their broker. Replicas that have to be replaced are set to -1.
+func (p *reassignmentPlanner) keptReplicas(replicas []int32) []int32 {
+	kept := make([]int32, len(replicas))
+	seenBrokers := make(map[int32]struct{}, len(replicas))
+	seenRacks := make(map[string]struct{}, len(replicas))
+	rackDuplicates := make([]int, 0)
+	for i, brokerID := range replicas {
+		kept[i] = -1
+		if _, isTarget := p.racks[brokerID]; !isTarget {
+			continue
+		}
+		if _, isDuplicate := seenBrokers[brokerID]; isDuplicate {
+			continue
+		}
+		seenBrokers[brokerID] = struct{}{}
+		kept[i] = brokerID
+
+		rack := p.racks[brokerID]
+		if rack == "" {
+			continue
+		}
+		if _, exists := seenRacks[rack]; exists {
+			rackDuplicates = append(rackDuplicates, i)
+		}
+		seenRacks[rack] = struct{}{}
+	}
+
+	// Only replace as many replicas that share a rack as required to spread the partition over as many racks
+	// as possible.
+	desiredRacks := p.rackCount
+	if len(replicas) < desiredRacks {
+		desiredRacks = len(replicas)
+	}
+	for missingRacks := desiredRacks - len(seenRacks); missingRacks > 0 && len(rackDuplicates) > 0; missingRacks-- {
+		last := rackDuplicates[len(rackDuplicates)-1]
+		kept[last] = -1
+		rackDuplicates = rackDuplicates[:len(rackDuplicates)-1]
+	}
+
+	return kept
+}
+
+// leastLoadedBroker returns the target broker that should host a new replica of a partition with the given
+// replicas. Brokers in racks that do not host a replica of the partition yet are preferred.
+func (p *reassignmentPlanner) leastLoadedBroker(replicas []int32) int32 {
+	usedRacks := p.racksOf(replicas, -1)
+	best := int32(-1)
+	bestInUsedRack := false
+	for _, brokerID := range p.brokerIDs {
+		if containsInt32(replicas, brokerID) {
+			continue
+		}
+		_, inUsedRack := usedRacks[p.racks[brokerID]]
+		inUsedRack = inUsedRack && p.racks[brokerID] != ""
+		if best != -1 {
+			if inUsedRack != bestInUsedRack {
+				if inUsedRack {
+					continue
+				}
+			} else if !p.isLessLoaded(brokerID, best) {
+				continue
+			}
+		}
+		best = brokerID
+		bestInUsedRack = inUsedRack
+	}
+
+	return best
+}
+
+// moveReplica moves a single replica from the most loaded broker to a less loaded broker. Preferred leaders are
+// not moved, so that balancing does not shift the leadership. It returns false if there's no move that reduces
+// the imbalance.
+func (p *reassignmentPlanner) moveReplica() bool {
+	brokersByLoad := make([]int32, len(p.brokerIDs))
+	copy(brokersByLoad, p.brokerIDs)
+	sort.SliceStable(brokersByLoad, func(i, j int) bool {
+		return p.isLessLoaded(brokersByLoad[i], brokersByLoad[j])
+	})
+	source := brokersByLoad[len(brokersByLoad)-1]
+
+	for _, target := range brokersByLoad[:len(brokersByLoad)-1] {
+		sizeGap := p.bytesByBroker[source] - p.bytesByBroker[target]
+		replicaGap := p.replicasByBroker[source] - p.replicasByBroker[target]
+
+		bestPartition, bestSlot := -1, -1
+		var bestScore int64
+		bestIsOriginal := false
+		for i, replicas := range p.planned {
+			slot := indexOfInt32(replicas, source)
+			if slot <= 0 || containsInt32(replicas, target) {
+				continue
+			}
+			size := p.partitions[i].Size
+			if size > 0 && size >= sizeGap {
+				continue
+			}
+			if size == 0 && replicaGap <= 1 {
+				continue
+			}
+			// Moving the replica must not reduce the number of racks the partition is spread over
+			otherRacks := p.racksOf(replicas, slot)
+			_, sourceRackUsed := otherRacks[p.racks[source]]
+			_, targetRackUsed := otherRacks[p.racks[target]]
+			sourceAddsRack := p.racks[source] != "" && !sourceRackUsed
+			targetAddsRack := p.racks[target] != "" && !targetRackUsed
+			if sourceAddsRack && !targetAddsRack {
+				continue
+			}
+
+			// The ideal move halves the gap between both brokers. Moving replicas that have been added in this plan
+			// anyway doesn't cause additional data movement.
+			score := sizeGap - 2*size
+			if score < 0 {
+				score = -score
+			}
+			isOriginal := containsInt32(p.partitions[i].Replicas, source)
+			if bestPartition == -1 || score < bestScore || (score == bestScore && bestIsOriginal && !isOriginal) {
+				bestPartition, bestSlot, bestScore, bestIsOriginal = i, slot, score, isOriginal
+			}
+		}
+		if bestPartition == -1 {
+			continue
+		}
+
+		size := p.partitions[bestPartition].Size
+		p.planned[bestPartition][bestSlot] = target
+		p.addLoad(source, -size, -1)
+		p.addLoad(target, size, 1)
+		return true
+	}
+
+	return false
+}
+
+func (p *reassignmentPlanner) addLoad(brokerID int32, size int64, replicas int) {
+	p.bytesByBroker[brokerID] += size
+	p.replicasByBroker[brokerID] += replicas
+}
+
+// isLessLoaded compares brokers by their planned size, replica count and broker id.
+func (p *reassignmentPlanner) isLessLoaded(a, b int32) bool {
+	if p.bytesByBroker[a] != p.bytesByBroker[b] {
+		return p.bytesByBroker[a] < p.bytesByBroker[b]
+	}
+	if p.replicasByBroker[a] != p.replicasByBroker[b] {
+		return p.replicasByBroker[a] < p.replicasByBroker[b]
+	}
+	return a < b
+}
+
+// racksOf returns the racks of the given replicas, ignoring the replica at skipSlot.
+func (p *reassignmentPlanner) racksOf(replicas []int32, skipSlot int) map[string]struct{} {
+	racks := make(map[string]struct{}, len(replicas))
+	for i, brokerID := range replicas {
+		if i == skipSlot || brokerID == -1 || p.racks[brokerID] == "" {
+			continue
+		}
+		racks[p.racks[brokerID]] = struct{}{}
+	}
+	return racks
+}
+
+func containsInt32(values []int32, value int32) bool {
+	return indexOfInt32(values, value) != -1
+}
+
+func indexOfInt32(values []int32, value int32) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func int32SlicesEqual(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// int32Difference returns all values of a that are not in b.
+func int32Difference(a, b []int32) []int32 {
+	diff := make([]int32, 0)
+	for _, v := range a {
+		if !containsInt32(b, v) {
+			diff = append(diff, v)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanPartitionReassignmentsDecommission(t *testing.T) {
+	brokers := []reassignmentBroker{{ID: 1, Rack: "a"}, {ID: 2, Rack: "b"}, {ID: 4, Rack: "c"}}
+	partitions := []reassignmentPartition{
+		{TopicName: "orders", PartitionID: 0, Replicas: []int32{3, 1, 2}, Size: 100},
+		{TopicName: "orders", PartitionID: 1, Replicas: []int32{1, 2, 4}, Size: 100},
+		{TopicName: "orders", PartitionID: 2, Replicas: []int32{2, 3, 1}, Size: 100},
+	}
+
+	planned, err := planPartitionReassignments(context.Background(), brokers, partitions, false)
+	require.NoError(t, err)
+
+	// Broker 3 is replaced by the only remaining broker, the replica order is kept
+	assert.Equal(t, []int32{4, 1, 2}, planned[0])
+	assert.Equal(t, []int32{1, 2, 4}, planned[1])
+	assert.Equal(t, []int32{2, 4, 1}, planned[2])
+}
+
+func TestPlanPartitionReassignmentsSpread(t *testing.T) {
+	brokers := []reassignmentBroker{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	partitions := []reassignmentPartition{
+		{TopicName: "events", PartitionID: 0, Replicas: []int32{1, 2}, Size: 10},
+		{TopicName: "events", PartitionID: 1, Replicas: []int32{2, 1}, Size: 10},
+		{TopicName: "events", PartitionID: 2, Replicas: []int32{1, 2}, Size: 10},
+		{TopicName: "events", PartitionID: 3, Replicas: []int32{2, 1}, Size: 10},
+	}
+
+	// Without balancing no replica has to move
+	planned, err := planPartitionReassignments(context.Background(), brokers, partitions, false)
+	require.NoError(t, err)
+	for i, replicas := range planned {
+		assert.Equal(t, partitions[i].Replicas, replicas)
+	}
+
+	planned, err = planPartitionReassignments(context.Background(), brokers, partitions, true)
+	require.NoError(t, err)
+
+	replicasByBroker := make(map[int32]int)
+	moved := 0
+	for i, replicas := range planned {
+		for _, brokerID := range replicas {
+			replicasByBroker[brokerID]++
+		}
+		moved += len(int32Difference(replicas, partitions[i].Replicas))
+		assert.Equal(t, partitions[i].Replicas[0], replicas[0], "the preferred leader must not be moved")
+	}
+	assert.Equal(t, map[int32]int{1: 2, 2: 2, 3: 2, 4: 2}, replicasByBroker)
+	assert.Equal(t, 4, moved, "only as many replicas as required should be moved")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = planPartitionReassignments(ctx, brokers, partitions, true)
+	assert.Error(t, err)
+}
+
+func TestPlanPartitionReassignmentsRackAware(t *testing.T) {
+	brokers := []reassignmentBroker{{ID: 1, Rack: "a"}, {ID: 2, Rack: "a"}, {ID: 3, Rack: "b"}}
+	partitions := []reassignmentPartition{
+		{TopicName: "payments", PartitionID: 0, Replicas: []int32{1, 2}, Size: 0},
+	}
+
+	planned, err := planPartitionReassignments(context.Background(), brokers, partitions, false)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 3}, planned[0])
+
+	// Replication factor exceeds the number of target brokers
+	_, err = planPartitionReassignments(context.Background(), brokers[:1], partitions, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,449 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+)
+
+const (
+	configLeaderReplicationThrottledRate       = "leader.replication.throttled.rate"
+	configFollowerReplicationThrottledRate     = "follower.replication.throttled.rate"
+	configLeaderReplicationThrottledReplicas   = "leader.replication.throttled.replicas"
+	configFollowerReplicationThrottledReplicas = "follower.replication.throttled.replicas"
+
+	// reassignmentThrottleCheckInterval is the interval in which we check whether throttled reassignments
+	// have been completed, so that the throttles can be removed.
+	reassignmentThrottleCheckInterval = 10 * time.Second
+)
+
+// ReassignmentThrottle describes the replication throttle that is applied while partitions are reassigned.
+type ReassignmentThrottle struct {
+	// Rate is the replication throttle in bytes per second that is set on all involved brokers.
+	Rate      int64                       `json:"rate"`
+	BrokerIDs []int32                     `json:"brokerIds"`
+	Topics    []ReassignmentThrottleTopic `json:"topics"`
+}
+
+// ReassignmentThrottleTopic contains the throttled replicas of a topic in the "partitionId:brokerId" format
+// that is used by Kafka. Leader replicas are the replicas that are being copied from, follower replicas are the
+// replicas that are being added.
+type ReassignmentThrottleTopic struct {
+	TopicName        string   `json:"topicName"`
+	LeaderReplicas   []string `json:"leaderReplicas"`
+	FollowerReplicas []string `json:"followerReplicas"`
+}
+
+// ThrottlePartitionReassignments sets a replication throttle for all replicas that will be moved by the given
+// reassignments. The throttle rate is set on all brokers that are involved and the throttled replicas are appended
+// to the topic configs. All throttles are removed automatically as soon as the reassignments are completed.
+// This must be called before the reassignments are submitted, followed by ReassignmentsSubmitted afterwards.
+func (s *Service) ThrottlePartitionReassignments(ctx context.Context, topics []kmsg.AlterPartitionAssignmentsRequestTopic, rate int64) (*ReassignmentThrottle, *rest.Error) {
+	topicNames := make([]string, len(topics))
+	for i, topic := range topics {
+		topicNames[i] = topic.Topic
+	}
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get topic metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+	currentReplicas := make(map[string]map[int32][]int32, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		partitions := make(map[int32][]int32, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			partitions[partition.Partition] = partition.Replicas
+		}
+		currentReplicas[*topic.Topic] = partitions
+	}
+
+	throttle := buildReassignmentThrottle(topics, currentReplicas, rate)
+	if len(throttle.Topics) == 0 {
+		return &throttle, nil
+	}
+
+	err = s.reassignmentThrottler.add(ctx, throttle)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to set replication throttle: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	return &throttle, nil
+}
+
+// ReassignmentsSubmitted must be called once the throttled reassignments have been submitted (or failed to be
+// submitted). Until then the throttle of the involved topics is not removed, even if there is no reassignment in
+// progress yet.
+func (s *Service) ReassignmentsSubmitted(throttle *ReassignmentThrottle) {
+	if throttle == nil {
+		return
+	}
+	s.reassignmentThrottler.submitted(*throttle)
+}
+
+// buildReassignmentThrottle returns the throttled replicas for all partitions that get new replicas.
+func buildReassignmentThrottle(topics []kmsg.AlterPartitionAssignmentsRequestTopic, currentReplicas map[string]map[int32][]int32, rate int64) ReassignmentThrottle {
+	throttle := ReassignmentThrottle{
+		Rate:      rate,
+		BrokerIDs: make([]int32, 0),
+		Topics:    make([]ReassignmentThrottleTopic, 0),
+	}
+	brokerIDs := make(map[int32]struct{})
+	for _, topic := range topics {
+		throttledTopic := ReassignmentThrottleTopic{
+			TopicName:        topic.Topic,
+			LeaderReplicas:   make([]string, 0),
+			FollowerReplicas: make([]string, 0),
+		}
+		for _, partition := range topic.Partitions {
+			// Replicas is nil if a pending reassignment shall be cancelled
+			if partition.Replicas == nil {
+				continue
+			}
+			current := currentReplicas[topic.Topic][partition.Partition]
+			adding := int32Difference(partition.Replicas, current)
+			if len(adding) == 0 {
+				continue
+			}
+			for _, brokerID := range current {
+				throttledTopic.LeaderReplicas = append(throttledTopic.LeaderReplicas, fmt.Sprintf("%d:%d", partition.Partition, brokerID))
+				brokerIDs[brokerID] = struct{}{}
+			}
+			for _, brokerID := range adding {
+				throttledTopic.FollowerReplicas = append(throttledTopic.FollowerReplicas, fmt.Sprintf("%d:%d", partition.Partition, brokerID))
+				brokerIDs[brokerID] = struct{}{}
+			}
+		}
+		if len(throttledTopic.FollowerReplicas) > 0 {
+			throttle.Topics = append(throttle.Topics, throttledTopic)
+		}
+	}
+	for brokerID := range brokerIDs {
+		throttle.BrokerIDs = append(throttle.BrokerIDs, brokerID)
+	}
+	sort.Slice(throttle.BrokerIDs, func(i, j int) bool { return throttle.BrokerIDs[i] < throttle.BrokerIDs[j] })
+
+	return throttle
+}
+
+// reassignmentThrottler keeps track of the replication throttles that have been set for reassignments and removes
+// them once the reassignments are completed. The throttles are only tracked in memory, a restart while a throttled
+// reassignment is in progress will leave the throttles in place.
+type reassignmentThrottler struct {
+	kafkaSvc *kafka.Service
+	logger   *zap.Logger
+
+	mutex sync.Mutex
+	// previousRates are the dynamic throttle rates that have been configured on each throttled broker before we
+	// set ours. A nil rate means that no dynamic rate was set. These are restored once all throttles are removed.
+	previousRates map[int32]map[string]*string
+	topics        map[string]*throttledReplicas
+	// pendingTopics counts the throttled reassignments per topic that are not submitted yet. Their throttles
+	// must not be removed, even though no reassignment is in progress yet.
+	pendingTopics map[string]int
+	isWatching    bool
+}
+
+type throttledReplicas struct {
+	leaderReplicas   map[string]struct{}
+	followerReplicas map[string]struct{}
+}
+
+func newReassignmentThrottler(logger *zap.Logger, kafkaSvc *kafka.Service) *reassignmentThrottler {
+	return &reassignmentThrottler{
+		kafkaSvc:      kafkaSvc,
+		logger:        logger.With(zap.String("source", "reassignment_throttler")),
+		previousRates: make(map[int32]map[string]*string),
+		topics:        make(map[string]*throttledReplicas),
+		pendingTopics: make(map[string]int),
+	}
+}
+
+// add applies the throttle and starts watching the reassignments so that the throttle can be removed again. The
+// throttled topics are pending until submitted is called.
+func (t *reassignmentThrottler) add(ctx context.Context, throttle ReassignmentThrottle) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// Remember the current rates of all brokers that are not throttled by us yet, so that they can be restored
+	previousRates := make(map[int32]map[string]*string)
+	for _, brokerID := range throttle.BrokerIDs {
+		if _, exists := t.previousRates[brokerID]; exists {
+			continue
+		}
+		res, err := t.kafkaSvc.DescribeBrokerConfig(ctx, brokerID,
+			[]string{configLeaderReplicationThrottledRate, configFollowerReplicationThrottledRate})
+		if err != nil {
+			return fmt.Errorf("failed to describe throttle rates of broker '%v': %w", brokerID, err)
+		}
+		rates, err := dynamicThrottleRates(res)
+		if err != nil {
+			return fmt.Errorf("failed to describe throttle rates of broker '%v': %w", brokerID, err)
+		}
+		previousRates[brokerID] = rates
+	}
+
+	rate := strconv.FormatInt(throttle.Rate, 10)
+	resources := make([]kmsg.IncrementalAlterConfigsRequestResource, 0, len(throttle.BrokerIDs)+len(throttle.Topics))
+	for _, brokerID := range throttle.BrokerIDs {
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeBroker, strconv.Itoa(int(brokerID)),
+			newAlterConfig(configLeaderReplicationThrottledRate, kmsg.IncrementalAlterConfigOpSet, rate),
+			newAlterConfig(configFollowerReplicationThrottledRate, kmsg.IncrementalAlterConfigOpSet, rate)))
+	}
+	for _, topic := range throttle.Topics {
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeTopic, topic.TopicName,
+			newAlterConfig(configLeaderReplicationThrottledReplicas, kmsg.IncrementalAlterConfigOpAppend, strings.Join(topic.LeaderReplicas, ",")),
+			newAlterConfig(configFollowerReplicationThrottledReplicas, kmsg.IncrementalAlterConfigOpAppend, strings.Join(topic.FollowerReplicas, ","))))
+	}
+	err := t.alterConfigs(ctx, resources)
+	if err != nil {
+		return err
+	}
+
+	for brokerID, rates := range previousRates {
+		t.previousRates[brokerID] = rates
+	}
+	for _, topic := range throttle.Topics {
+		t.pendingTopics[topic.TopicName]++
+		replicas, exists := t.topics[topic.TopicName]
+		if !exists {
+			replicas = &throttledReplicas{
+				leaderReplicas:   make(map[string]struct{}),
+				followerReplicas: make(map[string]struct{}),
+			}
+			t.topics[topic.TopicName] = replicas
+		}
+		for _, replica := range topic.LeaderReplicas {
+			replicas.leaderReplicas[replica] = struct{}{}
+		}
+		for _, replica := range topic.FollowerReplicas {
+			replicas.followerReplicas[replica] = struct{}{}
+		}
+	}
+
+	if !t.isWatching {
+		t.isWatching = true
+		go t.watch()
+	}
+
+	return nil
+}
+
+// submitted marks the throttled topics as no longer pending.
+func (t *reassignmentThrottler) submitted(throttle ReassignmentThrottle) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, topic := range throttle.Topics {
+		t.pendingTopics[topic.TopicName]--
+		if t.pendingTopics[topic.TopicName] <= 0 {
+			delete(t.pendingTopics, topic.TopicName)
+		}
+	}
+}
+
+// dynamicThrottleRates returns the throttle rates that are set as dynamic config on the described broker. Rates
+// that are not set dynamically are nil.
+func dynamicThrottleRates(res *kmsg.DescribeConfigsResponse) (map[string]*string, error) {
+	rates := map[string]*string{
+		configLeaderReplicationThrottledRate:   nil,
+		configFollowerReplicationThrottledRate: nil,
+	}
+	for _, resource := range res.Resources {
+		if err := kerr.ErrorForCode(resource.ErrorCode); err != nil {
+			return nil, err
+		}
+		for _, config := range resource.Configs {
+			if _, isRate := rates[config.Name]; !isRate {
+				continue
+			}
+			if config.Source == kmsg.ConfigSourceDynamicBrokerConfig && config.Value != nil {
+				value := *config.Value
+				rates[config.Name] = &value
+			}
+		}
+	}
+	return rates, nil
+}
+
+// isThrottled returns true if a throttle has been set for any of the adding replicas of the given partition.
+func (t *reassignmentThrottler) isThrottled(topicName string, partitionID int32, addingReplicas []int32) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	replicas, exists := t.topics[topicName]
+	if !exists {
+		return false
+	}
+	for _, brokerID := range addingReplicas {
+		if _, exists := replicas.followerReplicas[fmt.Sprintf("%d:%d", partitionID, brokerID)]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// watch periodically checks whether the throttled reassignments are completed until all throttles are removed.
+func (t *reassignmentThrottler) watch() {
+	ticker := time.NewTicker(reassignmentThrottleCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), reassignmentThrottleCheckInterval)
+		isDone, err := t.removeCompleted(ctx)
+		cancel()
+		if err != nil {
+			t.logger.Warn("failed to remove replication throttles of completed reassignments", zap.Error(err))
+			continue
+		}
+		if isDone {
+			return
+		}
+	}
+}
+
+// removeCompleted removes the throttled replicas of all topics that have no reassignments in progress anymore and
+// that are not pending. Once no throttled topic is left the brokers' previous throttle rates are restored. It
+// returns true if all throttles have been removed.
+func (t *reassignmentThrottler) removeCompleted(ctx context.Context) (bool, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	reassignments, err := t.kafkaSvc.ListPartitionReassignments(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list partition reassignments: %w", err)
+	}
+	if err := kerr.ErrorForCode(reassignments.ErrorCode); err != nil {
+		return false, fmt.Errorf("failed to list partition reassignments: %w", err)
+	}
+	inProgress := make(map[string]struct{}, len(reassignments.Topics))
+	for _, topic := range reassignments.Topics {
+		if len(topic.Partitions) > 0 {
+			inProgress[topic.Topic] = struct{}{}
+		}
+	}
+
+	completedTopics := make([]string, 0)
+	resources := make([]kmsg.IncrementalAlterConfigsRequestResource, 0)
+	for topicName, replicas := range t.topics {
+		if _, exists := inProgress[topicName]; exists {
+			continue
+		}
+		if _, isPending := t.pendingTopics[topicName]; isPending {
+			continue
+		}
+		completedTopics = append(completedTopics, topicName)
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeTopic, topicName,
+			newAlterConfig(configLeaderReplicationThrottledReplicas, kmsg.IncrementalAlterConfigOpSubtract, joinSet(replicas.leaderReplicas)),
+			newAlterConfig(configFollowerReplicationThrottledReplicas, kmsg.IncrementalAlterConfigOpSubtract, joinSet(replicas.followerReplicas))))
+	}
+	isDone := len(completedTopics) == len(t.topics)
+	if isDone {
+		for brokerID, rates := range t.previousRates {
+			resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeBroker, strconv.Itoa(int(brokerID)),
+				restoreAlterConfig(configLeaderReplicationThrottledRate, rates[configLeaderReplicationThrottledRate]),
+				restoreAlterConfig(configFollowerReplicationThrottledRate, rates[configFollowerReplicationThrottledRate])))
+		}
+	}
+	if len(resources) == 0 {
+		return false, nil
+	}
+
+	err = t.alterConfigs(ctx, resources)
+	if err != nil {
+		return false, err
+	}
+	for _, topicName := range completedTopics {
+		delete(t.topics, topicName)
+	}
+	t.logger.Info("removed replication throttles of completed reassignments", zap.Strings("topics", completedTopics))
+	if isDone {
+		t.previousRates = make(map[int32]map[string]*string)
+		t.isWatching = false
+		t.logger.Info("restored previous replication throttle rates of brokers")
+	}
+
+	return isDone, nil
+}
+
+func (t *reassignmentThrottler) alterConfigs(ctx context.Context, resources []kmsg.IncrementalAlterConfigsRequestResource) error {
+	res, err := t.kafkaSvc.IncrementalAlterConfigs(ctx, resources)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]string, 0)
+	for _, resource := range res.Resources {
+		if err := kerr.ErrorForCode(resource.ErrorCode); err != nil {
+			errs = append(errs, fmt.Sprintf("%v '%v': %v", resource.ResourceType, resource.ResourceName, err.Error()))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to alter configs: %v", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func newAlterConfigsResource(resourceType kmsg.ConfigResourceType, name string, configs ...kmsg.IncrementalAlterConfigsRequestResourceConfig) kmsg.IncrementalAlterConfigsRequestResource {
+	resource := kmsg.NewIncrementalAlterConfigsRequestResource()
+	resource.ResourceType = resourceType
+	resource.ResourceName = name
+	resource.Configs = configs
+	return resource
+}
+
+func newAlterConfig(name string, op kmsg.IncrementalAlterConfigOp, value string) kmsg.IncrementalAlterConfigsRequestResourceConfig {
+	cfg := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+	cfg.Name = name
+	cfg.Op = op
+	if op != kmsg.IncrementalAlterConfigOpDelete {
+		cfg.Value = &value
+	}
+	return cfg
+}
+
+// restoreAlterConfig sets the config to the given previous value or deletes it if there was no previous value.
+func restoreAlterConfig(name string, previousValue *string) kmsg.IncrementalAlterConfigsRequestResourceConfig {
+	if previousValue == nil {
+		return newAlterConfig(name, kmsg.IncrementalAlterConfigOpDelete, "")
+	}
+	return newAlterConfig(name, kmsg.IncrementalAlterConfigOpSet, *previousValue)
+}
+
+func joinSet(set map[string]struct{}) string {
+	values := make([]string, 0, len(set))
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
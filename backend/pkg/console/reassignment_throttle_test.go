@@ -0,0 +1,92 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestBuildReassignmentThrottle(t *testing.T) {
+	newPartition := func(partitionID int32, replicas []int32) kmsg.AlterPartitionAssignmentsRequestTopicPartition {
+		partition := kmsg.NewAlterPartitionAssignmentsRequestTopicPartition()
+		partition.Partition = partitionID
+		partition.Replicas = replicas
+		return partition
+	}
+	topic := kmsg.NewAlterPartitionAssignmentsRequestTopic()
+	topic.Topic = "orders"
+	topic.Partitions = []kmsg.AlterPartitionAssignmentsRequestTopicPartition{
+		newPartition(0, []int32{4, 2}),
+		newPartition(1, []int32{2, 1}), // only changes the preferred leader
+		newPartition(2, nil),           // cancels a pending reassignment
+	}
+	current := map[string]map[int32][]int32{"orders": {0: {1, 2}, 1: {1, 2}, 2: {3, 1}}}
+
+	throttle := buildReassignmentThrottle([]kmsg.AlterPartitionAssignmentsRequestTopic{topic}, current, 1024)
+	assert.Equal(t, int64(1024), throttle.Rate)
+	assert.Equal(t, []int32{1, 2, 4}, throttle.BrokerIDs)
+	require.Len(t, throttle.Topics, 1)
+	assert.Equal(t, []string{"0:1", "0:2"}, throttle.Topics[0].LeaderReplicas)
+	assert.Equal(t, []string{"0:4"}, throttle.Topics[0].FollowerReplicas)
+}
+
+func TestSetReassignmentProgress(t *testing.T) {
+	reassignment := PartitionReassignmentsPartition{
+		PartitionID:      0,
+		Replicas:         []int32{4, 5, 1, 2},
+		AddingReplicas:   []int32{4, 5},
+		RemovingReplicas: []int32{1, 2},
+	}
+	setReassignmentProgress(&reassignment, []TopicPartitionLogDirs{
+		{BrokerID: 1, Size: 1000},
+		{BrokerID: 2, Size: 990},
+		{BrokerID: 4, Size: 250},
+	})
+
+	assert.Equal(t, int64(1000), reassignment.Size)
+	assert.Equal(t, int64(250), reassignment.BytesCopied)
+	assert.Equal(t, int64(2000), reassignment.BytesTotal)
+	assert.Equal(t, []PartitionReassignmentReplica{{BrokerID: 4, Size: 250}, {BrokerID: 5}}, reassignment.AddingProgress)
+}
+
+func TestDynamicThrottleRates(t *testing.T) {
+	rate := "1048576"
+	staticRate := "2048"
+	res := &kmsg.DescribeConfigsResponse{
+		Resources: []kmsg.DescribeConfigsResponseResource{
+			{
+				ResourceName: "1",
+				Configs: []kmsg.DescribeConfigsResponseResourceConfig{
+					{Name: configLeaderReplicationThrottledRate, Value: &rate, Source: kmsg.ConfigSourceDynamicBrokerConfig},
+					{Name: configFollowerReplicationThrottledRate, Value: &staticRate, Source: kmsg.ConfigSourceStaticBrokerConfig},
+				},
+			},
+		},
+	}
+
+	rates, err := dynamicThrottleRates(res)
+	require.NoError(t, err)
+	require.NotNil(t, rates[configLeaderReplicationThrottledRate])
+	assert.Equal(t, rate, *rates[configLeaderReplicationThrottledRate])
+	assert.Nil(t, rates[configFollowerReplicationThrottledRate], "static configs must not be restored as dynamic config")
+
+	// Previous rates are restored, rates that have not been set before are deleted
+	restored := restoreAlterConfig(configLeaderReplicationThrottledRate, rates[configLeaderReplicationThrottledRate])
+	assert.Equal(t, kmsg.IncrementalAlterConfigOpSet, restored.Op)
+	require.NotNil(t, restored.Value)
+	assert.Equal(t, rate, *restored.Value)
+	deleted := restoreAlterConfig(configFollowerReplicationThrottledRate, rates[configFollowerReplicationThrottledRate])
+	assert.Equal(t, kmsg.IncrementalAlterConfigOpDelete, deleted.Op)
+	assert.Nil(t, deleted.Value)
+}
@@ -0,0 +1,567 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Reset strategies that are supported when resetting consumer group offsets. They mirror the options of
+// Kafka's kafka-consumer-groups --reset-offsets command.
+const (
+	ResetOffsetsStrategyEarliest    = "earliest"
+	ResetOffsetsStrategyLatest      = "latest"
+	ResetOffsetsStrategyToOffset    = "to-offset"
+	ResetOffsetsStrategyToTimestamp = "to-timestamp"
+	ResetOffsetsStrategyToDatetime  = "to-datetime"
+	ResetOffsetsStrategyShiftBy     = "shift-by"
+	ResetOffsetsStrategyToGroup     = "to-group"
+)
+
+// ResetConsumerGroupOffsetsRequest describes how the offsets of a consumer group shall be reset.
+type ResetConsumerGroupOffsetsRequest struct {
+	Strategy string `json:"strategy"`
+
+	// Topics whose offsets shall be reset. If empty, all topics the group has committed offsets for are reset.
+	// When using the to-group strategy all topics of the source group are reset instead.
+	Topics []ResetOffsetsTopic `json:"topics"`
+
+	// Offset is the target offset for the to-offset strategy.
+	Offset *int64 `json:"offset"`
+
+	// Timestamp is the unix timestamp in milliseconds for the to-timestamp strategy.
+	Timestamp *int64 `json:"timestamp"`
+
+	// Datetime is the default datetime for the to-datetime strategy, e.g. "2022-01-31T12:00:00.000Z". It can be
+	// overridden per partition.
+	Datetime string `json:"datetime"`
+
+	// ShiftBy is the number of offsets that the committed offsets shall be shifted by (can be negative).
+	ShiftBy int64 `json:"shiftBy"`
+
+	// SourceGroupID is the group whose committed offsets are copied when using the to-group strategy.
+	SourceGroupID string `json:"sourceGroupId"`
+
+	// DryRun computes the new offsets and lags without committing them.
+	DryRun bool `json:"dryRun"`
+}
+
+// ResetOffsetsTopic is a topic whose group offsets shall be reset.
+type ResetOffsetsTopic struct {
+	TopicName string `json:"topicName"`
+
+	// Partitions to reset. All partitions of the topic are reset if empty.
+	Partitions []ResetOffsetsPartition `json:"partitions"`
+}
+
+// ResetOffsetsPartition is a partition whose group offset shall be reset.
+type ResetOffsetsPartition struct {
+	PartitionID int32 `json:"partitionId"`
+
+	// Datetime overrides the request's datetime for this partition when using the to-datetime strategy.
+	Datetime string `json:"datetime,omitempty"`
+}
+
+// Validate checks whether all parameters required by the chosen strategy are set.
+func (r *ResetConsumerGroupOffsetsRequest) Validate() error {
+	switch r.Strategy {
+	case ResetOffsetsStrategyEarliest, ResetOffsetsStrategyLatest, ResetOffsetsStrategyShiftBy:
+	case ResetOffsetsStrategyToOffset:
+		if r.Offset == nil {
+			return fmt.Errorf("an offset must be set when using the '%v' strategy", r.Strategy)
+		}
+		if *r.Offset < 0 {
+			return fmt.Errorf("offset must not be negative")
+		}
+	case ResetOffsetsStrategyToTimestamp:
+		if r.Timestamp == nil {
+			return fmt.Errorf("a timestamp must be set when using the '%v' strategy", r.Strategy)
+		}
+		if *r.Timestamp < 0 {
+			return fmt.Errorf("timestamp must not be negative")
+		}
+	case ResetOffsetsStrategyToDatetime:
+		if r.Datetime != "" {
+			if _, err := parseResetDatetime(r.Datetime); err != nil {
+				return err
+			}
+		}
+	case ResetOffsetsStrategyToGroup:
+		if r.SourceGroupID == "" {
+			return fmt.Errorf("a source group id must be set when using the '%v' strategy", r.Strategy)
+		}
+	default:
+		return fmt.Errorf("unknown reset strategy '%v'", r.Strategy)
+	}
+
+	seenTopics := make(map[string]struct{}, len(r.Topics))
+	for _, topic := range r.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic name must not be empty")
+		}
+		if _, exists := seenTopics[topic.TopicName]; exists {
+			return fmt.Errorf("topic '%v' is specified more than once", topic.TopicName)
+		}
+		seenTopics[topic.TopicName] = struct{}{}
+		if r.Strategy == ResetOffsetsStrategyToDatetime && r.Datetime == "" && len(topic.Partitions) == 0 {
+			return fmt.Errorf("topic '%v' must list its partitions with a datetime because no default datetime is set", topic.TopicName)
+		}
+		for _, partition := range topic.Partitions {
+			if partition.Datetime == "" {
+				if r.Strategy == ResetOffsetsStrategyToDatetime && r.Datetime == "" {
+					return fmt.Errorf("topic '%v', partition '%v' has no datetime set and no default datetime is set", topic.TopicName, partition.PartitionID)
+				}
+				continue
+			}
+			if r.Strategy != ResetOffsetsStrategyToDatetime {
+				return fmt.Errorf("partition datetimes can only be set when using the '%v' strategy", ResetOffsetsStrategyToDatetime)
+			}
+			if _, err := parseResetDatetime(partition.Datetime); err != nil {
+				return fmt.Errorf("topic '%v', partition '%v': %w", topic.TopicName, partition.PartitionID, err)
+			}
+		}
+	}
+	if r.Strategy == ResetOffsetsStrategyToDatetime && r.Datetime == "" && len(r.Topics) == 0 {
+		return fmt.Errorf("a datetime must be set if no topics are specified")
+	}
+
+	return nil
+}
+
+// parseResetDatetime parses datetimes in the formats that are accepted by kafka-consumer-groups. Datetimes without
+// timezone are interpreted as UTC.
+func parseResetDatetime(datetime string) (time.Time, error) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05.000", "2006-01-02T15:04:05"}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, datetime)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("datetime '%v' is invalid, expected format is 'YYYY-MM-DDTHH:mm:SS.sss' with optional timezone", datetime)
+}
+
+// ResetConsumerGroupOffsetsResponse contains the computed offsets and resulting lags for all reset partitions.
+type ResetConsumerGroupOffsetsResponse struct {
+	GroupID    string                           `json:"groupId"`
+	GroupState string                           `json:"groupState"`
+	Strategy   string                           `json:"strategy"`
+	IsDryRun   bool                             `json:"isDryRun"`
+	Error      string                           `json:"error,omitempty"`
+	Topics     []ResetConsumerGroupOffsetsTopic `json:"topics"`
+}
+
+// ResetConsumerGroupOffsetsTopic contains the computed offsets of a single topic.
+type ResetConsumerGroupOffsetsTopic struct {
+	TopicName  string                               `json:"topicName"`
+	SummedLag  int64                                `json:"summedLag"`
+	Partitions []ResetConsumerGroupOffsetsPartition `json:"partitions"`
+}
+
+// ResetConsumerGroupOffsetsPartition contains the previous and the computed offset of a single partition, along
+// with the lag the group will have after the reset.
+type ResetConsumerGroupOffsetsPartition struct {
+	PartitionID int32  `json:"partitionId"`
+	Error       string `json:"error,omitempty"`
+
+	// CurrentOffset is the currently committed group offset, -1 if the group has no offset for this partition.
+	CurrentOffset int64 `json:"currentOffset"`
+	NewOffset     int64 `json:"newOffset"`
+	LowWaterMark  int64 `json:"lowWaterMark"`
+	HighWaterMark int64 `json:"highWaterMark"`
+	Lag           int64 `json:"lag"`
+}
+
+// resetPartitionState contains all offsets that are required to resolve the new offset of a single partition.
+// Offsets that are unknown or not required by the strategy are -1.
+type resetPartitionState struct {
+	CurrentOffset   int64
+	SourceOffset    int64
+	TimestampOffset int64
+	Low             int64
+	High            int64
+}
+
+// resolveOffset computes the new offset of a single partition. Like kafka-consumer-groups, offsets that are out of
+// range are clamped to the partition's watermarks.
+func (r *ResetConsumerGroupOffsetsRequest) resolveOffset(state resetPartitionState) (int64, error) {
+	var offset int64
+	switch r.Strategy {
+	case ResetOffsetsStrategyEarliest:
+		offset = state.Low
+	case ResetOffsetsStrategyLatest:
+		offset = state.High
+	case ResetOffsetsStrategyToOffset:
+		offset = *r.Offset
+	case ResetOffsetsStrategyToTimestamp, ResetOffsetsStrategyToDatetime:
+		// An offset of -1 is returned if there is no record with a newer timestamp, hence we reset to the end
+		offset = state.TimestampOffset
+		if offset < 0 {
+			offset = state.High
+		}
+	case ResetOffsetsStrategyShiftBy:
+		if state.CurrentOffset < 0 {
+			return -1, fmt.Errorf("group has no committed offset for this partition that could be shifted")
+		}
+		offset = state.CurrentOffset + r.ShiftBy
+	case ResetOffsetsStrategyToGroup:
+		if state.SourceOffset < 0 {
+			return -1, fmt.Errorf("source group has no committed offset for this partition")
+		}
+		offset = state.SourceOffset
+	default:
+		return -1, fmt.Errorf("unknown reset strategy '%v'", r.Strategy)
+	}
+
+	if offset < state.Low {
+		offset = state.Low
+	}
+	if offset > state.High {
+		offset = state.High
+	}
+
+	return offset, nil
+}
+
+// partitionTimestamp returns the timestamp in milliseconds whose offset shall be looked up for the given partition.
+// It returns -1 if the strategy does not look up offsets by timestamp.
+func (r *ResetConsumerGroupOffsetsRequest) partitionTimestamp(partition ResetOffsetsPartition) int64 {
+	switch r.Strategy {
+	case ResetOffsetsStrategyToTimestamp:
+		return *r.Timestamp
+	case ResetOffsetsStrategyToDatetime:
+		datetime := r.Datetime
+		if partition.Datetime != "" {
+			datetime = partition.Datetime
+		}
+		// Datetimes have been validated already
+		t, _ := parseResetDatetime(datetime)
+		return t.UnixNano() / int64(time.Millisecond)
+	default:
+		return -1
+	}
+}
+
+// ResetConsumerGroupOffsets computes the new group offsets for the given reset strategy and commits them unless
+// the request is a dry run. The returned response contains the computed offsets and the resulting lags either way.
+func (s *Service) ResetConsumerGroupOffsets(ctx context.Context, groupID string, req ResetConsumerGroupOffsetsRequest) (*ResetConsumerGroupOffsetsResponse, *rest.Error) {
+	logger := s.logger.With(zap.String("group_id", groupID))
+
+	// 1. Fetch the group state, the group's current offsets and the offsets of the source group if needed
+	describedGroup, err := s.kafkaSvc.DescribeConsumerGroup(ctx, groupID)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     fmt.Errorf("failed to check group state: %w", err),
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to check consumer group state before proceeding: %v", err.Error()),
+		}
+	}
+	currentOffsets, restErr := s.listGroupOffsets(ctx, groupID)
+	if restErr != nil {
+		return nil, restErr
+	}
+	var sourceOffsets map[string]partitionOffsets
+	if req.Strategy == ResetOffsetsStrategyToGroup {
+		sourceOffsets, restErr = s.listGroupOffsets(ctx, req.SourceGroupID)
+		if restErr != nil {
+			return nil, restErr
+		}
+	}
+
+	// 2. Resolve the topic partitions that shall be reset
+	topics := req.Topics
+	if len(topics) == 0 {
+		groupOffsets := currentOffsets
+		if req.Strategy == ResetOffsetsStrategyToGroup {
+			groupOffsets = sourceOffsets
+		}
+		for topicName := range groupOffsets {
+			topics = append(topics, ResetOffsetsTopic{TopicName: topicName})
+		}
+	}
+	if len(topics) == 0 {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("no topics to reset, because no topics were specified and the group has no committed offsets"),
+			Status:   http.StatusBadRequest,
+			Message:  "No topics specified and the group has no committed offsets to reset",
+			IsSilent: false,
+		}
+	}
+	partitionsByTopic, restErr := s.resolveResetPartitions(ctx, topics)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	// 3. Fetch watermarks and, for timestamp based strategies, the offsets by timestamp
+	topicPartitions := make(map[string][]int32, len(partitionsByTopic))
+	timestamps := make(map[int64]map[string][]int32)
+	for topicName, partitions := range partitionsByTopic {
+		for _, partition := range partitions {
+			topicPartitions[topicName] = append(topicPartitions[topicName], partition.PartitionID)
+
+			ts := req.partitionTimestamp(partition)
+			if ts < 0 {
+				continue
+			}
+			if _, exists := timestamps[ts]; !exists {
+				timestamps[ts] = make(map[string][]int32)
+			}
+			timestamps[ts][topicName] = append(timestamps[ts][topicName], partition.PartitionID)
+		}
+	}
+	waterMarks, err := s.kafkaSvc.GetPartitionMarksBulk(ctx, topicPartitions)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to fetch partition watermarks: %v", err.Error()),
+		}
+	}
+	timestampOffsets := make(map[string]map[int32]int64)
+	timestampErrors := make(map[string]map[int32]error)
+	for ts, tsTopicPartitions := range timestamps {
+		for topicName, partitions := range s.kafkaSvc.ListOffsets(ctx, tsTopicPartitions, ts) {
+			if _, exists := timestampOffsets[topicName]; !exists {
+				timestampOffsets[topicName] = make(map[int32]int64)
+				timestampErrors[topicName] = make(map[int32]error)
+			}
+			for pID, partition := range partitions {
+				timestampOffsets[topicName][pID] = partition.Offset
+				timestampErrors[topicName][pID] = partition.Err
+			}
+		}
+	}
+
+	// 4. Compute the new offsets and resulting lags
+	res := &ResetConsumerGroupOffsetsResponse{
+		GroupID:    groupID,
+		GroupState: describedGroup.State,
+		Strategy:   req.Strategy,
+		IsDryRun:   req.DryRun,
+		Topics:     make([]ResetConsumerGroupOffsetsTopic, 0, len(partitionsByTopic)),
+	}
+	hasErrors := false
+	for topicName, partitions := range partitionsByTopic {
+		topicRes := ResetConsumerGroupOffsetsTopic{
+			TopicName:  topicName,
+			Partitions: make([]ResetConsumerGroupOffsetsPartition, 0, len(partitions)),
+		}
+		for _, partition := range partitions {
+			pID := partition.PartitionID
+			partitionRes := ResetConsumerGroupOffsetsPartition{
+				PartitionID:   pID,
+				CurrentOffset: offsetOrDefault(currentOffsets, topicName, pID),
+				NewOffset:     -1,
+				LowWaterMark:  -1,
+				HighWaterMark: -1,
+			}
+
+			marks, exists := waterMarks[topicName][pID]
+			if !exists || marks.Error != nil {
+				errMsg := "watermarks are missing"
+				if exists {
+					errMsg = fmt.Sprintf("failed to fetch watermarks: %v", marks.Error.Error())
+				}
+				partitionRes.Error = errMsg
+				topicRes.Partitions = append(topicRes.Partitions, partitionRes)
+				hasErrors = true
+				continue
+			}
+			partitionRes.LowWaterMark = marks.Low
+			partitionRes.HighWaterMark = marks.High
+
+			if tsErr := timestampErrors[topicName][pID]; tsErr != nil {
+				partitionRes.Error = fmt.Sprintf("failed to list offsets by timestamp: %v", tsErr.Error())
+				topicRes.Partitions = append(topicRes.Partitions, partitionRes)
+				hasErrors = true
+				continue
+			}
+			timestampOffset, hasTimestampOffset := timestampOffsets[topicName][pID]
+			if !hasTimestampOffset {
+				timestampOffset = -1
+			}
+
+			newOffset, err := req.resolveOffset(resetPartitionState{
+				CurrentOffset:   partitionRes.CurrentOffset,
+				SourceOffset:    offsetOrDefault(sourceOffsets, topicName, pID),
+				TimestampOffset: timestampOffset,
+				Low:             marks.Low,
+				High:            marks.High,
+			})
+			if err != nil {
+				partitionRes.Error = err.Error()
+				topicRes.Partitions = append(topicRes.Partitions, partitionRes)
+				hasErrors = true
+				continue
+			}
+			partitionRes.NewOffset = newOffset
+			partitionRes.Lag = marks.High - newOffset
+			topicRes.SummedLag += partitionRes.Lag
+			topicRes.Partitions = append(topicRes.Partitions, partitionRes)
+		}
+		sort.Slice(topicRes.Partitions, func(i, j int) bool {
+			return topicRes.Partitions[i].PartitionID < topicRes.Partitions[j].PartitionID
+		})
+		res.Topics = append(res.Topics, topicRes)
+	}
+	sort.Slice(res.Topics, func(i, j int) bool { return res.Topics[i].TopicName < res.Topics[j].TopicName })
+
+	if req.DryRun {
+		return res, nil
+	}
+	if hasErrors {
+		res.Error = "Offsets could not be computed for all partitions, therefore no offsets have been committed"
+		return res, nil
+	}
+
+	// 5. Commit the new offsets. Like kafka-consumer-groups we only allow this for inactive groups. Groups that do
+	// not exist yet are reported as dead and will be created by the commit.
+	if !strings.EqualFold(describedGroup.State, "empty") && !strings.EqualFold(describedGroup.State, "dead") {
+		res.Error = fmt.Sprintf("Consumer group is still active and therefore can't be edited. Current Group State is: %v", describedGroup.State)
+		return res, nil
+	}
+
+	commitTopics := make([]kmsg.OffsetCommitRequestTopic, len(res.Topics))
+	for i, topic := range res.Topics {
+		commitTopic := kmsg.NewOffsetCommitRequestTopic()
+		commitTopic.Topic = topic.TopicName
+		for _, partition := range topic.Partitions {
+			commitPartition := kmsg.NewOffsetCommitRequestTopicPartition()
+			commitPartition.Partition = partition.PartitionID
+			commitPartition.Offset = partition.NewOffset
+			commitTopic.Partitions = append(commitTopic.Partitions, commitPartition)
+		}
+		commitTopics[i] = commitTopic
+	}
+	commitResponse, err := s.kafkaSvc.EditConsumerGroupOffsets(ctx, groupID, commitTopics)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Reset consumer group offsets failed: %v", err.Error()),
+		}
+	}
+
+	commitErrors := make(map[string]map[int32]error)
+	for _, topic := range commitResponse.Topics {
+		commitErrors[topic.Topic] = make(map[int32]error)
+		for _, partition := range topic.Partitions {
+			commitErrors[topic.Topic][partition.Partition] = kerr.ErrorForCode(partition.ErrorCode)
+		}
+	}
+	for i, topic := range res.Topics {
+		for j, partition := range topic.Partitions {
+			if err := commitErrors[topic.TopicName][partition.PartitionID]; err != nil {
+				res.Topics[i].Partitions[j].Error = fmt.Sprintf("failed to commit offset: %v", err.Error())
+				logger.Warn("failed to commit reset group offset",
+					zap.String("topic_name", topic.TopicName),
+					zap.Int32("partition_id", partition.PartitionID),
+					zap.Error(err))
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// listGroupOffsets returns the committed offsets of the given group as a map of: topicName -> partitionID -> offset
+func (s *Service) listGroupOffsets(ctx context.Context, groupID string) (map[string]partitionOffsets, *rest.Error) {
+	offsets, err := s.kafkaSvc.ListConsumerGroupOffsets(ctx, groupID)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          err,
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to list committed offsets of consumer group '%v': %v", groupID, err.Error()),
+			InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+		}
+	}
+
+	return convertOffsets(offsets), nil
+}
+
+// resolveResetPartitions returns all partitions that shall be reset, grouped by topic. Topics without explicitly
+// specified partitions are expanded to all of their partitions.
+func (s *Service) resolveResetPartitions(ctx context.Context, topics []ResetOffsetsTopic) (map[string][]ResetOffsetsPartition, *rest.Error) {
+	topicNames := make([]string, len(topics))
+	for i, topic := range topics {
+		topicNames[i] = topic.TopicName
+	}
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to request topic metadata: %v", err.Error()),
+		}
+	}
+
+	partitionIDsByTopic := make(map[string]map[int32]struct{}, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		topicName := *topic.Topic
+		err := kerr.ErrorForCode(topic.ErrorCode)
+		if err != nil {
+			status := http.StatusServiceUnavailable
+			if err == kerr.UnknownTopicOrPartition {
+				status = http.StatusNotFound
+			}
+			return nil, &rest.Error{
+				Err:     fmt.Errorf("failed to request metadata for topic '%v': %w", topicName, err),
+				Status:  status,
+				Message: fmt.Sprintf("Failed to request metadata for topic '%v': %v", topicName, err.Error()),
+			}
+		}
+		partitionIDsByTopic[topicName] = make(map[int32]struct{}, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			partitionIDsByTopic[topicName][partition.Partition] = struct{}{}
+		}
+	}
+
+	res := make(map[string][]ResetOffsetsPartition, len(topics))
+	for _, topic := range topics {
+		partitionIDs := partitionIDsByTopic[topic.TopicName]
+		if len(topic.Partitions) == 0 {
+			for pID := range partitionIDs {
+				res[topic.TopicName] = append(res[topic.TopicName], ResetOffsetsPartition{PartitionID: pID})
+			}
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			if _, exists := partitionIDs[partition.PartitionID]; !exists {
+				return nil, &rest.Error{
+					Err:     fmt.Errorf("partition '%v' does not exist in topic '%v'", partition.PartitionID, topic.TopicName),
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("Partition '%v' does not exist in topic '%v'", partition.PartitionID, topic.TopicName),
+				}
+			}
+			res[topic.TopicName] = append(res[topic.TopicName], partition)
+		}
+	}
+
+	return res, nil
+}
+
+func offsetOrDefault(offsets map[string]partitionOffsets, topicName string, partitionID int32) int64 {
+	offset, exists := offsets[topicName][partitionID]
+	if !exists {
+		return -1
+	}
+	return offset
+}
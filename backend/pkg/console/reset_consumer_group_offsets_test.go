@@ -0,0 +1,113 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetConsumerGroupOffsetsRequest_ResolveOffset(t *testing.T) {
+	offset42, offset2 := int64(42), int64(2)
+	state := resetPartitionState{CurrentOffset: 50, SourceOffset: 70, TimestampOffset: 30, Low: 10, High: 100}
+
+	tests := []struct {
+		name     string
+		req      ResetConsumerGroupOffsetsRequest
+		state    resetPartitionState
+		expected int64
+		hasError bool
+	}{
+		{name: "earliest", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyEarliest}, state: state, expected: 10},
+		{name: "latest", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyLatest}, state: state, expected: 100},
+		{name: "to offset", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToOffset, Offset: &offset42}, state: state, expected: 42},
+		{name: "to offset clamped to low watermark", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToOffset, Offset: &offset2}, state: state, expected: 10},
+		{name: "to timestamp", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToTimestamp}, state: state, expected: 30},
+		{
+			name:     "to datetime without newer records",
+			req:      ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToDatetime},
+			state:    resetPartitionState{CurrentOffset: 50, SourceOffset: -1, TimestampOffset: -1, Low: 10, High: 100},
+			expected: 100,
+		},
+		{name: "shift by forward", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyShiftBy, ShiftBy: 20}, state: state, expected: 70},
+		{name: "shift by clamped to high watermark", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyShiftBy, ShiftBy: 200}, state: state, expected: 100},
+		{name: "shift by backward", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyShiftBy, ShiftBy: -45}, state: state, expected: 10},
+		{
+			name:     "shift by without committed offset",
+			req:      ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyShiftBy, ShiftBy: 1},
+			state:    resetPartitionState{CurrentOffset: -1, SourceOffset: -1, TimestampOffset: -1, Low: 10, High: 100},
+			hasError: true,
+		},
+		{name: "to group", req: ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToGroup, SourceGroupID: "other"}, state: state, expected: 70},
+		{
+			name:     "to group without source offset",
+			req:      ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToGroup, SourceGroupID: "other"},
+			state:    resetPartitionState{CurrentOffset: 50, SourceOffset: -1, TimestampOffset: -1, Low: 10, High: 100},
+			hasError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			offset, err := test.req.resolveOffset(test.state)
+			if test.hasError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, offset)
+		})
+	}
+}
+
+func TestResetConsumerGroupOffsetsRequest_Validate(t *testing.T) {
+	valid := ResetConsumerGroupOffsetsRequest{
+		Strategy: ResetOffsetsStrategyToDatetime,
+		Topics: []ResetOffsetsTopic{
+			{TopicName: "orders", Partitions: []ResetOffsetsPartition{{PartitionID: 0, Datetime: "2022-01-31T12:00:00.000"}}},
+		},
+	}
+	assert.NoError(t, valid.Validate())
+
+	missingDatetime := ResetConsumerGroupOffsetsRequest{
+		Strategy: ResetOffsetsStrategyToDatetime,
+		Topics:   []ResetOffsetsTopic{{TopicName: "orders"}},
+	}
+	assert.Error(t, missingDatetime.Validate())
+
+	partitionDatetimeWithOtherStrategy := ResetConsumerGroupOffsetsRequest{
+		Strategy: ResetOffsetsStrategyLatest,
+		Topics: []ResetOffsetsTopic{
+			{TopicName: "orders", Partitions: []ResetOffsetsPartition{{PartitionID: 0, Datetime: "2022-01-31T12:00:00Z"}}},
+		},
+	}
+	assert.Error(t, partitionDatetimeWithOtherStrategy.Validate())
+
+	assert.Error(t, (&ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToGroup}).Validate())
+	assert.Error(t, (&ResetConsumerGroupOffsetsRequest{Strategy: "to-nowhere"}).Validate())
+
+	// The target offset and timestamp must be set explicitly, zero is a valid value
+	zero := int64(0)
+	assert.Error(t, (&ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToOffset}).Validate())
+	assert.NoError(t, (&ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToOffset, Offset: &zero}).Validate())
+	assert.Error(t, (&ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToTimestamp}).Validate())
+	assert.NoError(t, (&ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToTimestamp, Timestamp: &zero}).Validate())
+}
+
+func TestResetConsumerGroupOffsetsRequest_PartitionTimestamp(t *testing.T) {
+	req := ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyToDatetime, Datetime: "2022-01-31T12:00:00Z"}
+	assert.Equal(t, int64(1643630400000), req.partitionTimestamp(ResetOffsetsPartition{PartitionID: 0}))
+	assert.Equal(t, int64(1643634000000), req.partitionTimestamp(ResetOffsetsPartition{PartitionID: 1, Datetime: "2022-01-31T14:00:00+01:00"}))
+
+	req = ResetConsumerGroupOffsetsRequest{Strategy: ResetOffsetsStrategyShiftBy}
+	assert.Equal(t, int64(-1), req.partitionTimestamp(ResetOffsetsPartition{PartitionID: 0}))
+}
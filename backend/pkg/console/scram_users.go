@@ -0,0 +1,258 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	ScramMechanismSHA256 = "SCRAM-SHA-256"
+	ScramMechanismSHA512 = "SCRAM-SHA-512"
+
+	// Kafka rejects SCRAM credentials with less than 4096 or more than 16384 iterations
+	ScramMinIterations     = 4096
+	ScramMaxIterations     = 16384
+	ScramDefaultIterations = 4096
+
+	scramSaltLength = 32
+)
+
+// ScramUser is a user along with all its SCRAM credentials.
+type ScramUser struct {
+	Name        string            `json:"name"`
+	Credentials []ScramCredential `json:"credentials"`
+}
+
+// ScramCredential describes a single SCRAM credential of a user. The salted password is never returned by Kafka.
+type ScramCredential struct {
+	Mechanism  string `json:"mechanism"`
+	Iterations int32  `json:"iterations"`
+}
+
+// ScramMechanismFromString returns the Kafka mechanism id for the given SCRAM mechanism name.
+func ScramMechanismFromString(mechanism string) (int8, error) {
+	switch mechanism {
+	case ScramMechanismSHA256:
+		return 1, nil
+	case ScramMechanismSHA512:
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("unknown SCRAM mechanism '%v', must be %v or %v", mechanism, ScramMechanismSHA256, ScramMechanismSHA512)
+	}
+}
+
+func scramMechanismToString(mechanism int8) string {
+	switch mechanism {
+	case 1:
+		return ScramMechanismSHA256
+	case 2:
+		return ScramMechanismSHA512
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ListScramUsers returns all users that have SCRAM credentials. If a user name is given, only this user is described.
+func (s *Service) ListScramUsers(ctx context.Context, userName *string) ([]ScramUser, *rest.Error) {
+	req := kmsg.NewDescribeUserSCRAMCredentialsRequest()
+	if userName != nil {
+		user := kmsg.NewDescribeUserSCRAMCredentialsRequestUser()
+		user.Name = *userName
+		req.Users = []kmsg.DescribeUserSCRAMCredentialsRequestUser{user}
+	}
+
+	res, err := s.kafkaSvc.DescribeUserSCRAMCredentials(ctx, req)
+	if err == nil {
+		err = kerr.ErrorForCode(res.ErrorCode)
+	}
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe SCRAM users: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	users := make([]ScramUser, 0, len(res.Results))
+	for _, result := range res.Results {
+		err := kerr.ErrorForCode(result.ErrorCode)
+		if err != nil {
+			if errors.Is(err, kerr.ResourceNotFound) {
+				continue
+			}
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to describe SCRAM user '%v': %v", result.User, err.Error()),
+				IsSilent: false,
+			}
+		}
+
+		credentials := make([]ScramCredential, len(result.CredentialInfos))
+		for i, info := range result.CredentialInfos {
+			credentials[i] = ScramCredential{
+				Mechanism:  scramMechanismToString(info.Mechanism),
+				Iterations: info.Iterations,
+			}
+		}
+		users = append(users, ScramUser{Name: result.User, Credentials: credentials})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+
+	return users, nil
+}
+
+// UpsertScramUser creates or updates the SCRAM credential of the given user and mechanism. The password is salted
+// client side, so that it is never sent to Kafka in plain text.
+func (s *Service) UpsertScramUser(ctx context.Context, userName string, mechanism string, password string, iterations int32) *rest.Error {
+	mechanismID, err := ScramMechanismFromString(mechanism)
+	if err != nil {
+		return &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  err.Error(),
+			IsSilent: true,
+		}
+	}
+
+	salt := make([]byte, scramSaltLength)
+	_, err = rand.Read(salt)
+	if err != nil {
+		return &rest.Error{
+			Err:      fmt.Errorf("failed to generate salt: %w", err),
+			Status:   http.StatusInternalServerError,
+			Message:  "Failed to generate salt for SCRAM credential",
+			IsSilent: false,
+		}
+	}
+
+	upsertion := kmsg.NewAlterUserSCRAMCredentialsRequestUpsertion()
+	upsertion.Name = userName
+	upsertion.Mechanism = mechanismID
+	upsertion.Iterations = iterations
+	upsertion.Salt = salt
+	upsertion.SaltedPassword = saltScramPassword(mechanism, password, salt, int(iterations))
+
+	req := kmsg.NewAlterUserSCRAMCredentialsRequest()
+	req.Upsertions = []kmsg.AlterUserSCRAMCredentialsRequestUpsertion{upsertion}
+	return s.alterScramCredentials(ctx, userName, req)
+}
+
+// DeleteScramUser deletes the SCRAM credential of the given user and mechanism. If no mechanism is given,
+// all credentials of the user will be deleted.
+func (s *Service) DeleteScramUser(ctx context.Context, userName string, mechanism string) *rest.Error {
+	mechanisms := []string{mechanism}
+	if mechanism == "" {
+		users, restErr := s.ListScramUsers(ctx, &userName)
+		if restErr != nil {
+			return restErr
+		}
+		mechanisms = make([]string, 0)
+		for _, user := range users {
+			for _, credential := range user.Credentials {
+				mechanisms = append(mechanisms, credential.Mechanism)
+			}
+		}
+		if len(mechanisms) == 0 {
+			return &rest.Error{
+				Err:      fmt.Errorf("user has no SCRAM credentials"),
+				Status:   http.StatusNotFound,
+				Message:  fmt.Sprintf("User '%v' has no SCRAM credentials", userName),
+				IsSilent: true,
+			}
+		}
+	}
+
+	req := kmsg.NewAlterUserSCRAMCredentialsRequest()
+	for _, m := range mechanisms {
+		mechanismID, err := ScramMechanismFromString(m)
+		if err != nil {
+			return &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  err.Error(),
+				IsSilent: true,
+			}
+		}
+		deletion := kmsg.NewAlterUserSCRAMCredentialsRequestDeletion()
+		deletion.Name = userName
+		deletion.Mechanism = mechanismID
+		req.Deletions = append(req.Deletions, deletion)
+	}
+
+	return s.alterScramCredentials(ctx, userName, req)
+}
+
+func (s *Service) alterScramCredentials(ctx context.Context, userName string, req kmsg.AlterUserSCRAMCredentialsRequest) *rest.Error {
+	res, err := s.kafkaSvc.AlterUserSCRAMCredentials(ctx, req)
+	if err != nil {
+		return &rest.Error{
+			Err:          err,
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to alter SCRAM credentials: %v", err.Error()),
+			InternalLogs: []zapcore.Field{zap.String("user", userName)},
+			IsSilent:     false,
+		}
+	}
+
+	for _, result := range res.Results {
+		err := kerr.ErrorForCode(result.ErrorCode)
+		if err == nil {
+			continue
+		}
+		msg := err.Error()
+		if result.ErrorMessage != nil && *result.ErrorMessage != "" {
+			msg = fmt.Sprintf("%v: %v", msg, *result.ErrorMessage)
+		}
+		status := http.StatusServiceUnavailable
+		if errors.Is(err, kerr.ResourceNotFound) {
+			status = http.StatusNotFound
+		}
+		return &rest.Error{
+			Err:          err,
+			Status:       status,
+			Message:      fmt.Sprintf("Failed to alter SCRAM credentials of user '%v': %v", result.User, msg),
+			InternalLogs: []zapcore.Field{zap.String("user", userName)},
+			IsSilent:     false,
+		}
+	}
+
+	return nil
+}
+
+// saltScramPassword computes SaltedPassword := Hi(Normalize(password), salt, i) as defined in RFC 5802, which
+// is PBKDF2 with HMAC as pseudorandom function.
+func saltScramPassword(mechanism string, password string, salt []byte, iterations int) []byte {
+	var hashFn func() hash.Hash
+	switch mechanism {
+	case ScramMechanismSHA512:
+		hashFn = sha512.New
+	default:
+		hashFn = sha256.New
+	}
+	return pbkdf2.Key([]byte(password), salt, iterations, hashFn().Size(), hashFn)
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSaltScramPassword verifies the salted password against the SCRAM-SHA-256 test vector of RFC 7677,
+// section 3. The RFC only lists the exchanged messages, hence the client proof and the server signature are
+// derived from the salted password and compared with the messages.
+func TestSaltScramPassword(t *testing.T) {
+	salt, err := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+	require.NoError(t, err)
+	saltedPassword := saltScramPassword(ScramMechanismSHA256, "pencil", salt, 4096)
+
+	hmacSHA256 := func(key []byte, message string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(message))
+		return mac.Sum(nil)
+	}
+	authMessage := "n=user,r=rOprNGfwEbeRWgbNEkqO," +
+		"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096," +
+		"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0"
+
+	clientKey := hmacSHA256(saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	clientSignature := hmacSHA256(storedKey[:], authMessage)
+	clientProof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		clientProof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	assert.Equal(t, "dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=", base64.StdEncoding.EncodeToString(clientProof))
+
+	serverKey := hmacSHA256(saltedPassword, "Server Key")
+	serverSignature := hmacSHA256(serverKey, authMessage)
+	assert.Equal(t, "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=", base64.StdEncoding.EncodeToString(serverSignature))
+}
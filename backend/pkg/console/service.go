@@ -23,10 +23,38 @@ type Service struct {
 	kafkaSvc *kafka.Service
 	gitSvc   *git.Service // Git service can be nil if not configured
 	logger   *zap.Logger
+
+	// topicManagementGitSvc provides the desired topics document, it is nil if not configured
+	topicManagementGitSvc *git.Service
+	topicManagementCfg    ConfigTopicManagement
+
+	// lagCollector records the lag history of all consumer groups, it is nil if not enabled
+	lagCollector *lagCollector
+
+	// topicActivitySampler records the high water marks of all topics, it is nil if not enabled
+	topicActivitySampler *topicActivitySampler
+
+	// clusterHealthExporter exports the cluster health as Prometheus metrics, it is nil if not enabled
+	clusterHealthExporter *clusterHealthExporter
+
+	// groupWatcher records membership and state changes of all consumer groups, it is nil if not enabled
+	groupWatcher *groupWatcher
+
+	// topicTemplates are the configured topic templates by template name
+	topicTemplates map[string]ConfigTopicTemplate
+
+	// topicConfigRules are the compiled rules of the topic config policy
+	topicConfigRules []topicConfigRule
+
+	// reassignmentThrottler removes replication throttles once the throttled reassignments are completed
+	reassignmentThrottler *reassignmentThrottler
+
+	// topicDeletionConfirmations holds the topics of pending bulk topic deletions by their confirmation token
+	topicDeletionConfirmations *topicDeletionConfirmations
 }
 
 // NewService for the Console package
-func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service) (*Service, error) {
+func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service, metricsNamespace string) (*Service, error) {
 	var gitSvc *git.Service
 	cfg.TopicDocumentation.Git.AllowedFileExtensions = []string{"md"}
 	if cfg.TopicDocumentation.Enabled && cfg.TopicDocumentation.Git.Enabled {
@@ -36,16 +64,77 @@ func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service) (*Servi
 		}
 		gitSvc = svc
 	}
-	return &Service{
+	var topicManagementGitSvc *git.Service
+	if cfg.TopicManagement.Git.Enabled {
+		svc, err := git.NewService(cfg.TopicManagement.Git, logger, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create git service for topic management: %w", err)
+		}
+		topicManagementGitSvc = svc
+	}
+	topicConfigRules, err := compileTopicConfigRules(cfg.TopicConfigPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile topic config policy: %w", err)
+	}
+	topicTemplates := make(map[string]ConfigTopicTemplate, len(cfg.TopicTemplates))
+	for _, template := range cfg.TopicTemplates {
+		topicTemplates[template.Name] = template
+	}
+	svc := &Service{
 		kafkaSvc: kafkaSvc,
 		gitSvc:   gitSvc,
 		logger:   logger,
-	}, nil
+
+		topicManagementGitSvc: topicManagementGitSvc,
+		topicManagementCfg:    cfg.TopicManagement,
+		topicTemplates:        topicTemplates,
+		topicConfigRules:      topicConfigRules,
+
+		reassignmentThrottler:      newReassignmentThrottler(logger, kafkaSvc),
+		topicDeletionConfirmations: newTopicDeletionConfirmations(topicDeletionConfirmationTTL),
+	}
+	if cfg.LagHistory.Enabled {
+		svc.lagCollector = newLagCollector(cfg.LagHistory, logger, metricsNamespace, svc.getAllConsumerGroupLags)
+	}
+	if cfg.TopicActivity.Enabled {
+		svc.topicActivitySampler = newTopicActivitySampler(cfg.TopicActivity, logger, svc.getSummedHighWaterMarks)
+	}
+	if cfg.ClusterHealth.Enabled {
+		svc.clusterHealthExporter = newClusterHealthExporter(cfg.ClusterHealth, logger, metricsNamespace, svc.evaluateClusterHealth)
+	}
+	if cfg.GroupEvents.Enabled {
+		convertGroups := func(describedGroups *kafka.DescribeConsumerGroupsResponseSharded) []ConsumerGroupOverview {
+			return svc.convertKgoGroupDescriptions(describedGroups, nil)
+		}
+		svc.groupWatcher = newGroupWatcher(cfg.GroupEvents, logger, kafkaSvc, convertGroups)
+	}
+
+	return svc, nil
 }
 
 // Start starts all the (background) tasks which are required for this service to work properly. If any of these
 // tasks can not be setup an error will be returned which will cause the application to exit.
 func (s *Service) Start() error {
+	if s.lagCollector != nil {
+		s.lagCollector.Start()
+	}
+	if s.groupWatcher != nil {
+		s.groupWatcher.Start()
+	}
+	if s.topicActivitySampler != nil {
+		s.topicActivitySampler.Start()
+	}
+	if s.clusterHealthExporter != nil {
+		s.clusterHealthExporter.Start()
+	}
+
+	if s.topicManagementGitSvc != nil {
+		err := s.topicManagementGitSvc.Start()
+		if err != nil {
+			return fmt.Errorf("failed to start git service for topic management: %w", err)
+		}
+	}
+
 	if s.gitSvc == nil {
 		return nil
 	}
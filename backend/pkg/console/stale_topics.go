@@ -0,0 +1,187 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const (
+	// StaleTopicEmpty means that the topic does not contain any messages.
+	StaleTopicEmpty = "empty"
+	// StaleTopicNeverConsumed means that no consumer group has committed offsets for the topic.
+	StaleTopicNeverConsumed = "neverConsumed"
+	// StaleTopicNoRecentWrites means that the latest message is older than the inactivity threshold and that no
+	// messages have been produced while the high water marks have been sampled.
+	StaleTopicNoRecentWrites = "noRecentWrites"
+	// StaleTopicOrphanedGroups means that at least one consumer group with committed offsets has no active members.
+	StaleTopicOrphanedGroups = "orphanedConsumerGroups"
+)
+
+// StaleTopicsReport lists all topics that have been classified as stale.
+type StaleTopicsReport struct {
+	InactiveDays int `json:"inactiveDays"`
+
+	// IsActivitySamplingEnabled is true if the high water marks are sampled in the background. Otherwise the
+	// producer activity of topics is not known and only the latest message timestamp is considered.
+	IsActivitySamplingEnabled bool         `json:"isActivitySamplingEnabled"`
+	Topics                    []StaleTopic `json:"topics"`
+}
+
+type StaleTopic struct {
+	TopicName      string `json:"topicName"`
+	PartitionCount int    `json:"partitionCount"`
+	MessageCount   int64  `json:"messageCount"`
+
+	// LastMessageTimestamp is the timestamp (in ms) of the latest message in all partitions. It is -1 if the
+	// topic is empty or the latest message could not be consumed in time.
+	LastMessageTimestamp int64                    `json:"lastMessageTimestamp"`
+	LogDirSummary        TopicLogDirSummary       `json:"logDirSummary"`
+	ProducerActivity     *TopicProducerActivity   `json:"producerActivity,omitempty"`
+	ConsumerGroups       []CommittedConsumerGroup `json:"consumerGroups"`
+
+	// Classifications contains one or more of the StaleTopic* constants.
+	Classifications []string `json:"classifications"`
+}
+
+// GetStaleTopics classifies all non internal topics by their messages, consumer groups and producer activity and
+// returns the topics that have at least one classification. Topics are considered to have no recent writes if
+// their latest message is older than inactiveDays.
+func (s *Service) GetStaleTopics(ctx context.Context, inactiveDays int) (*StaleTopicsReport, *rest.Error) {
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get topic metadata from cluster: %v", err.Error()),
+		}
+	}
+	topics := make([]kmsg.MetadataResponseTopic, 0, len(metadata.Topics))
+	topicPartitions := make(map[string][]int32)
+	for _, topic := range metadata.Topics {
+		if kerr.ErrorForCode(topic.ErrorCode) != nil || topic.IsInternal {
+			continue
+		}
+		topics = append(topics, topic)
+		for _, partition := range topic.Partitions {
+			topicPartitions[*topic.Topic] = append(topicPartitions[*topic.Topic], partition.Partition)
+		}
+	}
+
+	// 1. Get consumer groups with committed offsets along with their members
+	topicNames := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		topicNames = append(topicNames, *topic.Topic)
+	}
+	groupsByTopic, err := s.getConsumerGroupsByTopic(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get consumer groups of topics: %v", err.Error()),
+		}
+	}
+
+	// 2. Get watermarks, latest message timestamps and log dir sizes
+	marks, err := s.kafkaSvc.GetPartitionMarksBulk(ctx, topicPartitions)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get partition watermarks: %v", err.Error()),
+		}
+	}
+	lastTimestamps := s.getLatestMessageTimestamps(ctx, marks)
+
+	// Log dirs are described by each broker, an unavailable broker must not block the whole report
+	logDirCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	filteredMetadata := *metadata
+	filteredMetadata.Topics = topics
+	logDirsByTopic := s.logDirsByTopic(logDirCtx, &filteredMetadata)
+
+	// 3. Classify topics
+	report := &StaleTopicsReport{
+		InactiveDays:              inactiveDays,
+		IsActivitySamplingEnabled: s.topicActivitySampler != nil,
+		Topics:                    make([]StaleTopic, 0),
+	}
+	inactiveSince := unixMillis(time.Now().Add(-time.Duration(inactiveDays) * 24 * time.Hour))
+	for _, topic := range topics {
+		topicName := *topic.Topic
+		staleTopic := StaleTopic{
+			TopicName:            topicName,
+			PartitionCount:       len(topic.Partitions),
+			MessageCount:         -1,
+			LastMessageTimestamp: latestTimestampOf(lastTimestamps, topicName).Timestamp,
+			LogDirSummary:        logDirsByTopic[topicName],
+			ConsumerGroups:       groupsByTopic[topicName],
+		}
+
+		messageCount := int64(0)
+		for _, mark := range marks[topicName] {
+			if mark.Error != nil || mark.Low < 0 || mark.High < 0 {
+				messageCount = -1
+				break
+			}
+			messageCount += mark.High - mark.Low
+		}
+		staleTopic.MessageCount = messageCount
+
+		if s.topicActivitySampler != nil {
+			if activity, exists := s.topicActivitySampler.activity(topicName); exists {
+				staleTopic.ProducerActivity = &activity
+			}
+		}
+
+		staleTopic.Classifications = classifyStaleTopic(staleTopic, inactiveSince)
+		if len(staleTopic.Classifications) > 0 {
+			report.Topics = append(report.Topics, staleTopic)
+		}
+	}
+	sort.Slice(report.Topics, func(i, j int) bool { return report.Topics[i].TopicName < report.Topics[j].TopicName })
+
+	return report, nil
+}
+
+// classifyStaleTopic returns the stale topic classifications that apply to the given topic. Topics whose
+// latest message is older than inactiveSince (in ms) have no recent writes, unless new messages have been
+// sampled in the meantime (e.g. because producers set old timestamps).
+func classifyStaleTopic(topic StaleTopic, inactiveSince int64) []string {
+	classifications := make([]string, 0)
+	if topic.MessageCount == 0 {
+		classifications = append(classifications, StaleTopicEmpty)
+	}
+	if len(topic.ConsumerGroups) == 0 {
+		classifications = append(classifications, StaleTopicNeverConsumed)
+	}
+
+	hasSampledWrites := topic.ProducerActivity != nil && topic.ProducerActivity.MessagesProduced > 0
+	if topic.MessageCount != 0 && topic.LastMessageTimestamp >= 0 && topic.LastMessageTimestamp < inactiveSince && !hasSampledWrites {
+		classifications = append(classifications, StaleTopicNoRecentWrites)
+	}
+
+	for _, group := range topic.ConsumerGroups {
+		if group.MemberCount == 0 {
+			classifications = append(classifications, StaleTopicOrphanedGroups)
+			break
+		}
+	}
+
+	return classifications
+}
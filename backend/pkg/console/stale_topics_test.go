@@ -0,0 +1,80 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyStaleTopic(t *testing.T) {
+	inactiveSince := int64(10000)
+	activeGroup := CommittedConsumerGroup{GroupID: "active", State: "Stable", MemberCount: 2}
+	orphanedGroup := CommittedConsumerGroup{GroupID: "orphaned", State: "Empty", MemberCount: 0}
+
+	tt := []struct {
+		name     string
+		topic    StaleTopic
+		expected []string
+	}{
+		{
+			name:     "active topic",
+			topic:    StaleTopic{MessageCount: 10, LastMessageTimestamp: 20000, ConsumerGroups: []CommittedConsumerGroup{activeGroup}},
+			expected: []string{},
+		},
+		{
+			name:     "empty and never consumed",
+			topic:    StaleTopic{MessageCount: 0, LastMessageTimestamp: -1},
+			expected: []string{StaleTopicEmpty, StaleTopicNeverConsumed},
+		},
+		{
+			name:     "no recent writes with orphaned group",
+			topic:    StaleTopic{MessageCount: 10, LastMessageTimestamp: 5000, ConsumerGroups: []CommittedConsumerGroup{activeGroup, orphanedGroup}},
+			expected: []string{StaleTopicNoRecentWrites, StaleTopicOrphanedGroups},
+		},
+		{
+			name: "old timestamps but sampled writes",
+			topic: StaleTopic{MessageCount: 10, LastMessageTimestamp: 5000, ConsumerGroups: []CommittedConsumerGroup{activeGroup},
+				ProducerActivity: &TopicProducerActivity{MessagesProduced: 3}},
+			expected: []string{},
+		},
+		{
+			name:     "unknown message count",
+			topic:    StaleTopic{MessageCount: -1, LastMessageTimestamp: -1, ConsumerGroups: []CommittedConsumerGroup{activeGroup}},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, classifyStaleTopic(test.topic, inactiveSince))
+		})
+	}
+}
+
+func TestProducerActivity(t *testing.T) {
+	_, exists := producerActivity([]TopicActivitySample{{Timestamp: 1000, HighWaterMark: 10}})
+	assert.False(t, exists)
+
+	// The high water mark decreases when a topic is recreated, which must not count as produced messages
+	activity, exists := producerActivity([]TopicActivitySample{
+		{Timestamp: 1000, HighWaterMark: 10},
+		{Timestamp: 2000, HighWaterMark: 30},
+		{Timestamp: 3000, HighWaterMark: 5},
+		{Timestamp: 5000, HighWaterMark: 25},
+	})
+	require.True(t, exists)
+	assert.Equal(t, int64(1000), activity.SampledSince)
+	assert.Equal(t, 4, activity.SampleCount)
+	assert.Equal(t, int64(40), activity.MessagesProduced)
+	assert.Equal(t, 10.0, activity.MessagesPerSecond)
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"go.uber.org/zap"
+
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+// TopicActivitySample is the summed high water mark of all partitions of a topic at a point in time.
+type TopicActivitySample struct {
+	Timestamp     int64 `json:"timestamp"`
+	HighWaterMark int64 `json:"highWaterMark"`
+}
+
+// TopicProducerActivity describes the messages that have been produced to a topic within the sampled time range.
+type TopicProducerActivity struct {
+	SampledSince      int64   `json:"sampledSince"`
+	SampleCount       int     `json:"sampleCount"`
+	MessagesProduced  int64   `json:"messagesProduced"`
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+}
+
+// topicActivitySampler periodically records the summed high water marks of all topics.
+type topicActivitySampler struct {
+	cfg    ConfigTopicActivity
+	logger *zap.Logger
+
+	// fetchHighWaterMarks returns the summed high water marks of all topics, where the topic name is the key,
+	// and the names of all topics that exist, including those whose high water marks could not be fetched.
+	fetchHighWaterMarks func(ctx context.Context) (map[string]int64, map[string]struct{}, error)
+
+	mutex          sync.RWMutex
+	samplesByTopic map[string][]TopicActivitySample
+}
+
+func newTopicActivitySampler(cfg ConfigTopicActivity, logger *zap.Logger, fetchHighWaterMarks func(ctx context.Context) (map[string]int64, map[string]struct{}, error)) *topicActivitySampler {
+	return &topicActivitySampler{
+		cfg:                 cfg,
+		logger:              logger.With(zap.String("source", "topic_activity_sampler")),
+		fetchHighWaterMarks: fetchHighWaterMarks,
+		samplesByTopic:      make(map[string][]TopicActivitySample),
+	}
+}
+
+// Start records the high water marks in the background.
+func (t *topicActivitySampler) Start() {
+	go t.run()
+}
+
+func (t *topicActivitySampler) run() {
+	// Stop sampling when we receive a signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	t.sample()
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			t.logger.Info("stopped topic activity sampler", zap.String("reason", "received signal"))
+			return
+		case <-ticker.C:
+			t.sample()
+		}
+	}
+}
+
+func (t *topicActivitySampler) sample() {
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.Interval)
+	defer cancel()
+
+	highWaterMarks, topicNames, err := t.fetchHighWaterMarks(ctx)
+	if err != nil {
+		t.logger.Warn("failed to sample topic high water marks", zap.Error(err))
+		return
+	}
+	t.record(unixMillis(time.Now()), topicNames, highWaterMarks)
+}
+
+// record adds a sample for each given high water mark. The samples of topics that no longer exist are removed,
+// topics whose high water marks could not be fetched this time keep their samples.
+func (t *topicActivitySampler) record(timestamp int64, topicNames map[string]struct{}, highWaterMarks map[string]int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for topicName := range t.samplesByTopic {
+		if _, exists := topicNames[topicName]; !exists {
+			delete(t.samplesByTopic, topicName)
+		}
+	}
+	for topicName, highWaterMark := range highWaterMarks {
+		samples := append(t.samplesByTopic[topicName], TopicActivitySample{Timestamp: timestamp, HighWaterMark: highWaterMark})
+		if len(samples) > t.cfg.MaxSamples {
+			samples = samples[len(samples)-t.cfg.MaxSamples:]
+		}
+		t.samplesByTopic[topicName] = samples
+	}
+}
+
+// activity returns the producer activity of the given topic. False is returned if less than two samples have
+// been recorded so far.
+func (t *topicActivitySampler) activity(topicName string) (TopicProducerActivity, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return producerActivity(t.samplesByTopic[topicName])
+}
+
+// producerActivity sums the increases of the high water marks. Decreasing high water marks (e.g. because the
+// topic has been recreated) are not counted as produced messages.
+func producerActivity(samples []TopicActivitySample) (TopicProducerActivity, bool) {
+	if len(samples) < 2 {
+		return TopicProducerActivity{}, false
+	}
+
+	activity := TopicProducerActivity{
+		SampledSince: samples[0].Timestamp,
+		SampleCount:  len(samples),
+	}
+	for i := 1; i < len(samples); i++ {
+		if delta := samples[i].HighWaterMark - samples[i-1].HighWaterMark; delta > 0 {
+			activity.MessagesProduced += delta
+		}
+	}
+	durationSeconds := float64(samples[len(samples)-1].Timestamp-samples[0].Timestamp) / 1000
+	if durationSeconds > 0 {
+		activity.MessagesPerSecond = float64(activity.MessagesProduced) / durationSeconds
+	}
+
+	return activity, true
+}
+
+// getSummedHighWaterMarks returns the sum of all partition high water marks for each topic along with the names
+// of all topics in the metadata. Topics with partitions whose high water mark could not be fetched are omitted
+// from the high water marks.
+func (s *Service) getSummedHighWaterMarks(ctx context.Context) (map[string]int64, map[string]struct{}, error) {
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+	topicNames := make(map[string]struct{}, len(metadata.Topics))
+	topicPartitions := make(map[string][]int32, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if topic.Topic == nil {
+			continue
+		}
+		err := kerr.ErrorForCode(topic.ErrorCode)
+		if err == kerr.UnknownTopicOrPartition {
+			continue
+		}
+		// Topics with other errors (e.g. leader not available) still exist
+		topicNames[*topic.Topic] = struct{}{}
+		if err != nil {
+			continue
+		}
+		for _, partition := range topic.Partitions {
+			topicPartitions[*topic.Topic] = append(topicPartitions[*topic.Topic], partition.Partition)
+		}
+	}
+
+	highWaterMarks := make(map[string]int64, len(topicPartitions))
+	for topicName, partitions := range s.kafkaSvc.ListOffsets(ctx, topicPartitions, kafka.TimestampLatest) {
+		sum := int64(0)
+		hasErrors := len(partitions) != len(topicPartitions[topicName])
+		for _, partition := range partitions {
+			if partition.Err != nil {
+				hasErrors = true
+				break
+			}
+			sum += partition.Offset
+		}
+		if !hasErrors {
+			highWaterMarks[topicName] = sum
+		}
+	}
+
+	return highWaterMarks, topicNames, nil
+}
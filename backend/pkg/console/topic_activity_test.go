@@ -0,0 +1,33 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestTopicActivitySamplerRecord(t *testing.T) {
+	sampler := newTopicActivitySampler(ConfigTopicActivity{MaxSamples: 2}, zap.NewNop(), nil)
+
+	sampler.record(1000, map[string]struct{}{"orders": {}, "payments": {}}, map[string]int64{"orders": 10, "payments": 5})
+	sampler.record(2000, map[string]struct{}{"orders": {}, "payments": {}}, map[string]int64{"orders": 20, "payments": 7})
+
+	// Missing high water marks keep the samples of existing topics, deleted topics are removed
+	sampler.record(3000, map[string]struct{}{"orders": {}}, map[string]int64{})
+	assert.Equal(t, []TopicActivitySample{{Timestamp: 1000, HighWaterMark: 10}, {Timestamp: 2000, HighWaterMark: 20}}, sampler.samplesByTopic["orders"])
+	assert.NotContains(t, sampler.samplesByTopic, "payments")
+
+	// Only the latest samples are kept
+	sampler.record(4000, map[string]struct{}{"orders": {}}, map[string]int64{"orders": 25})
+	assert.Equal(t, []TopicActivitySample{{Timestamp: 2000, HighWaterMark: 20}, {Timestamp: 4000, HighWaterMark: 25}}, sampler.samplesByTopic["orders"])
+}
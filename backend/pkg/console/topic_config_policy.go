@@ -0,0 +1,290 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// TopicConfigPolicyReport lists all topic configs that violate the configured topic config policy, along with
+// the patches that would fix them.
+type TopicConfigPolicyReport struct {
+	RuleCount  int                    `json:"ruleCount"`
+	Violations []TopicConfigViolation `json:"violations"`
+
+	// Patches are suggested IncrementalAlterConfigs resources, they can be submitted as resources to the
+	// patch configs endpoint.
+	Patches []TopicConfigPatch `json:"patches"`
+
+	// Errors lists the topics whose configs could not be described, hence they have not been checked.
+	Errors []TopicConfigPolicyError `json:"errors"`
+}
+
+type TopicConfigPolicyError struct {
+	TopicName string `json:"topicName"`
+	Error     string `json:"error"`
+}
+
+type TopicConfigViolation struct {
+	TopicName  string  `json:"topicName"`
+	RuleName   string  `json:"ruleName"`
+	ConfigName string  `json:"configName"`
+	Value      *string `json:"value"`
+	Source     string  `json:"source"`
+	Reason     string  `json:"reason"`
+
+	// SuggestedValue is the value that the config would be set to by the suggested patch.
+	SuggestedValue string `json:"suggestedValue"`
+}
+
+type TopicConfigPatch struct {
+	ResourceType int8                     `json:"resourceType"`
+	ResourceName string                   `json:"resourceName"`
+	Configs      []TopicConfigPatchConfig `json:"configs"`
+}
+
+type TopicConfigPatchConfig struct {
+	Name  string  `json:"name"`
+	Op    int8    `json:"op"`
+	Value *string `json:"value"`
+}
+
+// topicConfigRule is a ConfigTopicConfigRule with compiled topic name expressions.
+type topicConfigRule struct {
+	ConfigTopicConfigRule
+	topicNamesExpr []*regexp.Regexp
+}
+
+// policyTopic is the state of a topic that is checked against the topic config rules.
+type policyTopic struct {
+	TopicName         string
+	ReplicationFactor int16
+	Configs           []*TopicConfigEntry
+}
+
+func compileTopicConfigRules(policy ConfigTopicConfigPolicy) ([]topicConfigRule, error) {
+	rules := make([]topicConfigRule, len(policy.Rules))
+	for i, rule := range policy.Rules {
+		rules[i] = topicConfigRule{ConfigTopicConfigRule: rule, topicNamesExpr: make([]*regexp.Regexp, len(rule.TopicNames))}
+		for j, topicName := range rule.TopicNames {
+			expr, err := regexutil.Compile(topicName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile topic name '%v' of rule '%v': %w", topicName, rule.Name, err)
+			}
+			rules[i].topicNamesExpr[j] = expr
+		}
+	}
+
+	return rules, nil
+}
+
+// appliesTo returns true if the rule shall be checked for the given topic.
+func (r *topicConfigRule) appliesTo(topic policyTopic) bool {
+	if r.ReplicationFactor > 0 && r.ReplicationFactor != topic.ReplicationFactor {
+		return false
+	}
+	if len(r.topicNamesExpr) == 0 {
+		return true
+	}
+	for _, expr := range r.topicNamesExpr {
+		if expr.MatchString(topic.TopicName) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns a description of the violation and the suggested value, or an empty string if the value
+// complies with the rule.
+func (r *topicConfigRule) check(value string) (string, string) {
+	if len(r.AllowedValues) > 0 {
+		if _, found := find(r.AllowedValues, value); !found {
+			return fmt.Sprintf("%v is '%v', but must be one of: %v", r.ConfigName, value, strings.Join(r.AllowedValues, ", ")),
+				r.suggestedValue(r.AllowedValues[0])
+		}
+	}
+	if r.MinValue == nil && r.MaxValue == nil {
+		return "", ""
+	}
+
+	numericValue, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Sprintf("%v is '%v', but must be numeric", r.ConfigName, value), r.suggestedValue("")
+	}
+	isUnlimited := numericValue < 0
+	if r.MinValue != nil && !isUnlimited && numericValue < *r.MinValue {
+		return fmt.Sprintf("%v is %d, but must be at least %d", r.ConfigName, numericValue, *r.MinValue),
+			r.suggestedValue(strconv.FormatInt(*r.MinValue, 10))
+	}
+	if r.MaxValue != nil && (isUnlimited || numericValue > *r.MaxValue) {
+		return fmt.Sprintf("%v is %d, but must be at most %d", r.ConfigName, numericValue, *r.MaxValue),
+			r.suggestedValue(strconv.FormatInt(*r.MaxValue, 10))
+	}
+
+	return "", ""
+}
+
+func (r *topicConfigRule) suggestedValue(fallback string) string {
+	if r.SuggestedValue != "" {
+		return r.SuggestedValue
+	}
+	return fallback
+}
+
+// GetTopicConfigPolicyReport checks the configs of all non internal topics against the configured topic config
+// policy.
+func (s *Service) GetTopicConfigPolicyReport(ctx context.Context) (*TopicConfigPolicyReport, *rest.Error) {
+	report := &TopicConfigPolicyReport{
+		RuleCount:  len(s.topicConfigRules),
+		Violations: make([]TopicConfigViolation, 0),
+		Patches:    make([]TopicConfigPatch, 0),
+		Errors:     make([]TopicConfigPolicyError, 0),
+	}
+	if len(s.topicConfigRules) == 0 {
+		return report, nil
+	}
+
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to get topic metadata from cluster: %v", err.Error()),
+		}
+	}
+	topics := make([]policyTopic, 0, len(metadata.Topics))
+	topicNames := make([]string, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if kerr.ErrorForCode(topic.ErrorCode) != nil || topic.IsInternal {
+			continue
+		}
+		policyTopic := policyTopic{TopicName: *topic.Topic}
+		if len(topic.Partitions) > 0 {
+			policyTopic.ReplicationFactor = int16(len(topic.Partitions[0].Replicas))
+		}
+		topics = append(topics, policyTopic)
+		topicNames = append(topicNames, *topic.Topic)
+	}
+	if len(topics) == 0 {
+		return report, nil
+	}
+
+	// Only describe the configs that are covered by at least one rule
+	configNameSet := make(map[string]struct{})
+	for _, rule := range s.topicConfigRules {
+		configNameSet[rule.ConfigName] = struct{}{}
+	}
+	configNames := make([]string, 0, len(configNameSet))
+	for configName := range configNameSet {
+		configNames = append(configNames, configName)
+	}
+	configs, err := s.GetTopicsConfigs(ctx, topicNames, configNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to describe topic configs: %v", err.Error()),
+		}
+	}
+	checkedTopics := make([]policyTopic, 0, len(topics))
+	for _, topic := range topics {
+		topicConfig, exists := configs[topic.TopicName]
+		switch {
+		case !exists:
+			report.Errors = append(report.Errors, TopicConfigPolicyError{
+				TopicName: topic.TopicName,
+				Error:     "Kafka did not return the configs of this topic",
+			})
+		case topicConfig.Error != nil:
+			report.Errors = append(report.Errors, TopicConfigPolicyError{
+				TopicName: topic.TopicName,
+				Error:     fmt.Sprintf("Failed to describe topic configs: %v", topicConfig.Error.Error()),
+			})
+		default:
+			topic.Configs = topicConfig.ConfigEntries
+			checkedTopics = append(checkedTopics, topic)
+		}
+	}
+	sort.Slice(report.Errors, func(i, j int) bool { return report.Errors[i].TopicName < report.Errors[j].TopicName })
+
+	report.Violations, report.Patches = checkTopicConfigPolicy(s.topicConfigRules, checkedTopics)
+	return report, nil
+}
+
+// checkTopicConfigPolicy returns all violations along with one patch per topic. If multiple rules for the same
+// config are violated, the suggested value of the first violated rule is used.
+func checkTopicConfigPolicy(rules []topicConfigRule, topics []policyTopic) ([]TopicConfigViolation, []TopicConfigPatch) {
+	violations := make([]TopicConfigViolation, 0)
+	patches := make([]TopicConfigPatch, 0)
+
+	sortedTopics := make([]policyTopic, len(topics))
+	copy(sortedTopics, topics)
+	sort.Slice(sortedTopics, func(i, j int) bool { return sortedTopics[i].TopicName < sortedTopics[j].TopicName })
+	for _, topic := range sortedTopics {
+		entriesByName := make(map[string]*TopicConfigEntry, len(topic.Configs))
+		for _, entry := range topic.Configs {
+			entriesByName[entry.Name] = entry
+		}
+
+		patch := TopicConfigPatch{
+			ResourceType: int8(kmsg.ConfigResourceTypeTopic),
+			ResourceName: topic.TopicName,
+			Configs:      make([]TopicConfigPatchConfig, 0),
+		}
+		patchedConfigs := make(map[string]struct{})
+		for i := range rules {
+			rule := &rules[i]
+			entry, exists := entriesByName[rule.ConfigName]
+			if !rule.appliesTo(topic) || !exists || entry.Value == nil {
+				continue
+			}
+			reason, suggestedValue := rule.check(*entry.Value)
+			if reason == "" {
+				continue
+			}
+			violations = append(violations, TopicConfigViolation{
+				TopicName:      topic.TopicName,
+				RuleName:       rule.Name,
+				ConfigName:     rule.ConfigName,
+				Value:          entry.Value,
+				Source:         entry.Source,
+				Reason:         reason,
+				SuggestedValue: suggestedValue,
+			})
+
+			if _, isPatched := patchedConfigs[rule.ConfigName]; isPatched || suggestedValue == "" {
+				continue
+			}
+			patchedConfigs[rule.ConfigName] = struct{}{}
+			value := suggestedValue
+			patch.Configs = append(patch.Configs, TopicConfigPatchConfig{
+				Name:  rule.ConfigName,
+				Op:    int8(kmsg.IncrementalAlterConfigOpSet),
+				Value: &value,
+			})
+		}
+		if len(patch.Configs) > 0 {
+			patches = append(patches, patch)
+		}
+	}
+
+	return violations, patches
+}
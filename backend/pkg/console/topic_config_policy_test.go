@@ -0,0 +1,79 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCheckTopicConfigPolicy(t *testing.T) {
+	maxRetention := int64(2592000000)
+	minISR := int64(2)
+	rules, err := compileTopicConfigRules(ConfigTopicConfigPolicy{Rules: []ConfigTopicConfigRule{
+		{Name: "max-retention", ConfigName: "retention.ms", MaxValue: &maxRetention},
+		{Name: "min-isr", ConfigName: "min.insync.replicas", ReplicationFactor: 3, MinValue: &minISR},
+		{Name: "compacted-changelogs", TopicNames: []string{"/.*-changelog/"}, ConfigName: "cleanup.policy", AllowedValues: []string{"compact", "compact,delete"}},
+	}})
+	require.NoError(t, err)
+
+	retentionWeek := "604800000"
+	retentionInfinite := "-1"
+	minISROne := "1"
+	cleanupDelete := "delete"
+	topics := []policyTopic{
+		{TopicName: "orders", ReplicationFactor: 3, Configs: []*TopicConfigEntry{
+			{Name: "retention.ms", Value: &retentionWeek},
+			{Name: "min.insync.replicas", Value: &minISROne},
+			{Name: "cleanup.policy", Value: &cleanupDelete},
+		}},
+		{TopicName: "app-store-changelog", ReplicationFactor: 1, Configs: []*TopicConfigEntry{
+			{Name: "retention.ms", Value: &retentionInfinite, IsExplicitlySet: true},
+			{Name: "min.insync.replicas", Value: &minISROne},
+			{Name: "cleanup.policy", Value: &cleanupDelete, IsExplicitlySet: true},
+		}},
+	}
+
+	violations, patches := checkTopicConfigPolicy(rules, topics)
+	assert.Equal(t, "orders", topics[0].TopicName, "the passed topics must not be reordered")
+	require.Len(t, violations, 3)
+	assert.Equal(t, "app-store-changelog", violations[0].TopicName)
+	assert.Equal(t, "max-retention", violations[0].RuleName)
+	assert.Equal(t, "2592000000", violations[0].SuggestedValue)
+	assert.Equal(t, "compacted-changelogs", violations[1].RuleName)
+	assert.Equal(t, "compact", violations[1].SuggestedValue)
+	assert.Equal(t, "orders", violations[2].TopicName)
+	assert.Equal(t, "min-isr", violations[2].RuleName)
+
+	require.Len(t, patches, 2)
+	assert.Equal(t, "app-store-changelog", patches[0].ResourceName)
+	assert.Equal(t, int8(kmsg.ConfigResourceTypeTopic), patches[0].ResourceType)
+	require.Len(t, patches[0].Configs, 2)
+	assert.Equal(t, "cleanup.policy", patches[0].Configs[1].Name)
+	assert.Equal(t, int8(kmsg.IncrementalAlterConfigOpSet), patches[0].Configs[1].Op)
+	require.NotNil(t, patches[1].Configs[0].Value)
+	assert.Equal(t, "2", *patches[1].Configs[0].Value)
+}
+
+func TestConfigTopicConfigRule_Validate(t *testing.T) {
+	minValue := int64(5)
+	maxValue := int64(1)
+	rule := ConfigTopicConfigRule{Name: "rule", ConfigName: "retention.ms"}
+	assert.Error(t, rule.Validate())
+
+	rule.MinValue = &minValue
+	assert.NoError(t, rule.Validate())
+
+	rule.MaxValue = &maxValue
+	assert.Error(t, rule.Validate())
+}
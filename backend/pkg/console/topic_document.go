@@ -0,0 +1,520 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	TopicImportActionCreate        = "create"
+	TopicImportActionAddPartitions = "addPartitions"
+	TopicImportActionAlterConfigs  = "alterConfigs"
+	TopicImportActionDelete        = "delete"
+)
+
+// TopicDocument is a declarative representation of the desired topics. It can be stored as YAML or JSON in git
+// and imported into a cluster.
+type TopicDocument struct {
+	Topics []DesiredTopic `json:"topics" yaml:"topics"`
+}
+
+// DesiredTopic describes the desired state of a single topic.
+type DesiredTopic struct {
+	TopicName string `json:"topicName" yaml:"topicName"`
+
+	// PartitionCount and ReplicationFactor are not managed if they are 0 (or -1), new topics use the broker
+	// defaults in that case.
+	PartitionCount    int32 `json:"partitionCount" yaml:"partitionCount"`
+	ReplicationFactor int16 `json:"replicationFactor" yaml:"replicationFactor"`
+
+	// Configs are the desired topic configs. If configs are set (even if empty), all explicitly set configs that
+	// are not listed will be reset to their defaults. If configs are not set at all, the configs are not managed.
+	Configs map[string]string `json:"configs" yaml:"configs"`
+}
+
+// TopicImportPlan contains the changes that are required to bring the cluster's topics in line with a
+// TopicDocument.
+type TopicImportPlan struct {
+	ToCreate        []DesiredTopic            `json:"toCreate"`
+	ToAddPartitions []TopicImportPartitions   `json:"toAddPartitions"`
+	ToAlterConfigs  []TopicImportConfigs      `json:"toAlterConfigs"`
+	ToDelete        []string                  `json:"toDelete"`
+	Errors          []TopicImportActionResult `json:"errors"`
+	UnchangedCount  int                       `json:"unchangedCount"`
+
+	// ConfirmationToken is set if the plan deletes topics. It is required to apply the plan and only valid as
+	// long as the same topics would be deleted.
+	ConfirmationToken string     `json:"confirmationToken,omitempty"`
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty"`
+}
+
+type TopicImportPartitions struct {
+	TopicName             string `json:"topicName"`
+	CurrentPartitionCount int32  `json:"currentPartitionCount"`
+	PartitionCount        int32  `json:"partitionCount"`
+}
+
+type TopicImportConfigs struct {
+	TopicName string                    `json:"topicName"`
+	Configs   []TopicImportConfigChange `json:"configs"`
+}
+
+// TopicImportConfigChange is a single config change. Value is nil if the config shall be reset to its default.
+type TopicImportConfigChange struct {
+	Name         string  `json:"name"`
+	CurrentValue *string `json:"currentValue"`
+	Value        *string `json:"value"`
+}
+
+// TopicImportActionResult is the outcome of a single planned action. Error is empty if the action succeeded.
+type TopicImportActionResult struct {
+	TopicName string `json:"topicName"`
+	Action    string `json:"action"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TopicImportResult is the result of applying a TopicImportPlan.
+type TopicImportResult struct {
+	Plan    *TopicImportPlan          `json:"plan"`
+	Results []TopicImportActionResult `json:"results"`
+}
+
+// currentTopicState is the state of an existing topic that is compared against the desired topic.
+type currentTopicState struct {
+	PartitionCount    int32
+	ReplicationFactor int16
+	IsInternal        bool
+	Configs           []*TopicConfigEntry
+}
+
+// Validate the document.
+func (d *TopicDocument) Validate() error {
+	if len(d.Topics) == 0 {
+		return fmt.Errorf("topic document must contain at least one topic")
+	}
+	topicNames := make(map[string]struct{}, len(d.Topics))
+	for i, topic := range d.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic at index %d has no topic name", i)
+		}
+		if _, exists := topicNames[topic.TopicName]; exists {
+			return fmt.Errorf("topic '%v' is defined more than once", topic.TopicName)
+		}
+		topicNames[topic.TopicName] = struct{}{}
+		if topic.PartitionCount < -1 {
+			return fmt.Errorf("partition count of topic '%v' must be -1 (not managed) or more", topic.TopicName)
+		}
+		if topic.ReplicationFactor < -1 {
+			return fmt.Errorf("replication factor of topic '%v' must be -1 (not managed) or more", topic.TopicName)
+		}
+	}
+
+	return nil
+}
+
+// ParseTopicDocument parses a YAML or JSON topic document. Unknown fields are rejected, so that typos do not
+// silently result in unmanaged settings.
+func ParseTopicDocument(data []byte) (TopicDocument, error) {
+	var doc TopicDocument
+
+	// JSON is a subset of YAML, hence we can parse both formats with the YAML decoder
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&doc)
+	if err != nil && err != io.EOF {
+		return doc, err
+	}
+
+	return doc, nil
+}
+
+// GetTopicDocumentFromGit returns the desired topics document from the configured git repository.
+func (s *Service) GetTopicDocumentFromGit() (*TopicDocument, *rest.Error) {
+	if s.topicManagementGitSvc == nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("git is not configured for topic management"),
+			Status:   http.StatusBadRequest,
+			Message:  "No git repository is configured for the topic management",
+			IsSilent: true,
+		}
+	}
+
+	for _, file := range s.topicManagementGitSvc.GetFilesByFilename() {
+		if file.Filename != s.topicManagementCfg.Filename {
+			continue
+		}
+		doc, err := ParseTopicDocument(file.Payload)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusUnprocessableEntity,
+				Message:  fmt.Sprintf("Failed to parse topic document '%v' from git: %v", file.Path, err.Error()),
+				IsSilent: false,
+			}
+		}
+		return &doc, nil
+	}
+
+	return nil, &rest.Error{
+		Err:      fmt.Errorf("topic document '%v' not found in git repository", s.topicManagementCfg.Filename),
+		Status:   http.StatusNotFound,
+		Message:  fmt.Sprintf("Topic document '%v' could not be found in the git repository", s.topicManagementCfg.Filename),
+		IsSilent: false,
+	}
+}
+
+// PlanTopicImport compares the given desired topics with the cluster's current topics. Missing topics will be
+// created, partitions added and configs altered. If deleteUnmanaged is true, all topics that are not part of the
+// document will be deleted, except internal topics and topics starting with an underscore.
+func (s *Service) PlanTopicImport(ctx context.Context, doc TopicDocument, deleteUnmanaged bool) (*TopicImportPlan, *rest.Error) {
+	err := doc.Validate()
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Topic document is invalid: %v", err.Error()),
+			IsSilent: true,
+		}
+	}
+
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get topic metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+	current := make(map[string]*currentTopicState, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to get metadata for topic '%v': %v", *topic.Topic, err.Error()),
+				IsSilent: false,
+			}
+		}
+		state := &currentTopicState{PartitionCount: int32(len(topic.Partitions)), IsInternal: topic.IsInternal}
+		if len(topic.Partitions) > 0 {
+			state.ReplicationFactor = int16(len(topic.Partitions[0].Replicas))
+		}
+		current[*topic.Topic] = state
+	}
+
+	// Describe configs of all desired topics that exist already
+	configTopicNames := make([]string, 0)
+	for _, topic := range doc.Topics {
+		if _, exists := current[topic.TopicName]; exists && topic.Configs != nil {
+			configTopicNames = append(configTopicNames, topic.TopicName)
+		}
+	}
+	if len(configTopicNames) > 0 {
+		configs, err := s.GetTopicsConfigs(ctx, configTopicNames, nil)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to describe topic configs: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+		for _, topicName := range configTopicNames {
+			topicConfig, exists := configs[topicName]
+			if !exists || topicConfig.Error != nil {
+				return nil, &rest.Error{
+					Err:      fmt.Errorf("failed to describe configs of topic '%v'", topicName),
+					Status:   http.StatusServiceUnavailable,
+					Message:  fmt.Sprintf("Failed to describe configs of topic '%v'", topicName),
+					IsSilent: false,
+				}
+			}
+			current[topicName].Configs = topicConfig.ConfigEntries
+		}
+	}
+
+	return planTopicImport(doc, current, deleteUnmanaged), nil
+}
+
+func planTopicImport(doc TopicDocument, current map[string]*currentTopicState, deleteUnmanaged bool) *TopicImportPlan {
+	plan := &TopicImportPlan{
+		ToCreate:        make([]DesiredTopic, 0),
+		ToAddPartitions: make([]TopicImportPartitions, 0),
+		ToAlterConfigs:  make([]TopicImportConfigs, 0),
+		ToDelete:        make([]string, 0),
+		Errors:          make([]TopicImportActionResult, 0),
+	}
+
+	desiredTopicNames := make(map[string]struct{}, len(doc.Topics))
+	for _, desired := range doc.Topics {
+		desiredTopicNames[desired.TopicName] = struct{}{}
+		state, exists := current[desired.TopicName]
+		if !exists {
+			plan.ToCreate = append(plan.ToCreate, desired)
+			continue
+		}
+
+		isUnchanged := true
+		if desired.ReplicationFactor > 0 && desired.ReplicationFactor != state.ReplicationFactor {
+			isUnchanged = false
+			plan.Errors = append(plan.Errors, TopicImportActionResult{
+				TopicName: desired.TopicName,
+				Error: fmt.Sprintf("replication factor can not be changed from %d to %d, partitions must be reassigned instead",
+					state.ReplicationFactor, desired.ReplicationFactor),
+			})
+		}
+		switch {
+		case desired.PartitionCount > 0 && desired.PartitionCount < state.PartitionCount:
+			isUnchanged = false
+			plan.Errors = append(plan.Errors, TopicImportActionResult{
+				TopicName: desired.TopicName,
+				Action:    TopicImportActionAddPartitions,
+				Error:     fmt.Sprintf("partition count can not be decreased from %d to %d", state.PartitionCount, desired.PartitionCount),
+			})
+		case desired.PartitionCount > state.PartitionCount:
+			isUnchanged = false
+			plan.ToAddPartitions = append(plan.ToAddPartitions, TopicImportPartitions{
+				TopicName:             desired.TopicName,
+				CurrentPartitionCount: state.PartitionCount,
+				PartitionCount:        desired.PartitionCount,
+			})
+		}
+
+		if desired.Configs != nil {
+			changes := diffTopicConfigs(desired.Configs, state.Configs)
+			if len(changes) > 0 {
+				isUnchanged = false
+				plan.ToAlterConfigs = append(plan.ToAlterConfigs, TopicImportConfigs{TopicName: desired.TopicName, Configs: changes})
+			}
+		}
+
+		if isUnchanged {
+			plan.UnchangedCount++
+		}
+	}
+
+	if deleteUnmanaged {
+		for topicName, state := range current {
+			if _, isDesired := desiredTopicNames[topicName]; isDesired || state.IsInternal || strings.HasPrefix(topicName, "_") {
+				continue
+			}
+			plan.ToDelete = append(plan.ToDelete, topicName)
+		}
+	}
+
+	sort.Slice(plan.ToCreate, func(i, j int) bool { return plan.ToCreate[i].TopicName < plan.ToCreate[j].TopicName })
+	sort.Strings(plan.ToDelete)
+
+	return plan
+}
+
+// diffTopicConfigs returns the config changes that are required so that the topic's explicitly set configs
+// match the desired configs. Desired configs whose value equals the current (default) value are not changed.
+func diffTopicConfigs(desired map[string]string, current []*TopicConfigEntry) []TopicImportConfigChange {
+	currentByName := make(map[string]*TopicConfigEntry, len(current))
+	for _, entry := range current {
+		currentByName[entry.Name] = entry
+	}
+
+	changes := make([]TopicImportConfigChange, 0)
+	for name, value := range desired {
+		entry, exists := currentByName[name]
+		if exists && entry.Value != nil && *entry.Value == value {
+			continue
+		}
+		v := value
+		change := TopicImportConfigChange{Name: name, Value: &v}
+		if exists {
+			change.CurrentValue = entry.Value
+		}
+		changes = append(changes, change)
+	}
+	for _, entry := range current {
+		if !entry.IsExplicitlySet {
+			continue
+		}
+		if _, isDesired := desired[entry.Name]; isDesired {
+			continue
+		}
+		changes = append(changes, TopicImportConfigChange{Name: entry.Name, CurrentValue: entry.Value})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes
+}
+
+// AddTopicImportConfirmation sets a confirmation token on the plan if it deletes topics. The token must be passed
+// when the plan is applied.
+func (s *Service) AddTopicImportConfirmation(plan *TopicImportPlan) *rest.Error {
+	if len(plan.ToDelete) == 0 {
+		return nil
+	}
+
+	token, expiresAt, err := s.topicDeletionConfirmations.add(plan.ToDelete)
+	if err != nil {
+		return &rest.Error{
+			Err:     err,
+			Status:  http.StatusInternalServerError,
+			Message: fmt.Sprintf("Failed to create confirmation token: %v", err.Error()),
+		}
+	}
+	plan.ConfirmationToken = token
+	plan.ExpiresAt = &expiresAt
+
+	return nil
+}
+
+// ConfirmTopicImportDeletions checks that the given token has been issued for a plan that deletes exactly the
+// same topics as the given plan. Plans that do not delete any topics do not require a token.
+func (s *Service) ConfirmTopicImportDeletions(plan *TopicImportPlan, token string) *rest.Error {
+	if len(plan.ToDelete) == 0 {
+		return nil
+	}
+	if token == "" {
+		return &rest.Error{
+			Err:      fmt.Errorf("topic import deletes topics but no confirmation token was passed"),
+			Status:   http.StatusBadRequest,
+			Message:  "The topic import deletes topics, please pass the confirmation token of the plan",
+			IsSilent: true,
+		}
+	}
+
+	confirmedTopicNames, exists := s.topicDeletionConfirmations.consume(token)
+	if !exists {
+		return &rest.Error{
+			Err:      fmt.Errorf("topic import confirmation token is unknown or expired"),
+			Status:   http.StatusBadRequest,
+			Message:  "The confirmation token is unknown, has already been used or has expired. Please plan the import again",
+			IsSilent: true,
+		}
+	}
+	if !isSameTopicNameSet(confirmedTopicNames, plan.ToDelete) {
+		return &rest.Error{
+			Err:      fmt.Errorf("topics to delete have changed since the topic import was planned"),
+			Status:   http.StatusConflict,
+			Message:  "The topics to delete have changed since the import was planned. Please plan the import again",
+			IsSilent: true,
+		}
+	}
+
+	return nil
+}
+
+// isSameTopicNameSet returns true if both slices contain the same topic names, regardless of their order.
+func isSameTopicNameSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := make(map[string]struct{}, len(a))
+	for _, name := range a {
+		names[name] = struct{}{}
+	}
+	for _, name := range b {
+		if _, exists := names[name]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ApplyTopicImportPlan creates topics, adds partitions, alters configs and deletes topics as planned. Every
+// action is executed even if a previous action has failed, the outcome of each action is returned.
+func (s *Service) ApplyTopicImportPlan(ctx context.Context, plan *TopicImportPlan) *TopicImportResult {
+	result := &TopicImportResult{Plan: plan, Results: make([]TopicImportActionResult, 0)}
+	addResult := func(topicName string, action string, restErr *rest.Error) {
+		actionResult := TopicImportActionResult{TopicName: topicName, Action: action}
+		if restErr != nil {
+			actionResult.Error = restErr.Message
+		}
+		result.Results = append(result.Results, actionResult)
+	}
+
+	for _, topic := range plan.ToCreate {
+		createTopicReq := kmsg.NewCreateTopicsRequestTopic()
+		createTopicReq.Topic = topic.TopicName
+		createTopicReq.NumPartitions = topic.PartitionCount
+		if createTopicReq.NumPartitions == 0 {
+			createTopicReq.NumPartitions = -1
+		}
+		createTopicReq.ReplicationFactor = topic.ReplicationFactor
+		if createTopicReq.ReplicationFactor == 0 {
+			createTopicReq.ReplicationFactor = -1
+		}
+		configNames := make([]string, 0, len(topic.Configs))
+		for name := range topic.Configs {
+			configNames = append(configNames, name)
+		}
+		sort.Strings(configNames)
+		for _, name := range configNames {
+			value := topic.Configs[name]
+			cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+			cfg.Name = name
+			cfg.Value = &value
+			createTopicReq.Configs = append(createTopicReq.Configs, cfg)
+		}
+		_, restErr := s.CreateTopic(ctx, createTopicReq)
+		addResult(topic.TopicName, TopicImportActionCreate, restErr)
+	}
+
+	for _, topic := range plan.ToAddPartitions {
+		_, restErr := s.CreatePartitions(ctx, topic.TopicName, topic.PartitionCount, nil, false)
+		addResult(topic.TopicName, TopicImportActionAddPartitions, restErr)
+	}
+
+	if len(plan.ToAlterConfigs) > 0 {
+		resources := make([]kmsg.IncrementalAlterConfigsRequestResource, len(plan.ToAlterConfigs))
+		for i, topic := range plan.ToAlterConfigs {
+			configs := make([]kmsg.IncrementalAlterConfigsRequestResourceConfig, len(topic.Configs))
+			for j, change := range topic.Configs {
+				if change.Value == nil {
+					configs[j] = newAlterConfig(change.Name, kmsg.IncrementalAlterConfigOpDelete, "")
+					continue
+				}
+				configs[j] = newAlterConfig(change.Name, kmsg.IncrementalAlterConfigOpSet, *change.Value)
+			}
+			resources[i] = newAlterConfigsResource(kmsg.ConfigResourceTypeTopic, topic.TopicName, configs...)
+		}
+		patchedConfigs, restErr := s.IncrementalAlterConfigs(ctx, resources)
+		if restErr != nil {
+			for _, topic := range plan.ToAlterConfigs {
+				addResult(topic.TopicName, TopicImportActionAlterConfigs, restErr)
+			}
+		}
+		for _, patched := range patchedConfigs {
+			result.Results = append(result.Results, TopicImportActionResult{
+				TopicName: patched.ResourceName,
+				Action:    TopicImportActionAlterConfigs,
+				Error:     patched.Error,
+			})
+		}
+	}
+
+	for _, topicName := range plan.ToDelete {
+		restErr := s.DeleteTopic(ctx, topicName)
+		addResult(topicName, TopicImportActionDelete, restErr)
+	}
+
+	return result
+}
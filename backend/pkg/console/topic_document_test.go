@@ -0,0 +1,156 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanTopicImport(t *testing.T) {
+	retentionMs := "3600000"
+	cleanupPolicy := "delete"
+	maxMessageBytes := "2000000"
+	doc := TopicDocument{Topics: []DesiredTopic{
+		{TopicName: "orders", PartitionCount: 12, Configs: map[string]string{"retention.ms": "86400000", "cleanup.policy": "delete"}},
+		{TopicName: "payments", PartitionCount: 6, ReplicationFactor: 3},
+		{TopicName: "invoices", PartitionCount: 2},
+		{TopicName: "customers", PartitionCount: 3, ReplicationFactor: 3},
+		{TopicName: "new-topic"},
+	}}
+	current := map[string]*currentTopicState{
+		"orders": {PartitionCount: 6, ReplicationFactor: 3, Configs: []*TopicConfigEntry{
+			{Name: "retention.ms", Value: &retentionMs, IsExplicitlySet: true},
+			{Name: "cleanup.policy", Value: &cleanupPolicy},
+			{Name: "max.message.bytes", Value: &maxMessageBytes, IsExplicitlySet: true},
+		}},
+		"payments":           {PartitionCount: 6, ReplicationFactor: 3},
+		"invoices":           {PartitionCount: 4, ReplicationFactor: 3},
+		"customers":          {PartitionCount: 3, ReplicationFactor: 1},
+		"unmanaged":          {PartitionCount: 1, ReplicationFactor: 1},
+		"_schemas":           {PartitionCount: 1, ReplicationFactor: 3},
+		"__consumer_offsets": {PartitionCount: 50, ReplicationFactor: 3, IsInternal: true},
+	}
+
+	plan := planTopicImport(doc, current, false)
+	require.Len(t, plan.ToCreate, 1)
+	assert.Equal(t, "new-topic", plan.ToCreate[0].TopicName)
+	assert.Equal(t, []TopicImportPartitions{{TopicName: "orders", CurrentPartitionCount: 6, PartitionCount: 12}}, plan.ToAddPartitions)
+	assert.Empty(t, plan.ToDelete)
+	assert.Equal(t, 1, plan.UnchangedCount)
+
+	// cleanup.policy is already the effective value, max.message.bytes is not desired and must be reset
+	require.Len(t, plan.ToAlterConfigs, 1)
+	changes := plan.ToAlterConfigs[0].Configs
+	require.Len(t, changes, 2)
+	assert.Equal(t, "max.message.bytes", changes[0].Name)
+	assert.Nil(t, changes[0].Value)
+	assert.Equal(t, "retention.ms", changes[1].Name)
+	require.NotNil(t, changes[1].Value)
+	assert.Equal(t, "86400000", *changes[1].Value)
+
+	// Decreasing partitions and changing the replication factor is not possible
+	require.Len(t, plan.Errors, 2)
+	errorTopics := []string{plan.Errors[0].TopicName, plan.Errors[1].TopicName}
+	assert.ElementsMatch(t, []string{"invoices", "customers"}, errorTopics)
+
+	// Internal topics and topics with a leading underscore are never deleted
+	plan = planTopicImport(doc, current, true)
+	assert.Equal(t, []string{"unmanaged"}, plan.ToDelete)
+}
+
+func TestTopicDocumentValidate(t *testing.T) {
+	tt := []struct {
+		name    string
+		doc     TopicDocument
+		wantErr bool
+	}{
+		{"valid", TopicDocument{Topics: []DesiredTopic{{TopicName: "orders", PartitionCount: 3}, {TopicName: "payments"}}}, false},
+		{"no topics", TopicDocument{}, true},
+		{"duplicate topic", TopicDocument{Topics: []DesiredTopic{{TopicName: "orders"}, {TopicName: "orders"}}}, true},
+		{"missing topic name", TopicDocument{Topics: []DesiredTopic{{TopicName: ""}}}, true},
+	}
+
+	for _, test := range tt {
+		err := test.doc.Validate()
+		if test.wantErr {
+			assert.Error(t, err, test.name)
+		} else {
+			assert.NoError(t, err, test.name)
+		}
+	}
+}
+
+func TestParseTopicDocument(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		want    TopicDocument
+		wantErr bool
+	}{
+		{
+			name:  "yaml",
+			input: "topics:\n  - topicName: orders\n    partitionCount: 6\n",
+			want:  TopicDocument{Topics: []DesiredTopic{{TopicName: "orders", PartitionCount: 6}}},
+		},
+		{
+			name:  "json",
+			input: `{"topics": [{"topicName": "orders", "configs": {"retention.ms": "86400000"}}]}`,
+			want:  TopicDocument{Topics: []DesiredTopic{{TopicName: "orders", Configs: map[string]string{"retention.ms": "86400000"}}}},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  TopicDocument{},
+		},
+		{
+			name:    "unknown field",
+			input:   "topics:\n  - topicName: orders\n    partitions: 6\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tt {
+		doc, err := ParseTopicDocument([]byte(test.input))
+		if test.wantErr {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.want, doc, test.name)
+	}
+}
+
+func TestConfirmTopicImportDeletions(t *testing.T) {
+	svc := &Service{topicDeletionConfirmations: newTopicDeletionConfirmations(time.Minute)}
+
+	plan := &TopicImportPlan{ToDelete: []string{"orders", "payments"}}
+	require.Nil(t, svc.AddTopicImportConfirmation(plan))
+	require.NotEmpty(t, plan.ConfirmationToken)
+	require.NotNil(t, plan.ExpiresAt)
+
+	changedPlan := &TopicImportPlan{ToDelete: []string{"orders", "payments", "invoices"}}
+	restErr := svc.ConfirmTopicImportDeletions(changedPlan, plan.ConfirmationToken)
+	require.NotNil(t, restErr)
+	assert.Equal(t, http.StatusConflict, restErr.Status)
+
+	// Tokens can only be used once
+	require.Nil(t, svc.AddTopicImportConfirmation(plan))
+	reorderedPlan := &TopicImportPlan{ToDelete: []string{"payments", "orders"}}
+	assert.Nil(t, svc.ConfirmTopicImportDeletions(reorderedPlan, plan.ConfirmationToken))
+	assert.NotNil(t, svc.ConfirmTopicImportDeletions(reorderedPlan, plan.ConfirmationToken))
+
+	assert.NotNil(t, svc.ConfirmTopicImportDeletions(reorderedPlan, ""))
+	assert.Nil(t, svc.ConfirmTopicImportDeletions(&TopicImportPlan{}, ""))
+}
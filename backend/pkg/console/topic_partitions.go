@@ -201,6 +201,12 @@ func (s *Service) getTopicPartitionMetadata(ctx context.Context, topicNames []st
 		}
 	}
 
+	return s.convertTopicPartitionMetadata(metadata), nil
+}
+
+// convertTopicPartitionMetadata converts a metadata response into topic details by topic name. Errors on the topic
+// or partition level are propagated into the respective details.
+func (s *Service) convertTopicPartitionMetadata(metadata *kmsg.MetadataResponse) map[string]TopicDetails {
 	overviewByTopic := make(map[string]TopicDetails)
 	for _, topic := range metadata.Topics {
 		topicName := *topic.Topic
@@ -252,7 +258,7 @@ func (s *Service) getTopicPartitionMetadata(ctx context.Context, topicNames []st
 		overviewByTopic[topicName] = topicOverview
 	}
 
-	return overviewByTopic, nil
+	return overviewByTopic
 }
 
 func (s *Service) describePartitionLogDirs(ctx context.Context, topicMetadata map[string]TopicDetails) map[string]map[int32][]TopicPartitionLogDirs {
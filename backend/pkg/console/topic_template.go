@@ -0,0 +1,90 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// GetTopicTemplates returns all configured topic templates sorted by name.
+func (s *Service) GetTopicTemplates() []ConfigTopicTemplate {
+	templates := make([]ConfigTopicTemplate, 0, len(s.topicTemplates))
+	for _, template := range s.topicTemplates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	return templates
+}
+
+// ApplyTopicTemplate fills all settings which are not set in the given create topic request with the settings of
+// the topic template with the given name. A partition count or replication factor of 0 is considered as not set.
+func (s *Service) ApplyTopicTemplate(templateName string, topic kmsg.CreateTopicsRequestTopic) (kmsg.CreateTopicsRequestTopic, *rest.Error) {
+	template, exists := s.topicTemplates[templateName]
+	if !exists {
+		return topic, &rest.Error{
+			Err:      fmt.Errorf("topic template '%v' does not exist", templateName),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Topic template '%v' does not exist", templateName),
+			IsSilent: false,
+		}
+	}
+
+	return applyTopicTemplate(template, topic), nil
+}
+
+func applyTopicTemplate(template ConfigTopicTemplate, topic kmsg.CreateTopicsRequestTopic) kmsg.CreateTopicsRequestTopic {
+	if topic.NumPartitions == 0 {
+		topic.NumPartitions = template.PartitionCount
+	}
+	if topic.NumPartitions == 0 {
+		topic.NumPartitions = -1
+	}
+	if topic.ReplicationFactor == 0 {
+		topic.ReplicationFactor = template.ReplicationFactor
+	}
+	if topic.ReplicationFactor == 0 {
+		topic.ReplicationFactor = -1
+	}
+
+	// Configs in the request take precedence over the template's configs
+	configNames := make([]string, 0, len(template.Configs))
+	for name := range template.Configs {
+		configNames = append(configNames, name)
+	}
+	sort.Strings(configNames)
+
+	configs := make([]kmsg.CreateTopicsRequestTopicConfig, 0, len(template.Configs)+len(topic.Configs))
+	for _, name := range configNames {
+		isOverridden := false
+		for _, cfg := range topic.Configs {
+			if cfg.Name == name {
+				isOverridden = true
+				break
+			}
+		}
+		if isOverridden {
+			continue
+		}
+		value := template.Configs[name]
+		cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+		cfg.Name = name
+		cfg.Value = &value
+		configs = append(configs, cfg)
+	}
+	topic.Configs = append(configs, topic.Configs...)
+
+	return topic
+}
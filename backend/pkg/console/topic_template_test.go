@@ -0,0 +1,42 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestApplyTopicTemplate(t *testing.T) {
+	template := ConfigTopicTemplate{
+		Name:           "compacted",
+		PartitionCount: 6,
+		Configs:        map[string]string{"cleanup.policy": "compact", "min.insync.replicas": "2"},
+	}
+	value := "1"
+	cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+	cfg.Name = "min.insync.replicas"
+	cfg.Value = &value
+	topic := kmsg.NewCreateTopicsRequestTopic()
+	topic.Topic = "customers"
+	topic.Configs = []kmsg.CreateTopicsRequestTopicConfig{cfg}
+
+	topic = applyTopicTemplate(template, topic)
+	assert.Equal(t, int32(6), topic.NumPartitions)
+	assert.Equal(t, int16(-1), topic.ReplicationFactor, "broker default should be used if neither request nor template set it")
+
+	configs := make(map[string]string)
+	for _, cfg := range topic.Configs {
+		configs[cfg.Name] = *cfg.Value
+	}
+	assert.Equal(t, map[string]string{"cleanup.policy": "compact", "min.insync.replicas": "1"}, configs)
+}
@@ -0,0 +1,207 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+// CommittedConsumerGroup is a consumer group that has committed offsets on a topic.
+type CommittedConsumerGroup struct {
+	GroupID string `json:"groupId"`
+	State   string `json:"state"`
+
+	// MemberCount is -1 if the group could not be described.
+	MemberCount int `json:"memberCount"`
+}
+
+// getConsumerGroupsByTopic returns the consumer groups that have committed offsets on each of the given topics,
+// along with their state and member count.
+func (s *Service) getConsumerGroupsByTopic(ctx context.Context, topicNames []string) (map[string][]CommittedConsumerGroup, error) {
+	groupsByTopic, err := s.getCommittedGroupsByTopic(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDSet := make(map[string]struct{})
+	for _, topicName := range topicNames {
+		for _, groupID := range groupsByTopic[topicName] {
+			groupIDSet[groupID] = struct{}{}
+		}
+	}
+	describedByID := make(map[string]kmsg.DescribeGroupsResponseGroup)
+	if len(groupIDSet) > 0 {
+		groupIDs := make([]string, 0, len(groupIDSet))
+		for groupID := range groupIDSet {
+			groupIDs = append(groupIDs, groupID)
+		}
+		describedGroups, err := s.kafkaSvc.DescribeConsumerGroups(ctx, groupIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe consumer groups: %w", err)
+		}
+		for _, group := range describedGroups.GetDescribedGroups() {
+			describedByID[group.Group] = group
+		}
+	}
+
+	consumerGroupsByTopic := make(map[string][]CommittedConsumerGroup, len(topicNames))
+	for _, topicName := range topicNames {
+		groups := make([]CommittedConsumerGroup, 0, len(groupsByTopic[topicName]))
+		for _, groupID := range groupsByTopic[topicName] {
+			group := CommittedConsumerGroup{GroupID: groupID, State: "Unknown", MemberCount: -1}
+			if described, exists := describedByID[groupID]; exists {
+				group.State = described.State
+				group.MemberCount = len(described.Members)
+			}
+			groups = append(groups, group)
+		}
+		consumerGroupsByTopic[topicName] = groups
+	}
+
+	return consumerGroupsByTopic, nil
+}
+
+// getCommittedGroupsByTopic returns the sorted IDs of all consumer groups that have at least one committed
+// offset, grouped by topic name.
+func (s *Service) getCommittedGroupsByTopic(ctx context.Context) (map[string][]string, error) {
+	groups, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	offsetsByGroup, err := s.kafkaSvc.ListConsumerGroupOffsetsBulk(ctx, groups.GetGroupIDs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer group offsets: %w", err)
+	}
+
+	groupsByTopic := make(map[string][]string)
+	for groupID, offsets := range offsetsByGroup {
+		if err := kerr.ErrorForCode(offsets.ErrorCode); err != nil {
+			s.logger.Warn("failed to fetch offsets of consumer group", zap.String("group", groupID), zap.Error(err))
+			continue
+		}
+		for _, topic := range offsets.Topics {
+			for _, partition := range topic.Partitions {
+				if partition.Offset >= 0 {
+					groupsByTopic[topic.Topic] = append(groupsByTopic[topic.Topic], groupID)
+					break
+				}
+			}
+		}
+	}
+	for _, groupIDs := range groupsByTopic {
+		sort.Strings(groupIDs)
+	}
+
+	return groupsByTopic, nil
+}
+
+// Statuses of the latest message timestamp lookup of a topic.
+const (
+	// LatestTimestampStatusOK means that the latest message of all partitions has been consumed.
+	LatestTimestampStatusOK = "ok"
+	// LatestTimestampStatusEmpty means that the topic does not contain any records.
+	LatestTimestampStatusEmpty = "empty"
+	// LatestTimestampStatusNoDataRecords means that the latest records of all partitions are control records.
+	LatestTimestampStatusNoDataRecords = "noDataRecords"
+	// LatestTimestampStatusTimedOut means that not all partitions could be consumed in time. The timestamp is
+	// the latest one of all consumed partitions, if any.
+	LatestTimestampStatusTimedOut = "timedOut"
+	// LatestTimestampStatusFailed means that the watermarks or the latest records could not be fetched.
+	LatestTimestampStatusFailed = "failed"
+)
+
+// latestMessageTimestamp is the timestamp (in ms) of the latest message of a topic along with the status of the
+// lookup. Timestamp is -1 if no message has been consumed.
+type latestMessageTimestamp struct {
+	Timestamp int64
+	Status    string
+}
+
+// latestMessageTimestampsTimeout is the maximum duration for fetching the latest messages of all topics.
+const latestMessageTimestampsTimeout = 15 * time.Second
+
+// getLatestMessageTimestamps returns the timestamp of the latest message of each topic in the given marks.
+func (s *Service) getLatestMessageTimestamps(ctx context.Context, marks map[string]map[int32]*kafka.PartitionMarks) map[string]latestMessageTimestamp {
+	fetchCtx, cancel := context.WithTimeout(ctx, latestMessageTimestampsTimeout)
+	defer cancel()
+
+	result := make(map[string]latestMessageTimestamp, len(marks))
+	latestRecords, err := s.kafkaSvc.FetchLatestRecords(fetchCtx, marks)
+	if err != nil {
+		s.logger.Warn("failed to fetch latest records of topics", zap.Error(err))
+		return result
+	}
+	for topicName, topicMarks := range marks {
+		latest := latestRecords[topicName]
+		if latest.Error != nil {
+			s.logger.Warn("failed to fetch latest records of topic", zap.String("topic", topicName), zap.Error(latest.Error))
+		}
+		result[topicName] = newLatestMessageTimestamp(topicMarks, latest)
+	}
+
+	return result
+}
+
+// newLatestMessageTimestamp returns the latest timestamp of the given records and the status of the lookup.
+func newLatestMessageTimestamp(marks map[int32]*kafka.PartitionMarks, latestRecords *kafka.LatestRecords) latestMessageTimestamp {
+	latest := latestMessageTimestamp{Timestamp: -1}
+	for _, record := range latestRecords.Records {
+		timestamp := record.Timestamp.UnixNano() / int64(time.Millisecond)
+		if timestamp > latest.Timestamp {
+			latest.Timestamp = timestamp
+		}
+	}
+
+	hasMarkErrors := false
+	hasRecords := false
+	for _, mark := range marks {
+		if mark.Error != nil {
+			hasMarkErrors = true
+			continue
+		}
+		if mark.High > mark.Low {
+			hasRecords = true
+		}
+	}
+
+	switch {
+	case latestRecords.Error != nil || (hasMarkErrors && len(latestRecords.Records) == 0):
+		latest.Status = LatestTimestampStatusFailed
+	case !latestRecords.IsComplete:
+		latest.Status = LatestTimestampStatusTimedOut
+	case !hasRecords && !hasMarkErrors:
+		latest.Status = LatestTimestampStatusEmpty
+	case len(latestRecords.Records) == 0:
+		latest.Status = LatestTimestampStatusNoDataRecords
+	default:
+		latest.Status = LatestTimestampStatusOK
+	}
+
+	return latest
+}
+
+// latestTimestampOf returns the latest message timestamp of the given topic. Topics whose watermarks have not
+// been fetched are reported as failed.
+func latestTimestampOf(timestamps map[string]latestMessageTimestamp, topicName string) latestMessageTimestamp {
+	latest, exists := timestamps[topicName]
+	if !exists {
+		return latestMessageTimestamp{Timestamp: -1, Status: LatestTimestampStatusFailed}
+	}
+	return latest
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/cloudhut/kowl/backend/pkg/kafka"
+)
+
+func TestNewLatestMessageTimestamp(t *testing.T) {
+	older := &kgo.Record{Partition: 0, Timestamp: time.Unix(1600000000, 0)}
+	newer := &kgo.Record{Partition: 1, Timestamp: time.Unix(1600000100, 0)}
+	filledMarks := map[int32]*kafka.PartitionMarks{
+		0: {PartitionID: 0, Low: 0, High: 10},
+		1: {PartitionID: 1, Low: 5, High: 20},
+	}
+
+	tt := []struct {
+		name          string
+		marks         map[int32]*kafka.PartitionMarks
+		latestRecords *kafka.LatestRecords
+		wantTimestamp int64
+		wantStatus    string
+	}{
+		{
+			name:          "latest of all partitions",
+			marks:         filledMarks,
+			latestRecords: &kafka.LatestRecords{Records: map[int32]*kgo.Record{0: older, 1: newer}, IsComplete: true},
+			wantTimestamp: 1600000100000,
+			wantStatus:    LatestTimestampStatusOK,
+		},
+		{
+			name:          "empty topic",
+			marks:         map[int32]*kafka.PartitionMarks{0: {PartitionID: 0, Low: 3, High: 3}},
+			latestRecords: &kafka.LatestRecords{IsComplete: true},
+			wantTimestamp: -1,
+			wantStatus:    LatestTimestampStatusEmpty,
+		},
+		{
+			name:          "only control records",
+			marks:         filledMarks,
+			latestRecords: &kafka.LatestRecords{IsComplete: true},
+			wantTimestamp: -1,
+			wantStatus:    LatestTimestampStatusNoDataRecords,
+		},
+		{
+			name:          "timed out with partial result",
+			marks:         filledMarks,
+			latestRecords: &kafka.LatestRecords{Records: map[int32]*kgo.Record{0: older}, IsComplete: false},
+			wantTimestamp: 1600000000000,
+			wantStatus:    LatestTimestampStatusTimedOut,
+		},
+		{
+			name:          "fetch failed",
+			marks:         filledMarks,
+			latestRecords: &kafka.LatestRecords{IsComplete: true, Error: errors.New("broker not available")},
+			wantTimestamp: -1,
+			wantStatus:    LatestTimestampStatusFailed,
+		},
+		{
+			name:          "watermarks failed",
+			marks:         map[int32]*kafka.PartitionMarks{0: {PartitionID: 0, Error: errors.New("not leader")}},
+			latestRecords: &kafka.LatestRecords{IsComplete: true},
+			wantTimestamp: -1,
+			wantStatus:    LatestTimestampStatusFailed,
+		},
+	}
+
+	for _, test := range tt {
+		latest := newLatestMessageTimestamp(test.marks, test.latestRecords)
+		assert.Equal(t, test.wantTimestamp, latest.Timestamp, test.name)
+		assert.Equal(t, test.wantStatus, latest.Status, test.name)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// AlterClientQuotas sets or removes client quotas for one or more entities. If validateOnly is set in the
+// request, the request will only be validated but the quotas will not be changed.
+func (s *Service) AlterClientQuotas(ctx context.Context, req kmsg.AlterClientQuotasRequest) (*kmsg.AlterClientQuotasResponse, error) {
+	return req.RequestWith(ctx, s.KafkaClient)
+}
@@ -31,6 +31,9 @@ type Config struct {
 	Protobuf    proto.Config   `yaml:"protobuf"`
 	MessagePack msgpack.Config `yaml:"messagePack"`
 
+	HeaderDecoding HeaderDecodingConfig `yaml:"headerDecoding"`
+	Redaction      RedactionConfig      `yaml:"redaction"`
+
 	TLS  TLSConfig  `yaml:"tls"`
 	SASL SASLConfig `yaml:"sasl"`
 }
@@ -69,6 +72,19 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("failed to validate msgpack config: %w", err)
 	}
 
+	err = c.HeaderDecoding.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate header decoding config: %w", err)
+	}
+	if c.HeaderDecoding.usesProtobuf() && !c.Protobuf.Enabled {
+		return fmt.Errorf("header decoding rules with protobuf encoding require protobuf to be enabled")
+	}
+
+	err = c.Redaction.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate redaction config: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,96 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+)
+
+const (
+	HeaderEncodingString   = "string"
+	HeaderEncodingInt64BE  = "int64BE"
+	HeaderEncodingUUID     = "uuid"
+	HeaderEncodingBase64   = "base64"
+	HeaderEncodingHex      = "hex"
+	HeaderEncodingProtobuf = "protobuf"
+)
+
+// HeaderDecodingConfig allows to configure how record header values shall be decoded. Header values that
+// are not matched by any rule will be decoded using the same auto-detection that is used for record values.
+type HeaderDecodingConfig struct {
+	// Rules are evaluated in order, the first rule that matches the header key (and topic) will be used.
+	Rules []HeaderDecodingRule `yaml:"rules"`
+}
+
+// HeaderDecodingRule defines the encoding that shall be used for all header values with a given key.
+type HeaderDecodingRule struct {
+	// HeaderKey is the exact header key this rule applies to.
+	HeaderKey string `yaml:"headerKey"`
+
+	// TopicNames is an optional list of topic names this rule shall be limited to. Names can be provided
+	// as regex string (e. g. "/prefix-.*/") or as plain topic name. If empty the rule applies to all topics.
+	TopicNames []string `yaml:"topicNames"`
+
+	// Encoding is one of: string, int64BE, uuid, base64, hex or protobuf.
+	Encoding string `yaml:"encoding"`
+
+	// ProtoType is the proto's fully qualified name. Only required if encoding is set to protobuf.
+	ProtoType string `yaml:"protoType"`
+}
+
+// Validate all configured header decoding rules.
+func (c *HeaderDecodingConfig) Validate() error {
+	for i, rule := range c.Rules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate header decoding rule with index '%d': %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate the header decoding rule.
+func (r *HeaderDecodingRule) Validate() error {
+	if r.HeaderKey == "" {
+		return fmt.Errorf("header key must be set")
+	}
+
+	switch r.Encoding {
+	case HeaderEncodingString, HeaderEncodingInt64BE, HeaderEncodingUUID, HeaderEncodingBase64, HeaderEncodingHex:
+	case HeaderEncodingProtobuf:
+		if r.ProtoType == "" {
+			return fmt.Errorf("proto type must be set if encoding is protobuf")
+		}
+	default:
+		return fmt.Errorf("given encoding '%v' is invalid", r.Encoding)
+	}
+
+	for _, topic := range r.TopicNames {
+		_, err := regexutil.Compile(topic)
+		if err != nil {
+			return fmt.Errorf("topic name '%v' is not valid regex", topic)
+		}
+	}
+
+	return nil
+}
+
+// usesProtobuf returns true if at least one rule requires the protobuf service.
+func (c *HeaderDecodingConfig) usesProtobuf() bool {
+	for _, rule := range c.Rules {
+		if rule.Encoding == HeaderEncodingProtobuf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+)
+
+// RedactionConfig allows to redact sensitive data (e.g. emails or credit card numbers) from the deserialized
+// record keys, values and headers before they are returned to the user or passed to the JavaScript filter.
+type RedactionConfig struct {
+	Rules []RedactionRule `yaml:"rules"`
+}
+
+// RedactionRule defines which fields and text patterns shall be redacted in the messages of the matching topics.
+type RedactionRule struct {
+	// TopicNames is an optional list of topic names this rule shall be limited to. Names can be provided
+	// as regex string (e. g. "/prefix-.*/") or as plain topic name. If empty the rule applies to all topics.
+	TopicNames []string `yaml:"topicNames"`
+
+	// Paths are JSONPath expressions (e.g. "$.customer.email", "$.cards[*].number" or "$..password") or proto
+	// field paths (e.g. "customer.email_address") whose values shall be replaced.
+	Paths []string `yaml:"paths"`
+
+	// Patterns are regular expressions. All matches within any string value (or text payload) will be replaced.
+	Patterns []string `yaml:"patterns"`
+
+	// Replacement is the text that will be inserted instead of the redacted value. Defaults to "<redacted>".
+	Replacement string `yaml:"replacement"`
+}
+
+// Validate all configured redaction rules.
+func (c *RedactionConfig) Validate() error {
+	for i, rule := range c.Rules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate redaction rule with index '%d': %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate the redaction rule.
+func (r *RedactionRule) Validate() error {
+	if len(r.Paths) == 0 && len(r.Patterns) == 0 {
+		return fmt.Errorf("at least one path or pattern must be set")
+	}
+
+	for _, topic := range r.TopicNames {
+		_, err := regexutil.Compile(topic)
+		if err != nil {
+			return fmt.Errorf("topic name '%v' is not valid regex", topic)
+		}
+	}
+
+	for _, path := range r.Paths {
+		_, err := parseRedactionPath(path)
+		if err != nil {
+			return fmt.Errorf("path '%v' is invalid: %w", path, err)
+		}
+	}
+
+	for _, pattern := range r.Patterns {
+		_, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("pattern '%v' is not valid regex: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
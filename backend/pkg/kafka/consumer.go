@@ -56,6 +56,10 @@ type TopicMessage struct {
 type MessageHeader struct {
 	Key   string               `json:"key"`
 	Value *deserializedPayload `json:"value"`
+
+	// Encoding is the encoding the header value has been decoded with. This is either the encoding of a matching
+	// header decoding rule or the auto-detected encoding.
+	Encoding messageEncoding `json:"encoding"`
 }
 
 // PartitionConsumeRequest is a partitionID along with it's calculated start and end offset.
@@ -75,6 +79,9 @@ type TopicConsumeRequest struct {
 	MaxMessageCount       int
 	Partitions            map[int32]*PartitionConsumeRequest
 	FilterInterpreterCode string
+
+	// BypassRedaction disables the configured redaction rules, so that the messages are returned as they are
+	BypassRedaction bool
 }
 
 type interpreterArguments struct {
@@ -125,7 +132,7 @@ func (s *Service) FetchMessages(ctx context.Context, progress IListMessagesProgr
 		}
 
 		wg.Add(1)
-		go s.startMessageWorker(workerCtx, &wg, isMessageOK, jobs, resultsCh)
+		go s.startMessageWorker(workerCtx, &wg, isMessageOK, consumeReq.BypassRedaction, jobs, resultsCh)
 	}
 	// Close the results channel once all workers have finished processing jobs and therefore no senders are left anymore
 	go func() {
@@ -18,7 +18,7 @@ import (
 	"time"
 )
 
-func (s *Service) startMessageWorker(ctx context.Context, wg *sync.WaitGroup, isMessageOK isMessageOkFunc, jobs <-chan *kgo.Record, resultsCh chan<- *TopicMessage) {
+func (s *Service) startMessageWorker(ctx context.Context, wg *sync.WaitGroup, isMessageOK isMessageOkFunc, bypassRedaction bool, jobs <-chan *kgo.Record, resultsCh chan<- *TopicMessage) {
 	defer wg.Done()
 
 	for record := range jobs {
@@ -45,15 +45,13 @@ func (s *Service) startMessageWorker(ctx context.Context, wg *sync.WaitGroup, is
 
 		// Run Interpreter filter and check if message passes the filter
 		deserializedRec := s.Deserializer.DeserializeRecord(record)
+		if !bypassRedaction {
+			s.redactRecord(record.Topic, deserializedRec)
+		}
 
 		headersByKey := make(map[string]interface{}, len(deserializedRec.Headers))
-		headers := make([]MessageHeader, 0)
 		for key, header := range deserializedRec.Headers {
 			headersByKey[key] = header.Object
-			headers = append(headers, MessageHeader{
-				Key:   key,
-				Value: header,
-			})
 		}
 
 		// Check if message passes filter code
@@ -73,20 +71,9 @@ func (s *Service) startMessageWorker(ctx context.Context, wg *sync.WaitGroup, is
 			errMessage = fmt.Sprintf("Failed to check if message is ok (partition: '%v', offset: '%v'). Err: %v", record.Partition, record.Offset, err)
 		}
 
-		topicMessage := &TopicMessage{
-			PartitionID:     record.Partition,
-			Offset:          record.Offset,
-			Timestamp:       record.Timestamp.UnixNano() / int64(time.Millisecond),
-			Headers:         headers,
-			Compression:     compressionTypeDisplayname(record.Attrs.CompressionType()),
-			IsTransactional: record.Attrs.IsTransactional(),
-			Key:             deserializedRec.Key,
-			Value:           deserializedRec.Value,
-			IsValueNull:     record.Value == nil,
-			IsMessageOk:     isOK,
-			ErrorMessage:    errMessage,
-			MessageSize:     int64(len(record.Key) + len(record.Value)),
-		}
+		topicMessage := newTopicMessage(record, deserializedRec)
+		topicMessage.IsMessageOk = isOK
+		topicMessage.ErrorMessage = errMessage
 
 		select {
 		case <-ctx.Done():
@@ -95,3 +82,29 @@ func (s *Service) startMessageWorker(ctx context.Context, wg *sync.WaitGroup, is
 		}
 	}
 }
+
+// newTopicMessage creates a TopicMessage from the given record and its deserialized key, value and headers.
+func newTopicMessage(record *kgo.Record, deserializedRec *deserializedRecord) *TopicMessage {
+	headers := make([]MessageHeader, 0)
+	for key, header := range deserializedRec.Headers {
+		headers = append(headers, MessageHeader{
+			Key:      key,
+			Value:    header,
+			Encoding: header.RecognizedEncoding,
+		})
+	}
+
+	return &TopicMessage{
+		PartitionID:     record.Partition,
+		Offset:          record.Offset,
+		Timestamp:       record.Timestamp.UnixNano() / int64(time.Millisecond),
+		Headers:         headers,
+		Compression:     compressionTypeDisplayname(record.Attrs.CompressionType()),
+		IsTransactional: record.Attrs.IsTransactional(),
+		Key:             deserializedRec.Key,
+		Value:           deserializedRec.Value,
+		IsValueNull:     record.Value == nil,
+		IsMessageOk:     true,
+		MessageSize:     int64(len(record.Key) + len(record.Value)),
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// CreatePartitions increases the partition count of the given topic. If assignments are set, it must contain one
+// replica assignment for each partition that is added.
+func (s *Service) CreatePartitions(ctx context.Context, topicName string, partitionCount int32, assignments [][]int32, validateOnly bool) (*kmsg.CreatePartitionsResponse, error) {
+	topicReq := kmsg.NewCreatePartitionsRequestTopic()
+	topicReq.Topic = topicName
+	topicReq.Count = partitionCount
+	for _, replicas := range assignments {
+		assignment := kmsg.NewCreatePartitionsRequestTopicAssignment()
+		assignment.Replicas = replicas
+		topicReq.Assignment = append(topicReq.Assignment, assignment)
+	}
+
+	req := kmsg.NewCreatePartitionsRequest()
+	req.Topics = []kmsg.CreatePartitionsRequestTopic{topicReq}
+	req.TimeoutMillis = 30 * 1000 // 30s
+	req.ValidateOnly = validateOnly
+
+	res, err := req.RequestWith(ctx, s.KafkaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create partitions: %w", err)
+	}
+
+	return res, nil
+}
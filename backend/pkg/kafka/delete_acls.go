@@ -0,0 +1,22 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DeleteACLs sends a DeleteACLs request for one or more filters. All ACL bindings that match a filter will be
+// deleted and returned in the filter's result.
+func (s *Service) DeleteACLs(ctx context.Context, req kmsg.DeleteACLsRequest) (*kmsg.DeleteACLsResponse, error) {
+	return req.RequestWith(ctx, s.KafkaClient)
+}
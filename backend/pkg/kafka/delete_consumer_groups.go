@@ -0,0 +1,31 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DeleteConsumerGroups deletes the given consumer groups including all their committed offsets. Kafka only
+// deletes groups that have no active members.
+func (s *Service) DeleteConsumerGroups(ctx context.Context, groupIDs []string) (*kmsg.DeleteGroupsResponse, error) {
+	req := kmsg.NewDeleteGroupsRequest()
+	req.Groups = groupIDs
+
+	res, err := req.RequestWith(ctx, s.KafkaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete consumer groups: %w", err)
+	}
+
+	return res, nil
+}
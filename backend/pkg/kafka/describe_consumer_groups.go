@@ -12,6 +12,7 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"github.com/twmb/franz-go/pkg/kbin"
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -117,3 +118,88 @@ func (s *Service) DescribeConsumerGroup(ctx context.Context, groupID string) (km
 
 	return describedGroup, nil
 }
+
+// ConnectMemberMetadata is the member metadata that Kafka Connect workers send when joining the group.
+type ConnectMemberMetadata struct {
+	Version      int16
+	URL          string
+	ConfigOffset int64
+}
+
+// ConnectMemberAssignment is the assignment that the leader of a Kafka Connect group sends to each worker.
+// Assignments of version 1 and newer (incremental cooperative rebalancing) may contain revocations.
+type ConnectMemberAssignment struct {
+	Version          int16
+	Error            int16
+	Leader           string
+	LeaderURL        string
+	ConfigOffset     int64
+	Assigned         []ConnectorTaskAssignment
+	Revoked          []ConnectorTaskAssignment
+	ScheduledDelayMs int32
+}
+
+// ConnectorTaskAssignment is a connector along with the assigned task ids. The task id -1 indicates that the
+// connector instance itself is assigned.
+type ConnectorTaskAssignment struct {
+	Connector string
+	TaskIDs   []int32
+}
+
+// ConnectorTaskID is the task id which indicates that the connector instance itself is assigned.
+const ConnectorTaskID = -1
+
+// DecodeConnectMemberMetadata decodes the member metadata of the "connect" protocol type.
+func DecodeConnectMemberMetadata(metadata []byte) (*ConnectMemberMetadata, error) {
+	r := kbin.Reader{Src: metadata}
+	res := &ConnectMemberMetadata{
+		Version:      r.Int16(),
+		URL:          r.String(),
+		ConfigOffset: r.Int64(),
+	}
+	// Version 1 and newer append the worker's previous assignment, which we don't need
+	if err := r.Complete(); err != nil {
+		return nil, fmt.Errorf("failed to decode connect member metadata: %w", err)
+	}
+
+	return res, nil
+}
+
+// DecodeConnectMemberAssignment decodes the member assignment of the "connect" protocol type.
+func DecodeConnectMemberAssignment(assignment []byte) (*ConnectMemberAssignment, error) {
+	r := kbin.Reader{Src: assignment}
+	res := &ConnectMemberAssignment{
+		Version:      r.Int16(),
+		Error:        r.Int16(),
+		Leader:       r.String(),
+		LeaderURL:    r.String(),
+		ConfigOffset: r.Int64(),
+	}
+	res.Assigned = readConnectorTaskAssignments(&r)
+	if res.Version >= 1 {
+		res.Revoked = readConnectorTaskAssignments(&r)
+		res.ScheduledDelayMs = r.Int32()
+	}
+	if err := r.Complete(); err != nil {
+		return nil, fmt.Errorf("failed to decode connect member assignment: %w", err)
+	}
+
+	return res, nil
+}
+
+func readConnectorTaskAssignments(r *kbin.Reader) []ConnectorTaskAssignment {
+	l := r.ArrayLen()
+	if l <= 0 {
+		return nil
+	}
+	assignments := make([]ConnectorTaskAssignment, 0, l)
+	for i := int32(0); i < l && r.Ok(); i++ {
+		assignment := ConnectorTaskAssignment{Connector: r.String()}
+		taskCount := r.ArrayLen()
+		for j := int32(0); j < taskCount && r.Ok(); j++ {
+			assignment.TaskIDs = append(assignment.TaskIDs, r.Int32())
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kbin"
+)
+
+func appendConnectorTaskAssignment(dst []byte, connector string, taskIDs ...int32) []byte {
+	dst = kbin.AppendString(dst, connector)
+	dst = kbin.AppendArrayLen(dst, len(taskIDs))
+	for _, taskID := range taskIDs {
+		dst = kbin.AppendInt32(dst, taskID)
+	}
+	return dst
+}
+
+func TestDecodeConnectMemberMetadata(t *testing.T) {
+	// Version 1 metadata contains the previous assignment, which must be ignored
+	metadata := kbin.AppendInt16(nil, 1)
+	metadata = kbin.AppendString(metadata, "http://worker-1:8083/")
+	metadata = kbin.AppendInt64(metadata, 42)
+	metadata = kbin.AppendNullableBytes(metadata, []byte{0, 1})
+
+	decoded, err := DecodeConnectMemberMetadata(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, ConnectMemberMetadata{Version: 1, URL: "http://worker-1:8083/", ConfigOffset: 42}, *decoded)
+
+	_, err = DecodeConnectMemberMetadata([]byte{0, 1, 0})
+	assert.Error(t, err)
+}
+
+func TestDecodeConnectMemberAssignment(t *testing.T) {
+	header := func(version int16) []byte {
+		b := kbin.AppendInt16(nil, version)
+		b = kbin.AppendInt16(b, 0)
+		b = kbin.AppendString(b, "leader-id")
+		b = kbin.AppendString(b, "http://worker-1:8083/")
+		return kbin.AppendInt64(b, 42)
+	}
+
+	// Eager rebalance protocol (version 0)
+	v0 := header(0)
+	v0 = kbin.AppendArrayLen(v0, 1)
+	v0 = appendConnectorTaskAssignment(v0, "s3-sink", ConnectorTaskID, 0, 2)
+
+	decoded, err := DecodeConnectMemberAssignment(v0)
+	require.NoError(t, err)
+	assert.Equal(t, "leader-id", decoded.Leader)
+	assert.Equal(t, "http://worker-1:8083/", decoded.LeaderURL)
+	assert.Equal(t, int64(42), decoded.ConfigOffset)
+	assert.Equal(t, []ConnectorTaskAssignment{{Connector: "s3-sink", TaskIDs: []int32{-1, 0, 2}}}, decoded.Assigned)
+	assert.Nil(t, decoded.Revoked)
+
+	// Incremental cooperative rebalance protocol (version 1) with a null assignment and revocations
+	v1 := header(1)
+	v1 = kbin.AppendNullableArrayLen(v1, 0, true)
+	v1 = kbin.AppendArrayLen(v1, 1)
+	v1 = appendConnectorTaskAssignment(v1, "jdbc-source", 1)
+	v1 = kbin.AppendInt32(v1, 3000)
+
+	decoded, err = DecodeConnectMemberAssignment(v1)
+	require.NoError(t, err)
+	assert.Nil(t, decoded.Assigned)
+	assert.Equal(t, []ConnectorTaskAssignment{{Connector: "jdbc-source", TaskIDs: []int32{1}}}, decoded.Revoked)
+	assert.Equal(t, int32(3000), decoded.ScheduledDelayMs)
+
+	_, err = DecodeConnectMemberAssignment(v1[:len(v1)-2])
+	assert.Error(t, err)
+}
@@ -34,6 +34,9 @@ type deserializer struct {
 	SchemaService  *schema.Service
 	ProtoService   *proto.Service
 	MsgPackService *kmsgpack.Service
+
+	// HeaderDecodingRules define how specific record headers shall be decoded
+	HeaderDecodingRules []headerDecodingRule
 }
 
 type messageEncoding string
@@ -48,6 +51,12 @@ const (
 	messageEncodingConsumerOffsets messageEncoding = "consumerOffsets"
 	messageEncodingBinary          messageEncoding = "binary"
 	messageEncodingMsgP            messageEncoding = "msgpack"
+
+	// Encodings that are only used for headers which are decoded by a configured header decoding rule
+	messageEncodingInt64  messageEncoding = "int64"
+	messageEncodingUUID   messageEncoding = "uuid"
+	messageEncodingBase64 messageEncoding = "base64"
+	messageEncodingHex    messageEncoding = "hex"
 )
 
 // normalizedPayload is a wrapper of the original message with the purpose of having a custom JSON marshal method
@@ -106,7 +115,7 @@ func (d *deserializer) DeserializeRecord(record *kgo.Record) *deserializedRecord
 
 	headers := make(map[string]*deserializedPayload)
 	for _, header := range record.Headers {
-		headers[header.Key] = d.deserializePayload(header.Value, record.Topic, proto.RecordValue)
+		headers[header.Key] = d.deserializeHeader(header, record.Topic)
 	}
 	return &deserializedRecord{
 		Key:     d.deserializePayload(record.Key, record.Topic, proto.RecordKey),
@@ -132,127 +141,208 @@ func (d *deserializer) deserializePayload(payload []byte, topicName string, reco
 		}, Object: string(payload), RecognizedEncoding: messageEncodingText, Size: len(payload)}
 	}
 
-	// 1. Test for valid JSON
-	startsWithJSON := trimmed[0] == '[' || trimmed[0] == '{'
-	if startsWithJSON {
-		var obj interface{}
-		err := json.Unmarshal(payload, &obj)
+	// 1-7. Try all decoders in order, the first decoder that succeeds wins
+	for _, decoder := range d.payloadDecoders() {
+		deserialized, err := decoder.Decode(payload, topicName, recordType)
 		if err == nil {
-			return &deserializedPayload{Payload: normalizedPayload{
-				Payload:            trimmed,
-				RecognizedEncoding: messageEncodingJSON,
-			}, Object: obj, RecognizedEncoding: messageEncodingJSON, Size: len(payload)}
+			return deserialized
 		}
 	}
 
-	// 2. Test for json schema
-	if d.SchemaService != nil && len(payload) > 5 && payload[0] == byte(0) {
-		schemaID := binary.BigEndian.Uint32(payload[1:5])
-		trimmed := bytes.TrimLeft(payload[5:], " \t\r\n")
-		startsWithJSON := trimmed[0] == '[' || trimmed[0] == '{'
-		if startsWithJSON {
-			var obj interface{}
-			err := json.Unmarshal(payload[5:], &obj)
-			if err == nil {
-				return &deserializedPayload{Payload: normalizedPayload{
-					Payload:            trimmed,
-					RecognizedEncoding: messageEncodingJSON,
-				}, Object: obj, RecognizedEncoding: messageEncodingJSON, SchemaID: schemaID, Size: len(payload)}
-			}
-		}
+	// Anything else is considered as binary content
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            payload,
+		RecognizedEncoding: messageEncodingBinary,
+	}, Object: payload, RecognizedEncoding: messageEncodingBinary, Size: len(payload)}
+}
 
+// payloadDecoder is a single step in the deserializer chain. Decode returns an error if the payload is not
+// encoded in the decoder's format.
+type payloadDecoder struct {
+	Name   string
+	Decode func(payload []byte, topicName string, recordType proto.RecordPropertyType) (*deserializedPayload, error)
+}
+
+// payloadDecoders returns all decoders in the order they are tried by deserializePayload.
+func (d *deserializer) payloadDecoders() []payloadDecoder {
+	return []payloadDecoder{
+		{Name: "json", Decode: d.decodeJSON},
+		{Name: "jsonSchema", Decode: d.decodeJSONSchema},
+		{Name: "xml", Decode: d.decodeXML},
+		{Name: "avro", Decode: d.decodeAvro},
+		{Name: "protobuf", Decode: d.decodeProtobuf},
+		{Name: "msgpack", Decode: d.decodeMsgPack},
+		{Name: "text", Decode: d.decodeText},
 	}
+}
 
-	// 3. Test for valid XML
-	startsWithXML := trimmed[0] == '<'
-	if startsWithXML {
-		r := strings.NewReader(string(trimmed))
-		jsonPayload, err := xj.Convert(r)
-		if err == nil {
-			var obj interface{}
-			_ = json.Unmarshal(jsonPayload.Bytes(), &obj) // no err possible unless the xml2json package is buggy
-			return &deserializedPayload{Payload: normalizedPayload{
-				Payload:            jsonPayload.Bytes(),
-				RecognizedEncoding: messageEncodingXML,
-			}, Object: obj, RecognizedEncoding: messageEncodingXML, Size: len(payload)}
-		}
+// decodeJSON tests for valid JSON
+func (d *deserializer) decodeJSON(payload []byte, _ string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	trimmed := bytes.TrimLeft(payload, " \t\r\n")
+	startsWithJSON := len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')
+	if !startsWithJSON {
+		return nil, fmt.Errorf("first byte indicates this is not valid JSON, expected brackets")
 	}
 
-	// 4. Test for Avro (reference: https://docs.confluent.io/current/schema-registry/serdes-develop/index.html#wire-format)
-	if d.SchemaService != nil && len(payload) > 5 {
-		// Check if magic byte is set
-		if payload[0] == byte(0) {
-			schemaID := binary.BigEndian.Uint32(payload[1:5])
-			codec, err := d.SchemaService.GetAvroSchemaByID(schemaID)
-			if err == nil {
-				native, _, err := codec.NativeFromBinary(payload[5:])
-				if err == nil {
-					normalized, _ := codec.TextualFromNative(nil, native)
-					return &deserializedPayload{
-						Payload: normalizedPayload{
-							Payload:            normalized,
-							RecognizedEncoding: messageEncodingAvro,
-						},
-						Object:             native,
-						RecognizedEncoding: messageEncodingAvro,
-						SchemaID:           schemaID,
-						Size:               len(payload),
-					}
-				}
-			}
-		}
+	var obj interface{}
+	err := json.Unmarshal(payload, &obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON payload: %w", err)
 	}
 
-	// 5. Test for Protobuf
-	if d.ProtoService != nil {
-		jsonBytes, schemaID, err := d.ProtoService.UnmarshalPayload(payload, topicName, recordType)
-		if err == nil {
-			var native interface{}
-			err := json.Unmarshal(jsonBytes, &native)
-			if err == nil {
-				return &deserializedPayload{
-					Payload: normalizedPayload{
-						Payload:            jsonBytes,
-						RecognizedEncoding: messageEncodingProtobuf,
-					},
-					Object:             native,
-					RecognizedEncoding: messageEncodingProtobuf,
-					SchemaID:           uint32(schemaID),
-					Size:               len(payload),
-				}
-			}
-		}
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            trimmed,
+		RecognizedEncoding: messageEncodingJSON,
+	}, Object: obj, RecognizedEncoding: messageEncodingJSON, Size: len(payload)}, nil
+}
+
+// decodeJSONSchema tests for JSON which has been prefixed with the magic byte and schema ID
+func (d *deserializer) decodeJSONSchema(payload []byte, _ string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	if d.SchemaService == nil {
+		return nil, fmt.Errorf("no schema registry configured")
+	}
+	if len(payload) <= 5 || payload[0] != byte(0) {
+		return nil, fmt.Errorf("payload does not start with magic byte")
 	}
 
-	// 6. Test for MessagePack (only if enabled and topic allowed)
-	if d.MsgPackService != nil && d.MsgPackService.IsTopicAllowed(topicName) {
-		var obj interface{}
-		err := msgpack.Unmarshal(payload, &obj)
-		if err == nil {
-			data, err := json.Marshal(obj)
-			if err == nil {
-				return &deserializedPayload{Payload: normalizedPayload{
-					Payload:            data,
-					RecognizedEncoding: messageEncodingMsgP,
-				}, Object: string(payload), RecognizedEncoding: messageEncodingMsgP, Size: len(payload)}
-			}
-		}
+	schemaID := binary.BigEndian.Uint32(payload[1:5])
+	trimmed := bytes.TrimLeft(payload[5:], " \t\r\n")
+	startsWithJSON := len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')
+	if !startsWithJSON {
+		return nil, fmt.Errorf("first byte after schema id indicates this is not valid JSON, expected brackets")
 	}
 
-	// 7. Test for UTF-8 validity
-	isUTF8 := utf8.Valid(payload)
-	if isUTF8 {
-		return &deserializedPayload{Payload: normalizedPayload{
-			Payload:            payload,
-			RecognizedEncoding: messageEncodingText,
-		}, Object: string(payload), RecognizedEncoding: messageEncodingText, Size: len(payload)}
+	var obj interface{}
+	err := json.Unmarshal(payload[5:], &obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON payload: %w", err)
+	}
+
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            trimmed,
+		RecognizedEncoding: messageEncodingJSON,
+	}, Object: obj, RecognizedEncoding: messageEncodingJSON, SchemaID: schemaID, Size: len(payload)}, nil
+}
+
+// decodeXML tests for valid XML
+func (d *deserializer) decodeXML(payload []byte, _ string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	trimmed := bytes.TrimLeft(payload, " \t\r\n")
+	startsWithXML := len(trimmed) > 0 && trimmed[0] == '<'
+	if !startsWithXML {
+		return nil, fmt.Errorf("first byte indicates this is not valid XML")
+	}
+
+	r := strings.NewReader(string(trimmed))
+	jsonPayload, err := xj.Convert(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert XML payload to JSON: %w", err)
+	}
+
+	var obj interface{}
+	_ = json.Unmarshal(jsonPayload.Bytes(), &obj) // no err possible unless the xml2json package is buggy
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            jsonPayload.Bytes(),
+		RecognizedEncoding: messageEncodingXML,
+	}, Object: obj, RecognizedEncoding: messageEncodingXML, Size: len(payload)}, nil
+}
+
+// decodeAvro tests for Avro (reference: https://docs.confluent.io/current/schema-registry/serdes-develop/index.html#wire-format)
+func (d *deserializer) decodeAvro(payload []byte, _ string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	if d.SchemaService == nil {
+		return nil, fmt.Errorf("no schema registry configured")
+	}
+	// Check if magic byte is set
+	if len(payload) <= 5 || payload[0] != byte(0) {
+		return nil, fmt.Errorf("payload does not start with magic byte")
+	}
+
+	schemaID := binary.BigEndian.Uint32(payload[1:5])
+	codec, err := d.SchemaService.GetAvroSchemaByID(schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get avro schema by id '%d': %w", schemaID, err)
+	}
+	native, _, err := codec.NativeFromBinary(payload[5:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode avro payload: %w", err)
+	}
+
+	normalized, _ := codec.TextualFromNative(nil, native)
+	return &deserializedPayload{
+		Payload: normalizedPayload{
+			Payload:            normalized,
+			RecognizedEncoding: messageEncodingAvro,
+		},
+		Object:             native,
+		RecognizedEncoding: messageEncodingAvro,
+		SchemaID:           schemaID,
+		Size:               len(payload),
+	}, nil
+}
+
+// decodeProtobuf tests for Protobuf, either via schema registry or via the configured topic mappings
+func (d *deserializer) decodeProtobuf(payload []byte, topicName string, recordType proto.RecordPropertyType) (*deserializedPayload, error) {
+	if d.ProtoService == nil {
+		return nil, fmt.Errorf("no protobuf decoding configured")
+	}
+
+	jsonBytes, schemaID, err := d.ProtoService.UnmarshalPayload(payload, topicName, recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	var native interface{}
+	err = json.Unmarshal(jsonBytes, &native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protobuf JSON: %w", err)
+	}
+
+	return &deserializedPayload{
+		Payload: normalizedPayload{
+			Payload:            jsonBytes,
+			RecognizedEncoding: messageEncodingProtobuf,
+		},
+		Object:             native,
+		RecognizedEncoding: messageEncodingProtobuf,
+		SchemaID:           uint32(schemaID),
+		Size:               len(payload),
+	}, nil
+}
+
+// decodeMsgPack tests for MessagePack (only if enabled and topic allowed)
+func (d *deserializer) decodeMsgPack(payload []byte, topicName string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	if d.MsgPackService == nil {
+		return nil, fmt.Errorf("no messagepack decoding configured")
+	}
+	if !d.MsgPackService.IsTopicAllowed(topicName) {
+		return nil, fmt.Errorf("topic is not allowed to be decoded with messagepack")
+	}
+
+	var obj interface{}
+	err := msgpack.Unmarshal(payload, &obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode messagepack payload: %w", err)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal decoded messagepack payload to JSON: %w", err)
+	}
+
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            data,
+		RecognizedEncoding: messageEncodingMsgP,
+	}, Object: string(payload), RecognizedEncoding: messageEncodingMsgP, Size: len(payload)}, nil
+}
+
+// decodeText tests for UTF-8 validity
+func (d *deserializer) decodeText(payload []byte, _ string, _ proto.RecordPropertyType) (*deserializedPayload, error) {
+	if !utf8.Valid(payload) {
+		return nil, fmt.Errorf("payload is not valid UTF-8")
 	}
 
-	// Anything else is considered as binary content
 	return &deserializedPayload{Payload: normalizedPayload{
 		Payload:            payload,
-		RecognizedEncoding: messageEncodingBinary,
-	}, Object: payload, RecognizedEncoding: messageEncodingBinary, Size: len(payload)}
+		RecognizedEncoding: messageEncodingText,
+	}, Object: string(payload), RecognizedEncoding: messageEncodingText, Size: len(payload)}, nil
 }
 
 // deserializeConsumerOffset deserializes the binary messages in the __consumer_offsets topic
@@ -0,0 +1,152 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/cloudhut/kowl/backend/pkg/proto"
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+	"github.com/google/uuid"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// headerDecodingRule is a HeaderDecodingRule along with its compiled topic name expressions.
+type headerDecodingRule struct {
+	HeaderDecodingRule
+	topicNamesExpr []*regexp.Regexp
+}
+
+func compileHeaderDecodingRules(rules []HeaderDecodingRule) ([]headerDecodingRule, error) {
+	compiledRules := make([]headerDecodingRule, len(rules))
+	for i, rule := range rules {
+		exprs := make([]*regexp.Regexp, len(rule.TopicNames))
+		for j, topicName := range rule.TopicNames {
+			expr, err := regexutil.Compile(topicName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile topic name expression '%v': %w", topicName, err)
+			}
+			exprs[j] = expr
+		}
+		compiledRules[i] = headerDecodingRule{
+			HeaderDecodingRule: rule,
+			topicNamesExpr:     exprs,
+		}
+	}
+
+	return compiledRules, nil
+}
+
+// matches returns true if the rule shall be applied for the given header key in the given topic.
+func (r *headerDecodingRule) matches(topicName string, headerKey string) bool {
+	if r.HeaderKey != headerKey {
+		return false
+	}
+	if len(r.topicNamesExpr) == 0 {
+		return true
+	}
+	for _, expr := range r.topicNamesExpr {
+		if expr.MatchString(topicName) {
+			return true
+		}
+	}
+	return false
+}
+
+// deserializeHeader decodes a header value using the first matching header decoding rule. If no rule matches or the
+// header value can't be decoded with the configured encoding, the header value will be auto-detected the same
+// way as record values are.
+func (d *deserializer) deserializeHeader(header kgo.RecordHeader, topicName string) *deserializedPayload {
+	if len(header.Value) > 0 {
+		for _, rule := range d.HeaderDecodingRules {
+			if !rule.matches(topicName, header.Key) {
+				continue
+			}
+			payload, err := d.deserializeHeaderWithRule(header.Value, rule)
+			if err == nil {
+				return payload
+			}
+			break
+		}
+	}
+
+	return d.deserializePayload(header.Value, topicName, proto.RecordValue)
+}
+
+func (d *deserializer) deserializeHeaderWithRule(value []byte, rule headerDecodingRule) (*deserializedPayload, error) {
+	switch rule.Encoding {
+	case HeaderEncodingString:
+		if !utf8.Valid(value) {
+			return nil, fmt.Errorf("header value is not valid UTF-8")
+		}
+		return &deserializedPayload{Payload: normalizedPayload{
+			Payload:            value,
+			RecognizedEncoding: messageEncodingText,
+		}, Object: string(value), RecognizedEncoding: messageEncodingText, Size: len(value)}, nil
+	case HeaderEncodingInt64BE:
+		if len(value) != 8 {
+			return nil, fmt.Errorf("expected 8 bytes for a big endian int64, but got '%d' bytes", len(value))
+		}
+		number := int64(binary.BigEndian.Uint64(value))
+		return &deserializedPayload{Payload: normalizedPayload{
+			Payload:            []byte(strconv.FormatInt(number, 10)),
+			RecognizedEncoding: messageEncodingInt64,
+		}, Object: number, RecognizedEncoding: messageEncodingInt64, Size: len(value)}, nil
+	case HeaderEncodingUUID:
+		id, err := uuid.FromBytes(value)
+		if err != nil {
+			// UUIDs may also be sent in their string representation
+			id, err = uuid.ParseBytes(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse uuid: %w", err)
+			}
+		}
+		return newStringHeaderPayload(id.String(), messageEncodingUUID, len(value)), nil
+	case HeaderEncodingBase64:
+		return newStringHeaderPayload(base64.StdEncoding.EncodeToString(value), messageEncodingBase64, len(value)), nil
+	case HeaderEncodingHex:
+		return newStringHeaderPayload(hex.EncodeToString(value), messageEncodingHex, len(value)), nil
+	case HeaderEncodingProtobuf:
+		if d.ProtoService == nil {
+			return nil, fmt.Errorf("protobuf service is not configured")
+		}
+		jsonBytes, err := d.ProtoService.UnmarshalPayloadWithType(value, rule.ProtoType)
+		if err != nil {
+			return nil, err
+		}
+		var native interface{}
+		err = json.Unmarshal(jsonBytes, &native)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal protobuf json: %w", err)
+		}
+		return &deserializedPayload{Payload: normalizedPayload{
+			Payload:            jsonBytes,
+			RecognizedEncoding: messageEncodingProtobuf,
+		}, Object: native, RecognizedEncoding: messageEncodingProtobuf, Size: len(value)}, nil
+	default:
+		return nil, fmt.Errorf("unknown header encoding '%v'", rule.Encoding)
+	}
+}
+
+func newStringHeaderPayload(str string, encoding messageEncoding, size int) *deserializedPayload {
+	// Marshalling a string can not fail
+	payload, _ := json.Marshal(str)
+	return &deserializedPayload{Payload: normalizedPayload{
+		Payload:            payload,
+		RecognizedEncoding: encoding,
+	}, Object: str, RecognizedEncoding: encoding, Size: size}
+}
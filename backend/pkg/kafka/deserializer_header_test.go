@@ -0,0 +1,99 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestHeaderDecodingRuleMatches(t *testing.T) {
+	rules, err := compileHeaderDecodingRules([]HeaderDecodingRule{
+		{HeaderKey: "trace-id", Encoding: HeaderEncodingHex},
+		{HeaderKey: "created-at", TopicNames: []string{"/orders-.*/", "payments"}, Encoding: HeaderEncodingInt64BE},
+	})
+	require.NoError(t, err)
+
+	tt := []struct {
+		rule      int
+		topicName string
+		headerKey string
+		expected  bool
+	}{
+		{0, "orders-eu", "trace-id", true},
+		{0, "orders-eu", "Trace-Id", false},
+		{1, "orders-eu", "created-at", true},
+		{1, "payments", "created-at", true},
+		{1, "payments-eu", "created-at", false},
+		{1, "orders-eu", "trace-id", false},
+	}
+	for _, test := range tt {
+		assert.Equal(t, test.expected, rules[test.rule].matches(test.topicName, test.headerKey),
+			"rule %d, topic %v, header %v", test.rule, test.topicName, test.headerKey)
+	}
+}
+
+func TestDeserializeHeaderWithRule(t *testing.T) {
+	d := &deserializer{}
+	tt := []struct {
+		encoding         string
+		value            []byte
+		expectedObject   interface{}
+		expectedEncoding messageEncoding
+		expectErr        bool
+	}{
+		{HeaderEncodingString, []byte("hello"), "hello", messageEncodingText, false},
+		{HeaderEncodingString, []byte{0xff, 0xfe}, nil, "", true},
+		{HeaderEncodingInt64BE, []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}, int64(256), messageEncodingInt64, false},
+		{HeaderEncodingInt64BE, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, int64(-1), messageEncodingInt64, false},
+		{HeaderEncodingInt64BE, []byte{0x01}, nil, "", true},
+		{HeaderEncodingUUID, []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+			"123e4567-e89b-12d3-a456-426614174000", messageEncodingUUID, false},
+		{HeaderEncodingUUID, []byte("123e4567-e89b-12d3-a456-426614174000"),
+			"123e4567-e89b-12d3-a456-426614174000", messageEncodingUUID, false},
+		{HeaderEncodingUUID, []byte("no-uuid"), nil, "", true},
+		{HeaderEncodingBase64, []byte{0x00, 0xff}, "AP8=", messageEncodingBase64, false},
+		{HeaderEncodingHex, []byte{0x00, 0xff}, "00ff", messageEncodingHex, false},
+		{HeaderEncodingProtobuf, []byte{0x08, 0x01}, nil, "", true},
+	}
+	for _, test := range tt {
+		rule := headerDecodingRule{HeaderDecodingRule: HeaderDecodingRule{HeaderKey: "key", Encoding: test.encoding}}
+		payload, err := d.deserializeHeaderWithRule(test.value, rule)
+		if test.expectErr {
+			assert.Error(t, err, "encoding %v, value %x", test.encoding, test.value)
+			continue
+		}
+		require.NoError(t, err, "encoding %v, value %x", test.encoding, test.value)
+		assert.Equal(t, test.expectedObject, payload.Object)
+		assert.Equal(t, test.expectedEncoding, payload.RecognizedEncoding)
+		assert.Equal(t, len(test.value), payload.Size)
+	}
+}
+
+func TestDeserializeHeaderFallsBack(t *testing.T) {
+	rules, err := compileHeaderDecodingRules([]HeaderDecodingRule{
+		{HeaderKey: "created-at", TopicNames: []string{"orders"}, Encoding: HeaderEncodingInt64BE},
+	})
+	require.NoError(t, err)
+	d := &deserializer{HeaderDecodingRules: rules}
+
+	value := []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}
+	payload := d.deserializeHeader(kgo.RecordHeader{Key: "created-at", Value: value}, "orders")
+	assert.Equal(t, messageEncodingInt64, payload.RecognizedEncoding)
+
+	// Rules that do not match the topic or can not decode the value fall back to the regular payload detection
+	payload = d.deserializeHeader(kgo.RecordHeader{Key: "created-at", Value: []byte("text")}, "orders")
+	assert.Equal(t, messageEncodingText, payload.RecognizedEncoding)
+	payload = d.deserializeHeader(kgo.RecordHeader{Key: "created-at", Value: value}, "payments")
+	assert.NotEqual(t, messageEncodingInt64, payload.RecognizedEncoding)
+}
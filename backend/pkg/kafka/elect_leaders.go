@@ -0,0 +1,33 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ElectLeaders triggers a leader election of the given election type (0 = preferred, 1 = unclean) for the
+// given topic partitions. Pass nil for topics in order to elect leaders for all partitions in the cluster.
+func (s *Service) ElectLeaders(ctx context.Context, electionType int8, topics []kmsg.ElectLeadersRequestTopic) (*kmsg.ElectLeadersResponse, error) {
+	req := kmsg.NewElectLeadersRequest()
+	req.ElectionType = electionType
+	req.Topics = topics
+	req.TimeoutMillis = 30 * 1000 // 30s
+
+	res, err := req.RequestWith(ctx, s.KafkaClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to elect leaders: %w", err)
+	}
+
+	return res, nil
+}
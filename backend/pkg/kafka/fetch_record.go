@@ -0,0 +1,197 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// ErrRecordNotFound is returned if the requested offset does not exist (anymore) in the partition. This may be
+// the case if the offset is out of the watermark range, has been compacted or is a control record.
+var ErrRecordNotFound = errors.New("record not found")
+
+// FetchRecord consumes exactly one record at the given offset from the given topic partition.
+func (s *Service) FetchRecord(ctx context.Context, topicName string, partitionID int32, offset int64) (*kgo.Record, error) {
+	// 1. Check if the requested offset is within the partition's watermarks, otherwise we'd wait until the
+	// context is cancelled.
+	marks, err := s.GetPartitionMarks(ctx, topicName, []int32{partitionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partition watermarks: %w", err)
+	}
+	mark, exists := marks[partitionID]
+	if !exists {
+		return nil, fmt.Errorf("no watermarks have been returned for the requested partition")
+	}
+	if mark.Error != nil {
+		return nil, fmt.Errorf("failed to get partition watermarks: %w", mark.Error)
+	}
+	if offset < mark.Low || offset >= mark.High {
+		return nil, fmt.Errorf("%w: offset '%d' is not within the partition's low (%d) and high (%d) watermark",
+			ErrRecordNotFound, offset, mark.Low, mark.High)
+	}
+
+	// 2. Create client that consumes the partition starting at the requested offset
+	partitionOffsets := map[string]map[int32]kgo.Offset{
+		topicName: {partitionID: kgo.NewOffset().At(offset)},
+	}
+	client, err := s.NewKgoClient(kgo.ConsumePartitions(partitionOffsets))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new kafka client: %w", err)
+	}
+	defer client.Close()
+
+	// 3. Poll until we either received the requested offset, a record with a higher offset or reached the high
+	// water mark. The latter two indicate that the requested offset does not exist (e.g. due to compaction or
+	// because it holds a control marker or an aborted record that is not returned by the broker).
+	for {
+		fetches := client.PollFetches(ctx)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		for _, fetchErr := range fetches.Errors() {
+			s.Logger.Debug("error while fetching single record",
+				zap.String("topic_name", fetchErr.Topic),
+				zap.Int32("partition", fetchErr.Partition),
+				zap.Error(fetchErr.Err))
+			return nil, fmt.Errorf("failed to fetch record: %w", fetchErr.Err)
+		}
+
+		var record *kgo.Record
+		isHighWaterMarkReached := false
+		fetches.EachPartition(func(partition kgo.FetchTopicPartition) {
+			for _, rec := range partition.Records {
+				if rec.Offset >= offset && record == nil {
+					record = rec
+				}
+			}
+			if partition.HighWatermark > 0 && partition.HighWatermark-1 <= offset {
+				isHighWaterMarkReached = true
+			}
+		})
+
+		if record != nil {
+			if record.Offset > offset || record.Attrs.IsControl() {
+				return nil, fmt.Errorf("%w: offset '%d' does not exist in partition, it may have been compacted or is a control record",
+					ErrRecordNotFound, offset)
+			}
+			return record, nil
+		}
+		if isHighWaterMarkReached {
+			return nil, fmt.Errorf("%w: offset '%d' does not exist in partition, it is a control record or belongs to an aborted transaction",
+				ErrRecordNotFound, offset)
+		}
+	}
+}
+
+// LatestRecords are the latest data records of a topic's partitions, as returned by FetchLatestRecords.
+type LatestRecords struct {
+	Records map[int32]*kgo.Record
+
+	// IsComplete is false if at least one partition could not be consumed up to its high water mark in time.
+	IsComplete bool
+
+	// Error is set if fetching at least one of the partitions failed.
+	Error error
+}
+
+// FetchLatestRecords consumes the latest data record of each given partition, using a single client for all
+// topics. The passed marks must contain the watermarks of all partitions that shall be consumed, where the topic
+// name is the key. Empty partitions are skipped. Control records are never returned. Partitions whose latest
+// records are all control records are missing in the result, the same applies to partitions that could not be
+// consumed up to their high water mark before the context is done.
+func (s *Service) FetchLatestRecords(ctx context.Context, marks map[string]map[int32]*PartitionMarks) (map[string]*LatestRecords, error) {
+	// Start a few offsets before the high water mark, so that we likely get a record even if the latest
+	// offsets are transaction markers.
+	result := make(map[string]*LatestRecords, len(marks))
+	topicOffsets := make(map[string]map[int32]kgo.Offset)
+	pending := make(map[string]map[int32]struct{})
+	for topicName, topicMarks := range marks {
+		result[topicName] = &LatestRecords{Records: make(map[int32]*kgo.Record), IsComplete: true}
+		for partitionID, mark := range topicMarks {
+			if mark.Error != nil || mark.High <= mark.Low {
+				continue
+			}
+			startOffset := mark.High - 5
+			if startOffset < mark.Low {
+				startOffset = mark.Low
+			}
+			if _, exists := topicOffsets[topicName]; !exists {
+				topicOffsets[topicName] = make(map[int32]kgo.Offset)
+				pending[topicName] = make(map[int32]struct{})
+			}
+			topicOffsets[topicName][partitionID] = kgo.NewOffset().At(startOffset)
+			pending[topicName][partitionID] = struct{}{}
+		}
+	}
+	if len(topicOffsets) == 0 {
+		return result, nil
+	}
+
+	client, err := s.NewKgoClient(kgo.ConsumePartitions(topicOffsets))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new kafka client: %w", err)
+	}
+	defer client.Close()
+
+	// A partition is complete once we consumed the record before the high water mark, regardless of whether that
+	// is a data or a control record.
+	completePartition := func(topicName string, partitionID int32) {
+		delete(pending[topicName], partitionID)
+		if len(pending[topicName]) == 0 {
+			delete(pending, topicName)
+		}
+	}
+	for len(pending) > 0 {
+		fetches := client.PollFetches(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+
+		fetches.EachPartition(func(partition kgo.FetchTopicPartition) {
+			latest, exists := result[partition.Topic]
+			if !exists {
+				return
+			}
+			if partition.Err != nil {
+				latest.Error = fmt.Errorf("failed to fetch records of partition '%d': %w", partition.Partition, partition.Err)
+				completePartition(partition.Topic, partition.Partition)
+				return
+			}
+			for _, record := range partition.Records {
+				if !record.Attrs.IsControl() {
+					latest.Records[record.Partition] = record
+				}
+				if record.Offset >= marks[partition.Topic][record.Partition].High-1 {
+					completePartition(partition.Topic, record.Partition)
+				}
+			}
+		})
+	}
+	for topicName := range pending {
+		result[topicName].IsComplete = false
+	}
+
+	return result, nil
+}
+
+// DeserializeTopicMessage deserializes the given record into a TopicMessage, the same way records are returned
+// when listing messages. Configured redaction rules are applied unless bypassRedaction is true.
+func (s *Service) DeserializeTopicMessage(record *kgo.Record, bypassRedaction bool) *TopicMessage {
+	deserializedRec := s.Deserializer.DeserializeRecord(record)
+	if !bypassRedaction {
+		s.redactRecord(record.Topic, deserializedRec)
+	}
+	return newTopicMessage(record, deserializedRec)
+}
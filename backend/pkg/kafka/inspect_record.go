@@ -0,0 +1,226 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cloudhut/kowl/backend/pkg/proto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// RecordInspection is a byte-level breakdown of a single record. It is meant to debug records that can not be
+// deserialized as expected.
+type RecordInspection struct {
+	TopicName       string             `json:"topicName"`
+	PartitionID     int32              `json:"partitionId"`
+	Offset          int64              `json:"offset"`
+	Timestamp       int64              `json:"timestamp"`
+	Compression     string             `json:"compression"`
+	IsTransactional bool               `json:"isTransactional"`
+	Key             PayloadInspection  `json:"key"`
+	Value           PayloadInspection  `json:"value"`
+	Headers         []HeaderInspection `json:"headers"`
+}
+
+// HeaderInspection is the inspection result of a single record header.
+type HeaderInspection struct {
+	Key   string            `json:"key"`
+	Value PayloadInspection `json:"value"`
+}
+
+// PayloadInspection describes the raw bytes of a key, value or header value along with the results of all decoders.
+type PayloadInspection struct {
+	IsNull  bool           `json:"isNull"`
+	Size    int            `json:"size"`
+	HexDump string         `json:"hexDump"`
+	Framing PayloadFraming `json:"framing"`
+
+	// DecoderResults contains the result of every decoder in the deserializer chain, in the order they are tried.
+	DecoderResults []DecoderResult `json:"decoderResults"`
+
+	// Deserialized is the payload as it would be returned when listing messages.
+	Deserialized *deserializedPayload `json:"deserialized"`
+}
+
+// PayloadFraming is the detected wire format framing of a payload.
+type PayloadFraming struct {
+	// HasMagicByte is true if the payload starts with the magic byte 0 followed by a 4 byte schema id, as used by
+	// Confluent's serializers.
+	HasMagicByte bool    `json:"hasMagicByte"`
+	SchemaID     *uint32 `json:"schemaId,omitempty"`
+
+	// ProtobufMessageIndexes are the message indexes that follow the schema id if the payload has been
+	// serialized using Confluent's KafkaProtobufSerializer.
+	ProtobufMessageIndexes []int64 `json:"protobufMessageIndexes,omitempty"`
+
+	// MsgPackType is the MessagePack type that is indicated by the first byte of the payload.
+	MsgPackType string `json:"msgPackType,omitempty"`
+}
+
+// DecoderResult is the outcome of a single decoder in the deserializer chain.
+type DecoderResult struct {
+	Decoder      string               `json:"decoder"`
+	IsSuccessful bool                 `json:"isSuccessful"`
+	Error        string               `json:"error,omitempty"`
+	Result       *deserializedPayload `json:"result,omitempty"`
+}
+
+// InspectRecord runs all decoders against the record's key, value and headers and returns a byte-level breakdown
+// of the record.
+func (d *deserializer) InspectRecord(record *kgo.Record) *RecordInspection {
+	deserialized := d.DeserializeRecord(record)
+
+	inspection := &RecordInspection{
+		TopicName:       record.Topic,
+		PartitionID:     record.Partition,
+		Offset:          record.Offset,
+		Timestamp:       record.Timestamp.UnixNano() / int64(time.Millisecond),
+		Compression:     compressionTypeDisplayname(record.Attrs.CompressionType()),
+		IsTransactional: record.Attrs.IsTransactional(),
+		Key:             d.inspectPayload(record.Key, record.Topic, proto.RecordKey),
+		Value:           d.inspectPayload(record.Value, record.Topic, proto.RecordValue),
+		Headers:         make([]HeaderInspection, len(record.Headers)),
+	}
+	inspection.Key.Deserialized = deserialized.Key
+	inspection.Value.Deserialized = deserialized.Value
+
+	if record.Topic == "__consumer_offsets" {
+		result := DecoderResult{Decoder: "consumerOffsets"}
+		rec, err := d.deserializeConsumerOffset(record)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.IsSuccessful = true
+			result.Result = rec.Value
+		}
+		inspection.Value.DecoderResults = append([]DecoderResult{result}, inspection.Value.DecoderResults...)
+	}
+
+	for i, header := range record.Headers {
+		headerInspection := HeaderInspection{
+			Key:   header.Key,
+			Value: d.inspectPayload(header.Value, record.Topic, proto.RecordValue),
+		}
+		headerInspection.Value.Deserialized = d.deserializeHeader(header, record.Topic)
+
+		for _, rule := range d.HeaderDecodingRules {
+			if !rule.matches(record.Topic, header.Key) {
+				continue
+			}
+			result := DecoderResult{Decoder: fmt.Sprintf("headerRule (%v)", rule.Encoding)}
+			payload, err := d.deserializeHeaderWithRule(header.Value, rule)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.IsSuccessful = true
+				result.Result = payload
+			}
+			headerInspection.Value.DecoderResults = append([]DecoderResult{result}, headerInspection.Value.DecoderResults...)
+			break
+		}
+		inspection.Headers[i] = headerInspection
+	}
+
+	return inspection
+}
+
+func (d *deserializer) inspectPayload(payload []byte, topicName string, recordType proto.RecordPropertyType) PayloadInspection {
+	decoders := d.payloadDecoders()
+	results := make([]DecoderResult, len(decoders))
+	for i, decoder := range decoders {
+		result := DecoderResult{Decoder: decoder.Name}
+		deserialized, err := decoder.Decode(payload, topicName, recordType)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.IsSuccessful = true
+			result.Result = deserialized
+		}
+		results[i] = result
+	}
+
+	return PayloadInspection{
+		IsNull:         payload == nil,
+		Size:           len(payload),
+		HexDump:        hex.Dump(payload),
+		Framing:        detectPayloadFraming(payload),
+		DecoderResults: results,
+	}
+}
+
+func detectPayloadFraming(payload []byte) PayloadFraming {
+	framing := PayloadFraming{}
+	if len(payload) == 0 {
+		return framing
+	}
+	framing.MsgPackType = msgPackTypeName(payload[0])
+
+	if len(payload) < 5 || payload[0] != byte(0) {
+		return framing
+	}
+	framing.HasMagicByte = true
+	schemaID := binary.BigEndian.Uint32(payload[1:5])
+	framing.SchemaID = &schemaID
+
+	envelope, err := proto.DecodeConfluentBinaryWrapper(payload)
+	if err == nil {
+		framing.ProtobufMessageIndexes = envelope.IndexArray
+	}
+
+	return framing
+}
+
+// msgPackTypeName returns the MessagePack type which is indicated by the given first byte.
+// See: https://github.com/msgpack/msgpack/blob/master/spec.md#formats
+func msgPackTypeName(b byte) string {
+	switch {
+	case msgpcode.IsFixedNum(b):
+		return "fixint"
+	case msgpcode.IsFixedMap(b):
+		return "fixmap"
+	case msgpcode.IsFixedArray(b):
+		return "fixarray"
+	case msgpcode.IsFixedString(b):
+		return "fixstr"
+	}
+
+	switch b {
+	case msgpcode.Nil:
+		return "nil"
+	case msgpcode.False, msgpcode.True:
+		return "bool"
+	case msgpcode.Float:
+		return "float32"
+	case msgpcode.Double:
+		return "float64"
+	case msgpcode.Uint8, msgpcode.Uint16, msgpcode.Uint32, msgpcode.Uint64:
+		return "uint"
+	case msgpcode.Int8, msgpcode.Int16, msgpcode.Int32, msgpcode.Int64:
+		return "int"
+	case msgpcode.Str8, msgpcode.Str16, msgpcode.Str32:
+		return "str"
+	case msgpcode.Bin8, msgpcode.Bin16, msgpcode.Bin32:
+		return "bin"
+	case msgpcode.Array16, msgpcode.Array32:
+		return "array"
+	case msgpcode.Map16, msgpcode.Map32:
+		return "map"
+	case msgpcode.FixExt1, msgpcode.FixExt2, msgpcode.FixExt4, msgpcode.FixExt8, msgpcode.FixExt16,
+		msgpcode.Ext8, msgpcode.Ext16, msgpcode.Ext32:
+		return "ext"
+	default:
+		return "unused"
+	}
+}
@@ -0,0 +1,369 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cloudhut/kowl/backend/pkg/regexutil"
+)
+
+const defaultRedactionReplacement = "<redacted>"
+
+// redactionRule is a RedactionRule along with its compiled topic name expressions, paths and patterns.
+type redactionRule struct {
+	topicNamesExpr []*regexp.Regexp
+	paths          [][]redactionPathSegment
+	patterns       []*regexp.Regexp
+	replacement    string
+}
+
+// redactionPathSegment is a single step of a parsed path. A segment either selects an object key, an array index
+// or all children (wildcard). Recursive segments are applied at any depth (JSONPath's '..' operator).
+type redactionPathSegment struct {
+	Key       string
+	Index     int
+	Wildcard  bool
+	Recursive bool
+}
+
+func compileRedactionRules(rules []RedactionRule) ([]redactionRule, error) {
+	compiledRules := make([]redactionRule, len(rules))
+	for i, rule := range rules {
+		compiled := redactionRule{
+			topicNamesExpr: make([]*regexp.Regexp, len(rule.TopicNames)),
+			paths:          make([][]redactionPathSegment, len(rule.Paths)),
+			patterns:       make([]*regexp.Regexp, len(rule.Patterns)),
+			replacement:    rule.Replacement,
+		}
+		if compiled.replacement == "" {
+			compiled.replacement = defaultRedactionReplacement
+		}
+
+		for j, topicName := range rule.TopicNames {
+			expr, err := regexutil.Compile(topicName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile topic name expression '%v': %w", topicName, err)
+			}
+			compiled.topicNamesExpr[j] = expr
+		}
+		for j, path := range rule.Paths {
+			segments, err := parseRedactionPath(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse path '%v': %w", path, err)
+			}
+			compiled.paths[j] = segments
+		}
+		for j, pattern := range rule.Patterns {
+			expr, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile pattern '%v': %w", pattern, err)
+			}
+			compiled.patterns[j] = expr
+		}
+		compiledRules[i] = compiled
+	}
+
+	return compiledRules, nil
+}
+
+// parseRedactionPath parses a subset of JSONPath: '$', '.key', '..key', '.*', '[*]', '[0]' and "['key']".
+// Paths that do not start with '$' are considered proto field paths such as "customer.email_address".
+func parseRedactionPath(path string) ([]redactionPathSegment, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("path must not be empty")
+	}
+	if strings.HasPrefix(path, "$") {
+		path = path[1:]
+	} else {
+		path = "." + path
+	}
+
+	segments := make([]redactionPathSegment, 0)
+	for len(path) > 0 {
+		segment := redactionPathSegment{Index: -1}
+		switch {
+		case strings.HasPrefix(path, ".."):
+			segment.Recursive = true
+			path = path[2:]
+		case path[0] == '.':
+			path = path[1:]
+		case path[0] == '[':
+			end := strings.IndexByte(path, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("missing closing bracket")
+			}
+			inner := path[1:end]
+			path = path[end+1:]
+			switch {
+			case inner == "*":
+				segment.Wildcard = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segment.Key = inner[1 : len(inner)-1]
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid array index '%v'", inner)
+				}
+				segment.Index = index
+			}
+			segments = append(segments, segment)
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected character '%c'", path[0])
+		}
+
+		// Parse key or wildcard after a dot
+		end := strings.IndexAny(path, ".[")
+		if end == -1 {
+			end = len(path)
+		}
+		key := path[:end]
+		path = path[end:]
+		if key == "" {
+			return nil, fmt.Errorf("missing key after dot")
+		}
+		if key == "*" {
+			segment.Wildcard = true
+		} else {
+			segment.Key = key
+		}
+		segments = append(segments, segment)
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("path must select at least one field")
+	}
+
+	return segments, nil
+}
+
+// matchesTopic returns true if the rule shall be applied for records in the given topic.
+func (r *redactionRule) matchesTopic(topicName string) bool {
+	if len(r.topicNamesExpr) == 0 {
+		return true
+	}
+	for _, expr := range r.topicNamesExpr {
+		if expr.MatchString(topicName) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRedactionRules returns true if at least one redaction rule applies to the given topic.
+func (s *Service) HasRedactionRules(topicName string) bool {
+	return len(s.redactionRulesForTopic(topicName)) > 0
+}
+
+func (s *Service) redactionRulesForTopic(topicName string) []redactionRule {
+	rules := make([]redactionRule, 0)
+	for _, rule := range s.redactionRules {
+		if rule.matchesTopic(topicName) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// redactRecord applies all redaction rules of the given topic to the deserialized key, value and headers.
+// This must be called before the record is passed to the JavaScript filter so that filters can not be used
+// to probe the redacted values.
+func (s *Service) redactRecord(topicName string, record *deserializedRecord) {
+	rules := s.redactionRulesForTopic(topicName)
+	if len(rules) == 0 {
+		return
+	}
+
+	redactPayload(record.Key, rules)
+	redactPayload(record.Value, rules)
+	for _, header := range record.Headers {
+		redactPayload(header, rules)
+	}
+}
+
+// redactPayload redacts the parsed Object and the normalized payload. Binary payloads can not be redacted.
+func redactPayload(payload *deserializedPayload, rules []redactionRule) {
+	if payload == nil {
+		return
+	}
+
+	switch payload.Payload.RecognizedEncoding {
+	case messageEncodingNone, messageEncodingBinary, messageEncodingConsumerOffsets:
+		return
+	case messageEncodingText:
+		text := string(payload.Payload.Payload)
+		for _, rule := range rules {
+			text = rule.redactText(text)
+		}
+		payload.Payload.Payload = []byte(text)
+		payload.Object = text
+		return
+	}
+
+	// All other encodings are normalized to JSON. Numbers are kept as json.Number so that large integers
+	// do not lose precision in the redacted payload by being converted to float64.
+	var obj interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload.Payload.Payload))
+	decoder.UseNumber()
+	err := decoder.Decode(&obj)
+	if err != nil {
+		// We rather hide the whole payload than risk leaking sensitive data
+		redacted := rules[0].replacement
+		payload.Payload = normalizedPayload{Payload: []byte(redacted), RecognizedEncoding: messageEncodingText}
+		payload.Object = redacted
+		return
+	}
+
+	for _, rule := range rules {
+		obj = rule.redactObject(obj)
+	}
+	redacted, err := json.Marshal(obj)
+	if err != nil {
+		redacted, _ = json.Marshal(rules[0].replacement)
+	}
+	payload.Payload.Payload = redacted
+
+	// The object is passed to the JavaScript filters, which expect numbers (float64) rather than json.Number.
+	// Hence the object is decoded again, the same way the deserializer decodes unredacted JSON payloads.
+	var object interface{}
+	err = json.Unmarshal(redacted, &object)
+	if err != nil {
+		object = rules[0].replacement
+	}
+	payload.Object = object
+}
+
+func (r *redactionRule) redactText(text string) string {
+	for _, pattern := range r.patterns {
+		text = pattern.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
+
+func (r *redactionRule) redactObject(obj interface{}) interface{} {
+	for _, path := range r.paths {
+		obj = redactPath(obj, path, r.replacement)
+	}
+	if len(r.patterns) > 0 {
+		obj = r.redactStrings(obj)
+	}
+	return obj
+}
+
+// redactStrings applies all patterns to every string value within the given object.
+func (r *redactionRule) redactStrings(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case string:
+		return r.redactText(o)
+	case map[string]interface{}:
+		for key, value := range o {
+			o[key] = r.redactStrings(value)
+		}
+	case []interface{}:
+		for i, value := range o {
+			o[i] = r.redactStrings(value)
+		}
+	}
+	return obj
+}
+
+// redactPath replaces all values that are selected by the given path segments.
+func redactPath(node interface{}, segments []redactionPathSegment, replacement string) interface{} {
+	if len(segments) == 0 {
+		return replacement
+	}
+	segment := segments[0]
+
+	if segment.Recursive {
+		// Apply the segment at the current level and descend into all children with the recursive segment
+		nonRecursive := segment
+		nonRecursive.Recursive = false
+		node = redactPath(node, append([]redactionPathSegment{nonRecursive}, segments[1:]...), replacement)
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for key, value := range n {
+				n[key] = redactPath(value, segments, replacement)
+			}
+		case []interface{}:
+			for i, value := range n {
+				n[i] = redactPath(value, segments, replacement)
+			}
+		}
+		return node
+	}
+
+	remaining := segments[1:]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if segment.Wildcard {
+			for key, value := range n {
+				n[key] = redactPath(value, remaining, replacement)
+			}
+			return n
+		}
+		if segment.Key == "" {
+			return n
+		}
+		keys := []string{segment.Key}
+		if jsonName := protoJSONName(segment.Key); jsonName != segment.Key {
+			keys = append(keys, jsonName)
+		}
+		for _, key := range keys {
+			if value, exists := n[key]; exists {
+				n[key] = redactPath(value, remaining, replacement)
+			}
+		}
+	case []interface{}:
+		switch {
+		case segment.Wildcard:
+			for i, value := range n {
+				n[i] = redactPath(value, remaining, replacement)
+			}
+		case segment.Index >= 0:
+			if segment.Index < len(n) {
+				n[segment.Index] = redactPath(n[segment.Index], remaining, replacement)
+			}
+		default:
+			// Keys are applied to all array items, so that proto field paths work for repeated fields too
+			for i, value := range n {
+				n[i] = redactPath(value, segments, replacement)
+			}
+		}
+	}
+
+	return node
+}
+
+// protoJSONName returns the lowerCamelCase JSON name of a proto field name (e.g. "email_address" => "emailAddress"),
+// which is used when proto messages are converted to JSON.
+func protoJSONName(fieldName string) string {
+	var sb strings.Builder
+	upperNext := false
+	for _, r := range fieldName {
+		if r == '_' {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
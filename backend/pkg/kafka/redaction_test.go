@@ -0,0 +1,119 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRedactionPath(t *testing.T) {
+	segments, err := parseRedactionPath("$.cards[*].number")
+	require.NoError(t, err)
+	assert.Equal(t, []redactionPathSegment{
+		{Key: "cards", Index: -1},
+		{Index: -1, Wildcard: true},
+		{Key: "number", Index: -1},
+	}, segments)
+
+	segments, err = parseRedactionPath("customer.email_address")
+	require.NoError(t, err)
+	assert.Equal(t, []redactionPathSegment{
+		{Key: "customer", Index: -1},
+		{Key: "email_address", Index: -1},
+	}, segments)
+
+	for _, invalid := range []string{"", "$", "$.", "$.a[", "$.a[-1]", "$a"} {
+		_, err = parseRedactionPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRedactPayload(t *testing.T) {
+	rules, err := compileRedactionRules([]RedactionRule{
+		{
+			TopicNames: []string{"/customers-.*/"},
+			Paths:      []string{"$.cards[*].number", "$..password", "customer.email_address"},
+			Patterns:   []string{`\d{3}-\d{4}`},
+		},
+	})
+	require.NoError(t, err)
+	svc := &Service{redactionRules: rules}
+	assert.True(t, svc.HasRedactionRules("customers-eu"))
+	assert.False(t, svc.HasRedactionRules("orders"))
+
+	payload := &deserializedPayload{
+		Payload: normalizedPayload{
+			Payload: []byte(`{"customer":{"emailAddress":"a@b.c","phone":"call 555-1234"},` +
+				`"cards":[{"number":"4111"},{"number":"5500"}],"nested":{"password":"secret"}}`),
+			RecognizedEncoding: messageEncodingProtobuf,
+		},
+		RecognizedEncoding: messageEncodingProtobuf,
+	}
+	redactPayload(payload, rules)
+
+	expected := `{"cards":[{"number":"<redacted>"},{"number":"<redacted>"}],` +
+		`"customer":{"emailAddress":"<redacted>","phone":"call <redacted>"},"nested":{"password":"<redacted>"}}`
+	assert.JSONEq(t, expected, string(payload.Payload.Payload))
+	assert.Equal(t, "<redacted>", payload.Object.(map[string]interface{})["nested"].(map[string]interface{})["password"])
+
+	text := &deserializedPayload{
+		Payload:            normalizedPayload{Payload: []byte("phone: 555-1234"), RecognizedEncoding: messageEncodingText},
+		RecognizedEncoding: messageEncodingText,
+	}
+	redactPayload(text, rules)
+	assert.Equal(t, "phone: <redacted>", text.Object)
+	assert.Equal(t, "phone: <redacted>", string(text.Payload.Payload))
+
+	numbers := &deserializedPayload{
+		Payload: normalizedPayload{
+			Payload:            []byte(`{"id":9007199254740993,"amount":12.50,"password":"secret"}`),
+			RecognizedEncoding: messageEncodingJSON,
+		},
+		RecognizedEncoding: messageEncodingJSON,
+	}
+	redactPayload(numbers, rules)
+	assert.JSONEq(t, `{"amount":12.50,"id":9007199254740993,"password":"<redacted>"}`, string(numbers.Payload.Payload))
+	assert.Contains(t, string(numbers.Payload.Payload), `"id":9007199254740993`)
+	assert.Contains(t, string(numbers.Payload.Payload), `"amount":12.50`)
+}
+
+func TestRedactPayloadKeepsNumbersForFilters(t *testing.T) {
+	rules, err := compileRedactionRules([]RedactionRule{{TopicNames: []string{"orders"}, Paths: []string{"$.password"}}})
+	require.NoError(t, err)
+	svc := &Service{redactionRules: rules}
+
+	tt := []struct {
+		filterCode string
+		want       bool
+	}{
+		{"return value.n > 3", true},
+		{"return value.n === 5", true},
+		{"return value.n + 1 === 6", true},
+		{"return typeof value.n === 'number'", true},
+		{"return value.n < 3", false},
+	}
+
+	for _, test := range tt {
+		payload := &deserializedPayload{
+			Payload:            normalizedPayload{Payload: []byte(`{"n":5,"password":"secret"}`), RecognizedEncoding: messageEncodingJSON},
+			RecognizedEncoding: messageEncodingJSON,
+		}
+		redactPayload(payload, rules)
+
+		isMessageOk, err := svc.setupInterpreter(test.filterCode)
+		require.NoError(t, err)
+		isOk, err := isMessageOk(interpreterArguments{Value: payload.Object})
+		require.NoError(t, err, test.filterCode)
+		assert.Equal(t, test.want, isOk, test.filterCode)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// DescribeUserSCRAMCredentials describes the SCRAM credentials (mechanism and iterations) of the given users.
+// If no users are set in the request, the credentials of all users are returned. Requires Kafka v2.7+.
+func (s *Service) DescribeUserSCRAMCredentials(ctx context.Context, req kmsg.DescribeUserSCRAMCredentialsRequest) (*kmsg.DescribeUserSCRAMCredentialsResponse, error) {
+	return req.RequestWith(ctx, s.KafkaClient)
+}
+
+// AlterUserSCRAMCredentials upserts and deletes SCRAM credentials. Passwords must be salted client side.
+// Requires Kafka v2.7+.
+func (s *Service) AlterUserSCRAMCredentials(ctx context.Context, req kmsg.AlterUserSCRAMCredentialsRequest) (*kmsg.AlterUserSCRAMCredentialsResponse, error) {
+	return req.RequestWith(ctx, s.KafkaClient)
+}
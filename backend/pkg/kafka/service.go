@@ -37,6 +37,9 @@ type Service struct {
 	ProtoService     *proto.Service
 	Deserializer     deserializer
 	MetricsNamespace string
+
+	// redactionRules are applied to all deserialized records unless redaction is bypassed
+	redactionRules []redactionRule
 }
 
 // NewService creates a new Kafka service and immediately checks connectivity to all components. If any of these external
@@ -112,6 +115,16 @@ func NewService(cfg Config, logger *zap.Logger, metricsNamespace string) (*Servi
 		}
 	}
 
+	headerDecodingRules, err := compileHeaderDecodingRules(cfg.HeaderDecoding.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile header decoding rules: %w", err)
+	}
+
+	redactionRules, err := compileRedactionRules(cfg.Redaction.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile redaction rules: %w", err)
+	}
+
 	return &Service{
 		Config:           cfg,
 		Logger:           logger,
@@ -123,8 +136,11 @@ func NewService(cfg Config, logger *zap.Logger, metricsNamespace string) (*Servi
 			SchemaService:  schemaSvc,
 			ProtoService:   protoSvc,
 			MsgPackService: msgPackSvc,
+
+			HeaderDecodingRules: headerDecodingRules,
 		},
 		MetricsNamespace: metricsNamespace,
+		redactionRules:   redactionRules,
 	}, nil
 }
 
@@ -144,7 +144,7 @@ func (s *Service) Start() error {
 func (s *Service) unmarshalConfluentMessage(payload []byte, topicName string) ([]byte, int, error) {
 	// 1. If schema registry for protobuf is enabled, let's check if this message has been serialized utilizing
 	// Confluent's KafakProtobuf serialization format.
-	wrapper, err := s.decodeConfluentBinaryWrapper(payload)
+	wrapper, err := DecodeConfluentBinaryWrapper(payload)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to decode confluent wrapper from payload: %w", err)
 	}
@@ -205,7 +205,7 @@ func (s *Service) UnmarshalPayload(payload []byte, topicName string, property Re
 // according to Confluent's ProtobufSerializer. If successful it will return the found message descriptor along with
 // the protobuf payload (without the bytes that carry the metadata such as schema id), so that this can be used
 // for deserializing the content.
-func (s *Service) getMessageDescriptorFromConfluentMessage(wrapper *confluentEnvelope, topicName string) (*desc.MessageDescriptor, []byte, error) {
+func (s *Service) getMessageDescriptorFromConfluentMessage(wrapper *ConfluentEnvelope, topicName string) (*desc.MessageDescriptor, []byte, error) {
 	fd, exists := s.getFileDescriptorBySchemaID(int(wrapper.SchemaID))
 	if !exists {
 		return nil, nil, fmt.Errorf("could not find a file descriptor that matches the decoded schema id '%v'", wrapper.SchemaID)
@@ -252,6 +252,11 @@ func (s *Service) getMessageDescriptor(topicName string, property RecordProperty
 		protoTypeUrl = mapping.ValueProtoType
 	}
 
+	return s.getMessageDescriptorByType(protoTypeUrl)
+}
+
+// getMessageDescriptorByType looks up the message descriptor for the given fully qualified proto type in the registry.
+func (s *Service) getMessageDescriptorByType(protoTypeUrl string) (*desc.MessageDescriptor, error) {
 	s.registryMutex.RLock()
 	defer s.registryMutex.RUnlock()
 	messageDescriptor, err := s.registry.FindMessageTypeByUrl(protoTypeUrl)
@@ -267,13 +272,26 @@ func (s *Service) getMessageDescriptor(topicName string, property RecordProperty
 	return messageDescriptor, nil
 }
 
-type confluentEnvelope struct {
+// UnmarshalPayloadWithType deserializes the given payload using the given fully qualified proto type rather than
+// looking up the type via the topic mappings. It returns the JSON representation of the proto message.
+func (s *Service) UnmarshalPayloadWithType(payload []byte, protoType string) ([]byte, error) {
+	messageDescriptor, err := s.getMessageDescriptorByType(protoType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message descriptor for payload: %w", err)
+	}
+
+	return s.deserializeProtobufMessageToJSON(payload, messageDescriptor)
+}
+
+// ConfluentEnvelope is the decoded framing of a message that has been serialized using Confluent's
+// KafkaProtobufSerializer.
+type ConfluentEnvelope struct {
 	SchemaID     uint32
 	IndexArray   []int64
 	ProtoPayload []byte
 }
 
-// decodeConfluentBinaryWrapper decodes the serialized message that contains metadata in order for the client to
+// DecodeConfluentBinaryWrapper decodes the serialized message that contains metadata in order for the client to
 // know what information it has to fetch from the schema registry to deserialize the Protobuf message.
 //
 // Binary format:
@@ -285,7 +303,7 @@ type confluentEnvelope struct {
 //		where the message type is the first message in the schema (i.e. index data would be [1,0]) is encoded as
 //		a single 0 byte as an optimization.
 // Bytes n+1-end: Protobuf serialized payload.
-func (s *Service) decodeConfluentBinaryWrapper(payload []byte) (*confluentEnvelope, error) {
+func DecodeConfluentBinaryWrapper(payload []byte) (*ConfluentEnvelope, error) {
 	buf := bytes.NewReader(payload)
 	magicByte, err := buf.ReadByte()
 	if magicByte != byte(0) {
@@ -302,6 +320,9 @@ func (s *Service) decodeConfluentBinaryWrapper(payload []byte) (*confluentEnvelo
 	if err != nil {
 		return nil, fmt.Errorf("failed to read arrLength: %w", err)
 	}
+	if arrLength < 0 || arrLength > int64(buf.Len()) {
+		return nil, fmt.Errorf("invalid message index array length '%d'", arrLength)
+	}
 
 	msgTypeIDs := make([]int64, arrLength)
 	// If there is just one msgtype (default index - 0) the array won't be sent at all
@@ -323,7 +344,7 @@ func (s *Service) decodeConfluentBinaryWrapper(payload []byte) (*confluentEnvelo
 		return nil, fmt.Errorf("failed to read remaining payload: %w", err)
 	}
 
-	return &confluentEnvelope{
+	return &ConfluentEnvelope{
 		SchemaID:     schemaID,
 		IndexArray:   msgTypeIDs,
 		ProtoPayload: remainingPayload,
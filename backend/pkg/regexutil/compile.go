@@ -0,0 +1,26 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package regexutil provides helpers for the topic name expressions that are used across the configuration.
+package regexutil
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Compile compiles the given expression. Expressions which are wrapped in slashes (e.g. "/prefix-.*/") are
+// treated as regex, all other expressions are treated as literal that must match the whole input.
+func Compile(expr string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(expr, "/") && strings.HasSuffix(expr, "/") && len(expr) > 1 {
+		return regexp.Compile(expr[1 : len(expr)-1])
+	}
+
+	return regexp.Compile("^" + regexp.QuoteMeta(expr) + "$")
+}
@@ -253,11 +253,29 @@ export interface GroupMemberAssignment {
     topicName: string;
     partitionIds: number[];
 }
+export interface ConnectTaskAssignment {
+    connector: string;
+    taskId: number;
+}
+export interface GroupMemberConnectAssignment {
+    workerUrl: string;
+    leaderId: string;
+    leaderUrl: string;
+    configOffset: number;
+    error?: string;
+    connectors: string[]; // connector instances that run on this worker
+    tasks: ConnectTaskAssignment[]; // connector tasks that run on this worker
+    revokedConnectors?: string[];
+    revokedTasks?: ConnectTaskAssignment[];
+}
 export interface GroupMemberDescription {
     id: string; // unique ID assigned to the member after login
     clientId: string; // custom id reported by the member
     clientHost: string; // address/host of the connection
     assignments: GroupMemberAssignment[]; // topics+partitions that the worker is assigned to
+    connectAssignment?: GroupMemberConnectAssignment; // only set for Kafka Connect workers (protocolType "connect")
+    decodedMetadata?: any; // only set for other protocol types that use JSON (e.g. "sr" for schema registry)
+    decodedAssignment?: any;
 
     // added by frontend:
     hasMissingPartitionIds: boolean;
@@ -272,7 +290,7 @@ export interface GroupDescription {
     groupId: string; // name of the group
     state: string; // Dead, Initializing, Rebalancing, Stable
     protocol: string;
-    protocolType: string; // Will be "consumer" if we can decode the members; otherwise ".members" will be empty, which happens for "sr" (for schema registry) for example
+    protocolType: string; // "consumer" members have assignments, "connect" members have a connectAssignment, JSON based protocols (e.g. "sr" for schema registry) have decodedMetadata/decodedAssignment
     members: GroupMemberDescription[]; // members (consumers) that are currently present in the group
     coordinatorId: number;
     topicOffsets: GroupTopicOffsets[];
@@ -791,11 +809,23 @@ export interface PartitionReassignmentsPartition {
     addingReplicas: number[];
     removingReplicas: number[];
     replicas: number[];
+
+    isThrottled: boolean;
+    size: number;
+    bytesCopied: number;
+    bytesTotal: number;
+    addingProgress: PartitionReassignmentReplica[];
+}
+export interface PartitionReassignmentReplica {
+    brokerId: number;
+    size: number;
+    error?: string;
 }
 
 // PartitionReassignments - Patch
 export interface PartitionReassignmentRequest {
     topics: TopicAssignment[];
+    throttleRate?: number; // bytes per second, removed automatically once the reassignment is completed
 }
 export type TopicAssignment = {
     topicName: string; // name of topic to change
@@ -817,6 +847,16 @@ export interface AlterPartitionReassignmentsResponse {
         topicName: string;
         partitions: AlterPartitionReassignmentsPartitionResponse[];
     }[];
+    throttle?: ReassignmentThrottle;
+}
+export interface ReassignmentThrottle {
+    rate: number;
+    brokerIds: number[];
+    topics: {
+        topicName: string;
+        leaderReplicas: string[];
+        followerReplicas: string[];
+    }[];
 }
 export interface AlterPartitionReassignmentsPartitionResponse {
     partitionId: number;
@@ -1241,9 +1281,17 @@ export type TopicConfigEntry = WellKnownTopicConfigEntries | { name: string; val
 
 export interface CreateTopicRequest {
     topicName: string;
-    partitionCount: number; // -1 for default
-    replicationFactor: number; // -1 for default
+    partitionCount: number; // -1 for default, 0 to use the template's value
+    replicationFactor: number; // -1 for default, 0 to use the template's value
     configs: TopicConfigEntry[];
+    template?: string; // name of a configured topic template
+}
+
+export interface TopicTemplate {
+    name: string;
+    partitionCount: number;
+    replicationFactor: number;
+    configs: { [name: string]: string } | null;
 }
 
 export interface CreateTopicResponse {